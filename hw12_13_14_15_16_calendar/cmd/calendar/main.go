@@ -9,10 +9,12 @@ import (
 
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/app"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/config"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/health"
 	i "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/interface"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/logger"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/service/calendar"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/tracing"
 )
 
 var (
@@ -29,13 +31,23 @@ func init() {
 // @version 1.0
 // @description This is a server for Calendar
 // @BasePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 // .
 func main() {
 	flag.Parse()
 
-	if flag.Arg(0) == "version" {
+	switch flag.Arg(0) {
+	case "version":
 		printVersion()
 		return
+	case "token":
+		if flag.NArg() < 2 {
+			log.Fatalf("Usage: calendar [-config path] token <userID> [role...]")
+		}
+		issueToken(configFile, flag.Arg(1), flag.Args()[2:])
+		return
 	}
 
 	run(configFile, migrate)
@@ -49,6 +61,22 @@ func run(configPath string, migrate bool) {
 
 	logg := logger.New(cfg.Log.Level)
 
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Config{
+		ServiceName: "calendar",
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		logg.Fatalf("Failed to set up tracing: %v", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logg.Errorf("Failed to flush traces: %v", err)
+		}
+	}()
+
 	var storageApp i.Storage
 	storageApp, err = storage.InitStorage(storage.Config{
 		Type:           cfg.Database.Type,
@@ -56,13 +84,18 @@ func run(configPath string, migrate bool) {
 		MigrationsPath: cfg.Database.MigrationsPath,
 		Timeout:        cfg.Database.Timeout,
 		Migration:      cfg.Database.Migrate || migrate,
+		DataDir:        cfg.Database.DataDir,
+		SnapshotEvery:  cfg.Database.SnapshotEvery,
 	})
 	if err != nil {
 		logg.Fatalf("Failed to initialize storage: %v", err)
 	}
 
 	application := app.NewApp(storageApp, logg)
-	calendarService := calendar.NewCalendar(application, logg, cfg)
+	checker := health.NewChecker(health.DefaultTimeout).Add("storage", func(ctx context.Context) error {
+		return storage.Ping(ctx, storageApp)
+	})
+	calendarService := calendar.NewCalendar(application, logg, cfg, checker)
 
 	ctx, cancel := signal.NotifyContext(context.Background(),
 		syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
@@ -81,4 +114,10 @@ func run(configPath string, migrate bool) {
 	} else {
 		logg.Infof("Calendar service stopped gracefully")
 	}
+
+	if closer, ok := storageApp.(interface{ Close(context.Context) error }); ok {
+		if err = closer.Close(context.Background()); err != nil {
+			logg.Errorf("Failed to close storage: %v", err)
+		}
+	}
 }
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/auth"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/config"
+)
+
+const tokenTTL = 24 * time.Hour
+
+// issueToken prints a bearer token for the user signed with the first configured key.
+// It is meant for local development and tests.
+func issueToken(configPath string, userID string, roles []string) {
+	cfg, err := config.NewCalendarConfig(configPath)
+	if err != nil {
+		log.Fatalf("Config error: %s", err)
+	}
+
+	var key auth.Key
+	switch {
+	case cfg.Auth.Secret != "":
+		key = auth.Key{Secret: cfg.Auth.Secret}
+	case len(cfg.Auth.Keys) > 0:
+		key = auth.Key{ID: cfg.Auth.Keys[0].ID, Secret: cfg.Auth.Keys[0].Secret}
+	default:
+		log.Fatalf("No signing keys configured")
+	}
+
+	claims := auth.NewClaims(userID, roles, tokenTTL)
+	claims.Issuer = cfg.Auth.Issuer
+	if cfg.Auth.Audience != "" {
+		claims.Audience = []string{cfg.Auth.Audience}
+	}
+
+	token, err := auth.Sign(claims, key)
+	if err != nil {
+		log.Fatalf("Failed to sign token: %s", err)
+	}
+	fmt.Println(token)
+}
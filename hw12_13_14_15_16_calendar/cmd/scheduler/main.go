@@ -10,11 +10,14 @@ import (
 
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/app"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/config"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/health"
 	i "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/interface"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/leader"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/logger"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/rmq"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/service/scheduler"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/tracing"
 )
 
 var configFile string
@@ -38,19 +41,50 @@ func run(configPath string) {
 	logg := logger.New(cfg.Log.Level)
 	logg.Debugf("Scheduler Config: %v", *cfg)
 
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Config{
+		ServiceName: "scheduler",
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		logg.Fatalf("Failed to set up tracing: %v", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logg.Errorf("Failed to flush traces: %v", err)
+		}
+	}()
+
 	amqpURL := fmt.Sprintf("amqp://%s:%s@%s:%s/",
 		cfg.RabbitMQ.User, cfg.RabbitMQ.Password,
 		cfg.RabbitMQ.Host, cfg.RabbitMQ.Port,
 	)
 	logg.Debugf("AMQP URL: %s", amqpURL)
 
-	rmqClient, err := rmq.NewClient(amqpURL, cfg.RabbitMQ.Exchange)
+	rmqClient, err := rmq.NewClient(rmq.Config{
+		URL:      amqpURL,
+		Exchange: cfg.RabbitMQ.Exchange,
+		Prefetch: cfg.RabbitMQ.Prefetch,
+		Retry: rmq.RetryPolicy{
+			MaxRetries:      cfg.RabbitMQ.Retry.MaxRetries,
+			InitialInterval: cfg.RabbitMQ.Retry.InitialInterval,
+			MaxInterval:     cfg.RabbitMQ.Retry.MaxInterval,
+			Multiplier:      cfg.RabbitMQ.Retry.Multiplier,
+		},
+		ReconnectInterval:    cfg.RabbitMQ.ReconnectInterval,
+		MaxReconnectInterval: cfg.RabbitMQ.MaxReconnectInterval,
+		ConfirmTimeout:       cfg.RabbitMQ.ConfirmTimeout,
+		Logger:               logg,
+	})
 	if err != nil {
 		logg.Fatalf("Failed to create RMQ client: %v", err)
 	}
 	defer func() {
-		err = rmqClient.Close()
-		logg.Fatalf("Failed to close RMQ client: %v", err)
+		if err := rmqClient.Close(); err != nil {
+			logg.Errorf("Failed to close RMQ client: %v", err)
+		}
 	}()
 
 	var storageApp i.Storage
@@ -67,12 +101,33 @@ func run(configPath string) {
 	}
 
 	application := app.NewApp(storageApp, logg)
-	schedulerService := scheduler.NewScheduler(application, rmqClient, logg, cfg)
+	leaderLock, shared := storage.NewLeaderLock(storageApp, cfg.Scheduler.LeaderElection.LockKey)
+	if !shared {
+		logg.Warnf("Leader lock of %s storage is local to this process: run a single scheduler, "+
+			"other processes on the same data become leaders too and publish every notification again",
+			cfg.Database.Type)
+	}
+	elector := leader.NewElector(leaderLock, cfg.Scheduler.LeaderElection.Interval, logg)
+	schedulerService := scheduler.NewScheduler(application, rmqClient, logg, cfg, elector)
 
 	ctx, cancel := signal.NotifyContext(context.Background(),
 		syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 	defer cancel()
 
+	electorDone := make(chan struct{})
+	go func() {
+		defer close(electorDone)
+		_ = elector.Run(ctx)
+	}()
+	defer func() { <-electorDone }()
+
+	if cfg.Metrics.Addr != "" {
+		checker := health.NewChecker(health.DefaultTimeout).
+			Add("storage", func(ctx context.Context) error { return storage.Ping(ctx, storageApp) }).
+			Add("rabbitmq", health.RabbitMQ(rmqClient))
+		go health.Serve(ctx, cfg.Metrics.Addr, checker, logg)
+	}
+
 	go func() {
 		<-ctx.Done()
 		logg.Infof("Shutting down scheduler...")
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/config"
+)
+
+// deadLetters lists the notifications that exhausted their retries or moves them back to the queue.
+// Without a limit it handles the whole dead-letter queue.
+func deadLetters(configPath, command, limitArg string) {
+	cfg, err := config.NewSenderConfig(configPath)
+	if err != nil {
+		log.Fatalf("Sender config error: %s", err)
+	}
+
+	limit := 0
+	if limitArg != "" {
+		limit, err = strconv.Atoi(limitArg)
+		if err != nil || limit <= 0 {
+			log.Fatalf("Invalid limit %q", limitArg)
+		}
+	}
+
+	rmqClient, err := newRmqClient(cfg.RabbitMQ, nil)
+	if err != nil {
+		log.Fatalf("Failed to create RMQ client: %v", err)
+	}
+	defer rmqClient.Close()
+
+	switch command {
+	case "list":
+		letters, err := rmqClient.DeadLetters(cfg.QueueName, limit)
+		if err != nil {
+			log.Fatalf("Failed to read dead letters: %v", err)
+		}
+		for _, letter := range letters {
+			fmt.Printf("%s\tattempts=%d\tkey=%s\treason=%q\n%s\n",
+				letter.DeadAt.Format(time.RFC3339), letter.Attempts, letter.RoutingKey, letter.Reason, letter.Body)
+		}
+		fmt.Printf("%d dead letters\n", len(letters))
+	case "replay":
+		replayed, err := rmqClient.Replay(cfg.QueueName, limit)
+		if err != nil {
+			log.Fatalf("Replayed %d dead letters, then failed: %v", replayed, err)
+		}
+		fmt.Printf("Replayed %d dead letters\n", replayed)
+	default:
+		log.Fatalf("Unknown dlq command %q, expected list or replay", command)
+	}
+}
@@ -9,9 +9,11 @@ import (
 	"syscall"
 
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/config"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/health"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/logger"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/rmq"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/service/sender"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/tracing"
 )
 
 var configFile string
@@ -23,6 +25,14 @@ func init() {
 func main() {
 	flag.Parse()
 
+	if flag.Arg(0) == "dlq" {
+		if flag.NArg() < 2 || flag.NArg() > 3 {
+			log.Fatalf("Usage: sender [-config path] dlq list|replay [limit]")
+		}
+		deadLetters(configFile, flag.Arg(1), flag.Arg(2))
+		return
+	}
+
 	run(configFile)
 }
 
@@ -35,22 +45,39 @@ func run(configPath string) {
 	logg := logger.New(cfg.Log.Level)
 	logg.Debugf("Sender Config: %v", *cfg)
 
-	amqpURL := fmt.Sprintf("amqp://%s:%s@%s:%s/",
-		cfg.RabbitMQ.User, cfg.RabbitMQ.Password,
-		cfg.RabbitMQ.Host, cfg.RabbitMQ.Port,
-	)
-	logg.Debugf("AMQP URL: %s", amqpURL)
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Config{
+		ServiceName: "sender",
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		logg.Fatalf("Failed to set up tracing: %v", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logg.Errorf("Failed to flush traces: %v", err)
+		}
+	}()
 
-	rmqClient, err := rmq.NewClient(amqpURL, cfg.RabbitMQ.Exchange)
+	rmqClient, err := newRmqClient(cfg.RabbitMQ, logg)
 	if err != nil {
 		logg.Fatalf("Failed to create RMQ client: %v", err)
 	}
 	defer func() {
-		err = rmqClient.Close()
-		logg.Fatalf("Failed to close RMQ client: %v", err)
+		if err := rmqClient.Close(); err != nil {
+			logg.Errorf("Failed to close RMQ client: %v", err)
+		}
 	}()
 
-	senderService := sender.NewSender(rmqClient, logg, cfg)
+	notifier, err := sender.NewNotifier(cfg.Notifier)
+	if err != nil {
+		logg.Fatalf("Failed to set up notifier: %v", err)
+	}
+	defer notifier.Close()
+
+	senderService := sender.NewSender(rmqClient, notifier, logg, cfg)
 
 	ctx, cancel := signal.NotifyContext(context.Background(),
 		syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
@@ -62,6 +89,11 @@ func run(configPath string) {
 		cancel()
 	}()
 
+	if cfg.Metrics.Addr != "" {
+		checker := health.NewChecker(health.DefaultTimeout).Add("rabbitmq", health.RabbitMQ(rmqClient))
+		go health.Serve(ctx, cfg.Metrics.Addr, checker, logg)
+	}
+
 	logg.Infof("Starting sender service...")
 	if err = senderService.Run(ctx); err != nil {
 		logg.Errorf("Sender service stopped with error: %v", err)
@@ -70,3 +102,22 @@ func run(configPath string) {
 		logg.Infof("Sender service stopped gracefully")
 	}
 }
+
+func newRmqClient(cfg config.RabbitMQ, logger rmq.Logger) (rmq.Client, error) {
+	amqpURL := fmt.Sprintf("amqp://%s:%s@%s:%s/", cfg.User, cfg.Password, cfg.Host, cfg.Port)
+	return rmq.NewClient(rmq.Config{
+		URL:      amqpURL,
+		Exchange: cfg.Exchange,
+		Prefetch: cfg.Prefetch,
+		Retry: rmq.RetryPolicy{
+			MaxRetries:      cfg.Retry.MaxRetries,
+			InitialInterval: cfg.Retry.InitialInterval,
+			MaxInterval:     cfg.Retry.MaxInterval,
+			Multiplier:      cfg.Retry.Multiplier,
+		},
+		ReconnectInterval:    cfg.ReconnectInterval,
+		MaxReconnectInterval: cfg.MaxReconnectInterval,
+		ConfirmTimeout:       cfg.ConfirmTimeout,
+		Logger:               logger,
+	})
+}
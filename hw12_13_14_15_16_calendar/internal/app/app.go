@@ -2,64 +2,166 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/auth"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/changefeed"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/freebusy"
 	i "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/interface"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/mappers"
+	storagecommon "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/tracing"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/types"
 )
 
 type App struct {
 	Logger  i.Logger
 	Storage i.Storage
+	Changes *changefeed.Feed
 }
 
 func NewApp(storage i.Storage, logger i.Logger) *App {
 	return &App{
 		Storage: storage,
 		Logger:  logger,
+		Changes: changefeed.New(changefeed.DefaultHistorySize),
 	}
 }
 
-func (a *App) CreateEvent(_ context.Context, event types.Event) (string, error) {
+func (a *App) CreateEvent(ctx context.Context, event types.Event) (string, error) {
+	ctx, span := tracing.Start(ctx, "App.CreateEvent")
+	defer span.End()
+
+	if err := auth.CheckAccess(ctx, event.UserID); err != nil {
+		return "", err
+	}
+
 	storEvent := mappers.FromDomainEvent(event)
-	id, err := a.Storage.Create(storEvent)
-	return id, err
+	id, err := a.Storage.Create(ctx, storEvent)
+	if err != nil {
+		return "", err
+	}
+
+	event.ID = id
+	event.Version = storagecommon.InitialVersion
+	a.publish(changefeed.OpCreated, event)
+	return id, nil
 }
 
-func (a *App) UpdateEvent(_ context.Context, event types.Event) error {
+// UpdateEvent replaces the event if event.Version is zero or still current and returns the new version.
+func (a *App) UpdateEvent(ctx context.Context, event types.Event) (int64, error) {
+	ctx, span := tracing.Start(ctx, "App.UpdateEvent")
+	defer span.End()
+
+	previous, err := a.Storage.GetByID(ctx, event.ID)
+	if err != nil {
+		return 0, err
+	}
+	if err := auth.CheckAccess(ctx, previous.UserID); err != nil {
+		return 0, err
+	}
+	if err := auth.CheckAccess(ctx, event.UserID); err != nil {
+		return 0, err
+	}
+
 	storEvent := mappers.FromDomainEvent(event)
-	return a.Storage.Update(storEvent)
+	version, err := a.Storage.Update(ctx, storEvent)
+	if err != nil {
+		return 0, err
+	}
+
+	if previous.UserID != event.UserID {
+		a.publish(changefeed.OpDeleted, mappers.ToDomainEvent(previous))
+	}
+	event.Version = version
+	a.publish(changefeed.OpUpdated, event)
+	return version, nil
+}
+
+// DeleteEvent removes the event if version is zero or still current.
+func (a *App) DeleteEvent(ctx context.Context, id string, version int64) error {
+	ctx, span := tracing.Start(ctx, "App.DeleteEvent")
+	defer span.End()
+
+	previous, err := a.Storage.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := auth.CheckAccess(ctx, previous.UserID); err != nil {
+		return err
+	}
+
+	if err := a.Storage.Delete(ctx, id, version); err != nil {
+		return err
+	}
+
+	a.publish(changefeed.OpDeleted, mappers.ToDomainEvent(previous))
+	return nil
+}
+
+// WatchEvents subscribes to the changes of a user's events made through this process.
+func (a *App) WatchEvents(ctx context.Context, userID, revision string) (*changefeed.Subscription, error) {
+	ctx, span := tracing.Start(ctx, "App.WatchEvents")
+	defer span.End()
+
+	if err := auth.CheckAccess(ctx, userID); err != nil {
+		return nil, err
+	}
+	if a.Changes == nil {
+		return nil, fmt.Errorf("change feed is not configured")
+	}
+	return a.Changes.Subscribe(userID, revision)
 }
 
-func (a *App) DeleteEvent(_ context.Context, id string) error {
-	return a.Storage.Delete(id)
+func (a *App) publish(op changefeed.Op, event types.Event) {
+	if a.Changes == nil {
+		return
+	}
+	change := a.Changes.Publish(op, event)
+	a.Logger.Debugf("Published change: revision=%d op=%s event=%s", change.Revision, change.Op, event.ID)
 }
 
-func (a *App) GetEventByID(_ context.Context, id string) (types.Event, error) {
-	storEvent, err := a.Storage.GetByID(id)
+func (a *App) GetEventByID(ctx context.Context, id string) (types.Event, error) {
+	ctx, span := tracing.Start(ctx, "App.GetEventByID")
+	defer span.End()
+
+	storEvent, err := a.Storage.GetByID(ctx, id)
 	if err != nil {
 		return types.Event{}, err
 	}
+	if err := a.checkEventAccess(ctx, storEvent); err != nil {
+		return types.Event{}, err
+	}
 	return mappers.ToDomainEvent(storEvent), nil
 }
 
-func (a *App) ListEvents(_ context.Context) ([]types.Event, error) {
-	storEvents, err := a.Storage.List()
-	if err != nil {
-		return nil, err
-	}
+// ListEvents lists events of all users for admins and only the caller's own events otherwise.
+func (a *App) ListEvents(ctx context.Context, query types.ListEventsQuery) (types.EventPage, error) {
+	ctx, span := tracing.Start(ctx, "App.ListEvents")
+	defer span.End()
 
-	domainEvents := make([]types.Event, 0, len(storEvents))
-	for _, storEvent := range storEvents {
-		domainEvents = append(domainEvents, mappers.ToDomainEvent(storEvent))
+	if id, ok := auth.FromContext(ctx); ok && !id.IsAdmin() {
+		query.UserID = id.UserID
 	}
 
-	return domainEvents, nil
+	page, err := a.Storage.ListPage(ctx, mappers.FromDomainListQuery(query))
+	if err != nil {
+		return types.EventPage{}, err
+	}
+	return mappers.ToDomainEventPage(page), nil
 }
 
-func (a *App) ListEventsByUser(_ context.Context, userID string) ([]types.Event, error) {
-	storEvents, err := a.Storage.ListByUser(userID)
+func (a *App) ListEventsByUser(ctx context.Context, userID string) ([]types.Event, error) {
+	ctx, span := tracing.Start(ctx, "App.ListEventsByUser")
+	defer span.End()
+
+	if err := auth.CheckAccess(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	storEvents, err := a.Storage.ListByUser(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -73,11 +175,18 @@ func (a *App) ListEventsByUser(_ context.Context, userID string) ([]types.Event,
 }
 
 func (a *App) ListEventsByUserInRange(
-	_ context.Context,
+	ctx context.Context,
 	userID string,
 	from, to time.Time,
 ) ([]types.Event, error) {
-	storEvents, err := a.Storage.ListByUserInRange(userID, from, to)
+	ctx, span := tracing.Start(ctx, "App.ListEventsByUserInRange")
+	defer span.End()
+
+	if err := auth.CheckAccess(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	storEvents, err := a.Storage.ListByUserInRange(ctx, userID, from, to)
 	if err != nil {
 		return nil, err
 	}
@@ -90,26 +199,235 @@ func (a *App) ListEventsByUserInRange(
 	return domainEvents, nil
 }
 
-func (a *App) DeleteOlderThan(_ context.Context, t time.Time) error {
-	return a.Storage.DeleteOlder(t)
+// FreeBusy returns the busy intervals of the users and their common free slots.
+// Only time ranges are disclosed, so any caller may query any user.
+func (a *App) FreeBusy(ctx context.Context, query types.FreeBusyQuery) (types.FreeBusy, error) {
+	ctx, span := tracing.Start(ctx, "App.FreeBusy")
+	defer span.End()
+
+	query, err := freebusy.Validate(query)
+	if err != nil {
+		return types.FreeBusy{}, err
+	}
+
+	busy := make(map[string][]types.Interval, len(query.UserIDs))
+	for _, userID := range query.UserIDs {
+		if _, done := busy[userID]; done {
+			continue
+		}
+
+		events, err := a.Storage.ListByUserInRange(ctx, userID, query.From, query.To)
+		if err != nil {
+			return types.FreeBusy{}, err
+		}
+		intervals := make([]types.Interval, 0, len(events))
+		for _, e := range events {
+			intervals = append(intervals, types.Interval{Start: e.StartTime, End: e.EndTime})
+		}
+		busy[userID] = freebusy.Merge(intervals, query.From, query.To)
+	}
+
+	return types.FreeBusy{Busy: busy, Slots: freebusy.Slots(query, busy)}, nil
 }
 
-func (a *App) ListEventsDueBefore(ctx context.Context, before time.Time) ([]types.Event, error) {
-	allEvents, err := a.ListEvents(ctx)
+func (a *App) DeleteOlderThan(ctx context.Context, t time.Time) error {
+	ctx, span := tracing.Start(ctx, "App.DeleteOlderThan")
+	defer span.End()
+
+	if err := auth.CheckAdmin(ctx); err != nil {
+		return err
+	}
+	return a.Storage.DeleteOlder(ctx, t)
+}
+
+// ScheduleNotifications adds to the outbox a notification for every occurrence that has not started yet
+// and is to be notified about in [from, to), and returns how many were added. A window that starts in the
+// past picks up the notifications missed while the scheduler was down; the ones already in the outbox
+// are skipped.
+func (a *App) ScheduleNotifications(ctx context.Context, from, to time.Time) (int, error) {
+	ctx, span := tracing.Start(ctx, "App.ScheduleNotifications")
+	defer span.End()
+
+	if err := auth.CheckAdmin(ctx); err != nil {
+		return 0, err
+	}
+
+	occurrences, err := a.Storage.ListDueOccurrences(ctx, from, to)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
 	now := time.Now()
-	dueEvents := make([]types.Event, 0)
+	notifications := make([]storagecommon.Notification, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		if occurrence.StartTime.After(now) {
+			notifications = append(notifications, storagecommon.NewNotification(occurrence, now))
+		}
+	}
+
+	if len(notifications) == 0 {
+		return 0, nil
+	}
+	return a.Storage.AddNotifications(ctx, notifications)
+}
+
+// PendingNotifications returns up to limit notifications that have not been published yet, together with
+// the published ones whose delivery was not confirmed before resendBefore. A zero resendBefore leaves
+// published notifications out.
+func (a *App) PendingNotifications(
+	ctx context.Context,
+	resendBefore time.Time,
+	limit int,
+) ([]types.Notification, error) {
+	ctx, span := tracing.Start(ctx, "App.PendingNotifications")
+	defer span.End()
+
+	if err := auth.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	notifications, err := a.Storage.ListPendingNotifications(ctx, resendBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	return mappers.ToDomainNotifications(notifications), nil
+}
+
+func (a *App) MarkNotificationPublished(ctx context.Context, id string) error {
+	ctx, span := tracing.Start(ctx, "App.MarkNotificationPublished")
+	defer span.End()
+
+	if err := auth.CheckAdmin(ctx); err != nil {
+		return err
+	}
+	return a.Storage.SetNotificationState(ctx, id, storagecommon.NotificationPublished, time.Now())
+}
+
+// RecordNotificationStatus adds a status reported by the sender to the delivery history; a delivered
+// status also marks the notification delivered, so that it is not sent again.
+func (a *App) RecordNotificationStatus(ctx context.Context, status types.NotificationStatus) error {
+	ctx, span := tracing.Start(ctx, "App.RecordNotificationStatus")
+	defer span.End()
 
-	for _, event := range allEvents {
-		notifyAt := event.StartTime.Add(-time.Second * time.Duration(event.NotifyBefore))
+	if err := auth.CheckAdmin(ctx); err != nil {
+		return err
+	}
+	if status.ReportedAt.IsZero() {
+		status.ReportedAt = time.Now()
+	}
+	return a.Storage.AddNotificationStatus(ctx, mappers.FromDomainNotificationStatus(status))
+}
+
+// InviteAttendee invites a user to an event; only the owner of the event may invite.
+func (a *App) InviteAttendee(ctx context.Context, attendee types.Attendee) error {
+	ctx, span := tracing.Start(ctx, "App.InviteAttendee")
+	defer span.End()
+
+	event, err := a.Storage.GetByID(ctx, attendee.EventID)
+	if err != nil {
+		return err
+	}
+	if err := auth.CheckAccess(ctx, event.UserID); err != nil {
+		return err
+	}
+	return a.Storage.AddAttendee(ctx, mappers.FromDomainAttendee(attendee))
+}
 
-		if event.StartTime.After(now) && notifyAt.Before(before) && notifyAt.After(now) {
-			dueEvents = append(dueEvents, event)
+// RespondToInvitation records the response of an invited user.
+func (a *App) RespondToInvitation(ctx context.Context, attendee types.Attendee) error {
+	ctx, span := tracing.Start(ctx, "App.RespondToInvitation")
+	defer span.End()
+
+	if err := auth.CheckAccess(ctx, attendee.UserID); err != nil {
+		return err
+	}
+	return a.Storage.UpdateAttendee(ctx, mappers.FromDomainAttendee(attendee))
+}
+
+// RemoveAttendee withdraws an invitation; the owner and the attendee themselves may do it.
+func (a *App) RemoveAttendee(ctx context.Context, eventID, userID string) error {
+	ctx, span := tracing.Start(ctx, "App.RemoveAttendee")
+	defer span.End()
+
+	event, err := a.Storage.GetByID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if err := auth.CheckAccess(ctx, event.UserID); err != nil && auth.CheckAccess(ctx, userID) != nil {
+		return err
+	}
+	return a.Storage.RemoveAttendee(ctx, eventID, userID)
+}
+
+func (a *App) ListAttendees(ctx context.Context, eventID string) ([]types.Attendee, error) {
+	ctx, span := tracing.Start(ctx, "App.ListAttendees")
+	defer span.End()
+
+	event, err := a.Storage.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.checkEventAccess(ctx, event); err != nil {
+		return nil, err
+	}
+
+	attendees, err := a.Storage.ListAttendees(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return mappers.ToDomainAttendees(attendees), nil
+}
+
+// ListNotifications returns the notifications of the event with their delivery history.
+func (a *App) ListNotifications(ctx context.Context, eventID string) ([]types.Notification, error) {
+	ctx, span := tracing.Start(ctx, "App.ListNotifications")
+	defer span.End()
+
+	event, err := a.Storage.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.checkEventAccess(ctx, event); err != nil {
+		return nil, err
+	}
+
+	storNotifications, err := a.Storage.ListNotifications(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := a.Storage.ListNotificationStatuses(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	notifications := mappers.ToDomainNotifications(storNotifications)
+	index := make(map[string]int, len(notifications))
+	for k, notification := range notifications {
+		index[notification.ID] = k
+	}
+	for _, status := range statuses {
+		if k, ok := index[status.NotificationID]; ok {
+			notifications[k].Statuses = append(notifications[k].Statuses, mappers.ToDomainNotificationStatus(status))
 		}
 	}
+	return notifications, nil
+}
+
+// checkEventAccess allows the owner of the event and its attendees.
+func (a *App) checkEventAccess(ctx context.Context, event storagecommon.Event) error {
+	if err := auth.CheckAccess(ctx, event.UserID); !errors.Is(err, auth.ErrForbidden) {
+		return err
+	}
 
-	return dueEvents, nil
+	id, _ := auth.FromContext(ctx)
+	attendees, err := a.Storage.ListAttendees(ctx, event.ID)
+	if err != nil {
+		return err
+	}
+	for _, attendee := range attendees {
+		if attendee.UserID == id.UserID {
+			return nil
+		}
+	}
+	return auth.ErrForbidden
 }
@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const RoleAdmin = "admin"
+
+var (
+	ErrUnauthenticated = fmt.Errorf("unauthenticated")
+	ErrForbidden       = fmt.Errorf("access to the event is forbidden")
+)
+
+// Identity is the authenticated caller of an API request.
+type Identity struct {
+	UserID string
+	Roles  []string
+}
+
+func (id Identity) HasRole(role string) bool {
+	for _, r := range id.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (id Identity) IsAdmin() bool {
+	return id.HasRole(RoleAdmin)
+}
+
+// CanAccess reports whether the caller may read or change events of the given user.
+func (id Identity) CanAccess(userID string) bool {
+	return id.IsAdmin() || id.UserID == userID
+}
+
+type (
+	identityKey struct{}
+	systemKey   struct{}
+)
+
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
+
+// WithSystem marks the caller as trusted with every event: the services' own work, such as scheduling
+// notifications, and the API requests when authentication is disabled.
+func WithSystem(ctx context.Context) context.Context {
+	return context.WithValue(ctx, systemKey{}, true)
+}
+
+func IsSystem(ctx context.Context) bool {
+	system, _ := ctx.Value(systemKey{}).(bool)
+	return system
+}
+
+// CheckAccess returns ErrForbidden when the caller in ctx may not access events of userID, and
+// ErrUnauthenticated when ctx carries neither an identity nor the system mark.
+func CheckAccess(ctx context.Context, userID string) error {
+	if IsSystem(ctx) {
+		return nil
+	}
+	id, ok := FromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	if !id.CanAccess(userID) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// CheckAdmin returns ErrForbidden when the caller in ctx is not an admin, and ErrUnauthenticated when
+// ctx carries neither an identity nor the system mark.
+func CheckAdmin(ctx context.Context) error {
+	if IsSystem(ctx) {
+		return nil
+	}
+	id, ok := FromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	if !id.IsAdmin() {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// BearerToken extracts the token from an "Authorization: Bearer <token>" header value.
+func BearerToken(header string) (string, error) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", fmt.Errorf("%w: missing bearer token", ErrUnauthenticated)
+	}
+	return strings.TrimSpace(token), nil
+}
@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckAccess(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		wantErr   error
+		wantAdmin error
+	}{
+		{name: "no identity", ctx: ctx, wantErr: ErrUnauthenticated, wantAdmin: ErrUnauthenticated},
+		{name: "system", ctx: WithSystem(ctx)},
+		{name: "owner", ctx: WithIdentity(ctx, Identity{UserID: "alice"}), wantAdmin: ErrForbidden},
+		{name: "stranger", ctx: WithIdentity(ctx, Identity{UserID: "bob"}), wantErr: ErrForbidden, wantAdmin: ErrForbidden},
+		{name: "admin", ctx: WithIdentity(ctx, Identity{UserID: "bob", Roles: []string{RoleAdmin}})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorIs(t, CheckAccess(tt.ctx, "alice"), tt.wantErr)
+			require.ErrorIs(t, CheckAdmin(tt.ctx), tt.wantAdmin)
+		})
+	}
+}
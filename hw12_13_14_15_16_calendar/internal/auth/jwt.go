@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5" //nolint:depguard
+)
+
+// Key is a shared HMAC secret identified by the "kid" token header.
+type Key struct {
+	ID     string
+	Secret string
+}
+
+type JWTConfig struct {
+	Keys     []Key
+	Issuer   string
+	Audience string
+	Leeway   time.Duration
+}
+
+// Claims are the JWT claims understood by the calendar: the subject is the user ID.
+type Claims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles,omitempty"`
+}
+
+// JWTAuthenticator verifies HMAC-signed bearer tokens against a local key set.
+type JWTAuthenticator struct {
+	keys   map[string][]byte
+	parser *jwt.Parser
+}
+
+func NewJWTAuthenticator(cfg JWTConfig) (*JWTAuthenticator, error) {
+	if len(cfg.Keys) == 0 {
+		return nil, fmt.Errorf("at least one signing key is required")
+	}
+
+	keys := make(map[string][]byte, len(cfg.Keys))
+	for _, key := range cfg.Keys {
+		if key.Secret == "" {
+			return nil, fmt.Errorf("signing key %q has an empty secret", key.ID)
+		}
+		if _, exists := keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate signing key %q", key.ID)
+		}
+		keys[key.ID] = []byte(key.Secret)
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"HS256", "HS384", "HS512"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(cfg.Leeway),
+	}
+	if cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(cfg.Audience))
+	}
+
+	return &JWTAuthenticator{
+		keys:   keys,
+		parser: jwt.NewParser(options...),
+	}, nil
+}
+
+func (a *JWTAuthenticator) Authenticate(token string) (Identity, error) {
+	var claims Claims
+	if _, err := a.parser.ParseWithClaims(token, &claims, a.key); err != nil {
+		return Identity{}, fmt.Errorf("%w: %w", ErrUnauthenticated, err)
+	}
+	if claims.Subject == "" {
+		return Identity{}, fmt.Errorf("%w: token has no subject", ErrUnauthenticated)
+	}
+	return Identity{UserID: claims.Subject, Roles: claims.Roles}, nil
+}
+
+// key picks the secret named by the token's kid header; a token without kid
+// is accepted only when the key set has a single key.
+func (a *JWTAuthenticator) key(token *jwt.Token) (any, error) {
+	if kid, ok := token.Header["kid"].(string); ok {
+		if secret, ok := a.keys[kid]; ok {
+			return secret, nil
+		}
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	if len(a.keys) == 1 {
+		for _, secret := range a.keys {
+			return secret, nil
+		}
+	}
+	return nil, fmt.Errorf("token has no key id")
+}
+
+// Sign issues an HS256 token for the claims with the given key.
+func Sign(claims Claims, key Key) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	if key.ID != "" {
+		token.Header["kid"] = key.ID
+	}
+	return token.SignedString([]byte(key.Secret))
+}
+
+// NewClaims returns claims for a user valid for ttl from now.
+func NewClaims(userID string, roles []string, ttl time.Duration) Claims {
+	now := time.Now()
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+		Roles: roles,
+	}
+}
@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5" //nolint:depguard
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTAuthenticator(t *testing.T) {
+	primary := Key{ID: "primary", Secret: "primary-secret"}
+	secondary := Key{ID: "secondary", Secret: "secondary-secret"}
+
+	authenticator, err := NewJWTAuthenticator(JWTConfig{
+		Keys:     []Key{primary, secondary},
+		Issuer:   "calendar",
+		Audience: "calendar-api",
+	})
+	require.NoError(t, err)
+
+	valid := func() Claims {
+		claims := NewClaims("user1", []string{RoleAdmin}, time.Hour)
+		claims.Issuer = "calendar"
+		claims.Audience = jwt.ClaimStrings{"calendar-api"}
+		return claims
+	}
+
+	tests := []struct {
+		name    string
+		claims  func() Claims
+		key     Key
+		want    Identity
+		wantErr bool
+	}{
+		{
+			name:   "valid token",
+			claims: valid,
+			key:    secondary,
+			want:   Identity{UserID: "user1", Roles: []string{RoleAdmin}},
+		},
+		{
+			name: "expired token",
+			claims: func() Claims {
+				claims := valid()
+				claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+				return claims
+			},
+			key:     primary,
+			wantErr: true,
+		},
+		{
+			name: "missing expiration",
+			claims: func() Claims {
+				claims := valid()
+				claims.ExpiresAt = nil
+				return claims
+			},
+			key:     primary,
+			wantErr: true,
+		},
+		{
+			name: "wrong issuer",
+			claims: func() Claims {
+				claims := valid()
+				claims.Issuer = "someone"
+				return claims
+			},
+			key:     primary,
+			wantErr: true,
+		},
+		{
+			name: "missing subject",
+			claims: func() Claims {
+				claims := valid()
+				claims.Subject = ""
+				return claims
+			},
+			key:     primary,
+			wantErr: true,
+		},
+		{
+			name:    "unknown key",
+			claims:  valid,
+			key:     Key{ID: "other", Secret: "primary-secret"},
+			wantErr: true,
+		},
+		{
+			name:    "wrong secret",
+			claims:  valid,
+			key:     Key{ID: "primary", Secret: "forged"},
+			wantErr: true,
+		},
+		{
+			name:    "key id required with several keys",
+			claims:  valid,
+			key:     Key{Secret: "primary-secret"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := Sign(tt.claims(), tt.key)
+			require.NoError(t, err)
+
+			got, err := authenticator.Authenticate(token)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrUnauthenticated)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestJWTAuthenticator_RejectsUnsignedTokens(t *testing.T) {
+	authenticator, err := NewJWTAuthenticator(JWTConfig{Keys: []Key{{Secret: "secret"}}})
+	require.NoError(t, err)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodNone, NewClaims("user1", nil, time.Hour)).
+		SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	_, err = authenticator.Authenticate(token)
+	require.ErrorIs(t, err, ErrUnauthenticated)
+
+	token, err = Sign(NewClaims("user1", nil, time.Hour), Key{Secret: "secret"})
+	require.NoError(t, err)
+	id, err := authenticator.Authenticate(token)
+	require.NoError(t, err)
+	require.Equal(t, "user1", id.UserID)
+	require.False(t, id.IsAdmin())
+}
+
+func TestBearerToken(t *testing.T) {
+	token, err := BearerToken("Bearer abc.def.ghi")
+	require.NoError(t, err)
+	require.Equal(t, "abc.def.ghi", token)
+
+	token, err = BearerToken("bearer  abc")
+	require.NoError(t, err)
+	require.Equal(t, "abc", token)
+
+	for _, header := range []string{"", "Basic abc", "Bearer", "Bearer   "} {
+		_, err := BearerToken(header)
+		require.ErrorIs(t, err, ErrUnauthenticated, header)
+	}
+}
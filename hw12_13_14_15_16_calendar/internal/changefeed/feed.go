@@ -0,0 +1,193 @@
+package changefeed
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/types"
+)
+
+type Op string
+
+const (
+	OpCreated Op = "created"
+	OpUpdated Op = "updated"
+	OpDeleted Op = "deleted"
+)
+
+const (
+	DefaultHistorySize = 10000
+	subscriberBuffer   = 256
+)
+
+var (
+	ErrInvalidRevision = fmt.Errorf("invalid revision token")
+	ErrRevisionExpired = fmt.Errorf("revision is no longer available")
+	ErrSlowSubscriber  = fmt.Errorf("subscriber fell behind the change feed")
+)
+
+// Change is a single mutation of an event. Deleted changes carry the last known state of the event.
+type Change struct {
+	Revision uint64
+	Token    string
+	Op       Op
+	Event    types.Event
+}
+
+// Feed keeps the most recent changes in memory and fans them out to subscribers.
+// Revision tokens embed the epoch of the process, so tokens issued before a restart
+// are reported as expired instead of silently skipping changes.
+type Feed struct {
+	mu          sync.Mutex
+	epoch       string
+	revision    uint64
+	history     []Change
+	oldest      int
+	subscribers map[*Subscription]struct{}
+}
+
+func New(historySize int) *Feed {
+	if historySize <= 0 {
+		historySize = DefaultHistorySize
+	}
+	return &Feed{
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		history:     make([]Change, 0, historySize),
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+func (f *Feed) Publish(op Op, event types.Event) Change {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.revision++
+	change := Change{Revision: f.revision, Token: f.Token(f.revision), Op: op, Event: event}
+
+	if len(f.history) < cap(f.history) {
+		f.history = append(f.history, change)
+	} else {
+		f.history[f.oldest] = change
+		f.oldest = (f.oldest + 1) % len(f.history)
+	}
+
+	for sub := range f.subscribers {
+		if sub.userID != event.UserID {
+			continue
+		}
+		select {
+		case sub.ch <- change:
+		default:
+			f.drop(sub, ErrSlowSubscriber)
+		}
+	}
+	return change
+}
+
+// Subscribe streams the changes of a user's events. With a non-empty token the changes
+// made after that revision are replayed first.
+func (f *Feed) Subscribe(userID, token string) (*Subscription, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var replay []Change
+	if token != "" {
+		after, err := f.parseToken(token)
+		if err != nil {
+			return nil, err
+		}
+		if after > f.revision {
+			return nil, ErrInvalidRevision
+		}
+		if len(f.history) > 0 && after+1 < f.history[f.oldest].Revision {
+			return nil, ErrRevisionExpired
+		}
+		for k := range f.history {
+			change := f.history[(f.oldest+k)%len(f.history)]
+			if change.Revision > after && change.Event.UserID == userID {
+				replay = append(replay, change)
+			}
+		}
+	}
+
+	sub := &Subscription{
+		feed:     f,
+		userID:   userID,
+		revision: f.revision,
+		ch:       make(chan Change, len(replay)+subscriberBuffer),
+	}
+	for _, change := range replay {
+		sub.ch <- change
+	}
+	f.subscribers[sub] = struct{}{}
+	return sub, nil
+}
+
+// Token returns the revision token that resumes the feed after the given revision.
+func (f *Feed) Token(revision uint64) string {
+	raw := f.epoch + ":" + strconv.FormatUint(revision, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func (f *Feed) parseToken(token string) (uint64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, ErrInvalidRevision
+	}
+	epoch, rev, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return 0, ErrInvalidRevision
+	}
+	revision, err := strconv.ParseUint(rev, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidRevision
+	}
+	if epoch != f.epoch {
+		return 0, ErrRevisionExpired
+	}
+	return revision, nil
+}
+
+func (f *Feed) drop(sub *Subscription, err error) {
+	if _, ok := f.subscribers[sub]; !ok {
+		return
+	}
+	delete(f.subscribers, sub)
+	sub.err = err
+	close(sub.ch)
+}
+
+type Subscription struct {
+	feed     *Feed
+	userID   string
+	revision uint64
+	ch       chan Change
+	err      error
+}
+
+// Token is the revision token of the feed at the moment of subscribing, so a client
+// that received no changes can still resume without missing any.
+func (s *Subscription) Token() string {
+	return s.feed.Token(s.revision)
+}
+
+// Changes is closed when the subscription is closed or dropped; Err tells which.
+func (s *Subscription) Changes() <-chan Change {
+	return s.ch
+}
+
+func (s *Subscription) Err() error {
+	s.feed.mu.Lock()
+	defer s.feed.mu.Unlock()
+	return s.err
+}
+
+func (s *Subscription) Close() {
+	s.feed.mu.Lock()
+	defer s.feed.mu.Unlock()
+	s.feed.drop(s, nil)
+}
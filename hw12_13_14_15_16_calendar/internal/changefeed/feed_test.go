@@ -0,0 +1,97 @@
+package changefeed
+
+import (
+	"testing"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeed_SubscribeReceivesUserChanges(t *testing.T) {
+	feed := New(10)
+
+	sub, err := feed.Subscribe("user1", "")
+	require.NoError(t, err)
+	defer sub.Close()
+
+	feed.Publish(OpCreated, types.Event{ID: "1", UserID: "user1"})
+	feed.Publish(OpCreated, types.Event{ID: "2", UserID: "user2"})
+	feed.Publish(OpDeleted, types.Event{ID: "1", UserID: "user1"})
+
+	first := <-sub.Changes()
+	require.Equal(t, OpCreated, first.Op)
+	require.Equal(t, uint64(1), first.Revision)
+
+	second := <-sub.Changes()
+	require.Equal(t, OpDeleted, second.Op)
+	require.Equal(t, uint64(3), second.Revision)
+	require.Empty(t, sub.Changes())
+}
+
+func TestFeed_ResumeFromToken(t *testing.T) {
+	feed := New(10)
+
+	sub, err := feed.Subscribe("user1", "")
+	require.NoError(t, err)
+	token := sub.Token()
+	sub.Close()
+
+	_, ok := <-sub.Changes()
+	require.False(t, ok)
+	require.NoError(t, sub.Err())
+
+	feed.Publish(OpCreated, types.Event{ID: "1", UserID: "user1"})
+	feed.Publish(OpUpdated, types.Event{ID: "1", UserID: "user1"})
+
+	resumed, err := feed.Subscribe("user1", token)
+	require.NoError(t, err)
+	defer resumed.Close()
+
+	require.Equal(t, OpCreated, (<-resumed.Changes()).Op)
+	require.Equal(t, OpUpdated, (<-resumed.Changes()).Op)
+
+	feed.Publish(OpDeleted, types.Event{ID: "1", UserID: "user1"})
+	require.Equal(t, OpDeleted, (<-resumed.Changes()).Op)
+}
+
+func TestFeed_InvalidTokens(t *testing.T) {
+	feed := New(2)
+	for k := 0; k < 5; k++ {
+		feed.Publish(OpCreated, types.Event{UserID: "user1"})
+	}
+
+	_, err := feed.Subscribe("user1", "garbage")
+	require.ErrorIs(t, err, ErrInvalidRevision)
+
+	_, err = feed.Subscribe("user1", feed.Token(10))
+	require.ErrorIs(t, err, ErrInvalidRevision)
+
+	_, err = feed.Subscribe("user1", feed.Token(1))
+	require.ErrorIs(t, err, ErrRevisionExpired)
+
+	_, err = feed.Subscribe("user1", New(2).Token(0))
+	require.ErrorIs(t, err, ErrRevisionExpired)
+
+	sub, err := feed.Subscribe("user1", feed.Token(3))
+	require.NoError(t, err)
+	require.Len(t, sub.Changes(), 2)
+	sub.Close()
+}
+
+func TestFeed_DropsSlowSubscriber(t *testing.T) {
+	feed := New(10)
+
+	sub, err := feed.Subscribe("user1", "")
+	require.NoError(t, err)
+
+	for k := 0; k <= subscriberBuffer; k++ {
+		feed.Publish(OpCreated, types.Event{UserID: "user1"})
+	}
+
+	count := 0
+	for range sub.Changes() {
+		count++
+	}
+	require.Equal(t, subscriberBuffer, count)
+	require.ErrorIs(t, sub.Err(), ErrSlowSubscriber)
+}
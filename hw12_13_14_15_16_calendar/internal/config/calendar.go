@@ -10,6 +10,8 @@ type (
 		Log      `yaml:"log"`
 		Database `yaml:"database"`
 		GRPC     `yaml:"grpc"`
+		Auth     `yaml:"auth"`
+		Tracing  `yaml:"tracing"`
 	}
 
 	HTTP struct {
@@ -19,12 +21,30 @@ type (
 		WriteTimeout      time.Duration `yaml:"writeTimeout"`
 		IdleTimeout       time.Duration `yaml:"idleTimeout"`
 		ReadHeaderTimeout time.Duration `yaml:"readHeaderTimeout"`
+		// HandlerTimeout bounds request processing; WriteTimeout is used when it is not set.
+		HandlerTimeout time.Duration `yaml:"handlerTimeout"`
 	}
 
 	GRPC struct {
 		Enable bool   `yaml:"enable"`
 		Port   string `yaml:"port" env:"GRPC_PORT"`
 	}
+
+	// Auth configures bearer token verification. Secret is a shortcut for a single key
+	// without an ID, convenient to pass through the environment.
+	Auth struct {
+		Enable   bool          `yaml:"enable" env:"AUTH_ENABLE"`
+		Issuer   string        `yaml:"issuer" env:"AUTH_ISSUER"`
+		Audience string        `yaml:"audience" env:"AUTH_AUDIENCE"`
+		Leeway   time.Duration `yaml:"leeway"`
+		Secret   string        `yaml:"secret" env:"AUTH_SECRET"`
+		Keys     []AuthKey     `yaml:"keys"`
+	}
+
+	AuthKey struct {
+		ID     string `yaml:"id"`
+		Secret string `yaml:"secret"`
+	}
 )
 
 func NewCalendarConfig(configPath string) (*CalendarConfig, error) {
@@ -13,20 +13,61 @@ type (
 		Level string `yaml:"level" env:"LOG_LEVEL"`
 	}
 
+	// Database selects the storage: "memory", "postgres" or "sqlite". For sqlite the DSN is
+	// the database file path and migrations live in the sqlite subdirectory of the migrations.
+	// Memory storage is durable when DataDir is set: it keeps a write-ahead log there and compacts
+	// it into a snapshot every SnapshotEvery records.
 	Database struct {
 		Type           string        `yaml:"type"`
 		DSN            string        `yaml:"dsn" env:"DATABASE_DSN"`
 		MigrationsPath string        `yaml:"migrations" env:"MIGRATIONS_PATH"`
 		Migrate        bool          `yaml:"migrate" env:"MIGRATE"`
 		Timeout        time.Duration `yaml:"timeout"`
+		DataDir        string        `yaml:"dataDir" env:"DATA_DIR"`
+		SnapshotEvery  int           `yaml:"snapshotEvery"`
 	}
 
+	// RabbitMQ describes the broker. Prefetch bounds the unacknowledged messages a consumer holds.
+	// A lost connection is retried with delays doubling from ReconnectInterval to MaxReconnectInterval.
+	// ConfirmTimeout bounds the wait for the broker to confirm a published message.
 	RabbitMQ struct {
-		Host     string `yaml:"host" env:"RABBIT_HOST"`
-		Port     string `yaml:"port" env:"RABBIT_PORT"`
-		User     string `yaml:"user" env:"RABBIT_USER"`
-		Password string `yaml:"password" env:"RABBIT_PASSWORD"`
-		Exchange string `yaml:"exchange" env:"RABBIT_EXCHANGE"`
+		Host                 string        `yaml:"host" env:"RABBIT_HOST"`
+		Port                 string        `yaml:"port" env:"RABBIT_PORT"`
+		User                 string        `yaml:"user" env:"RABBIT_USER"`
+		Password             string        `yaml:"password" env:"RABBIT_PASSWORD"`
+		Exchange             string        `yaml:"exchange" env:"RABBIT_EXCHANGE"`
+		Prefetch             int           `yaml:"prefetch"`
+		Retry                RabbitRetry   `yaml:"retry"`
+		ReconnectInterval    time.Duration `yaml:"reconnectInterval"`
+		MaxReconnectInterval time.Duration `yaml:"maxReconnectInterval"`
+		ConfirmTimeout       time.Duration `yaml:"confirmTimeout"`
+	}
+
+	// RabbitRetry configures the redelivery of messages that failed to be processed: the delay starts at
+	// InitialInterval and grows by Multiplier up to MaxInterval; after MaxRetries retries the message is
+	// moved to the dead-letter queue. Unlike the unset delays, which fall back to defaults, an unset or zero
+	// MaxRetries disables retries: a message is dead-lettered on its first failure.
+	RabbitRetry struct {
+		MaxRetries      int           `yaml:"maxRetries" env:"RABBIT_MAX_RETRIES"`
+		InitialInterval time.Duration `yaml:"initialInterval"`
+		MaxInterval     time.Duration `yaml:"maxInterval"`
+		Multiplier      float64       `yaml:"multiplier"`
+	}
+
+	// Metrics is the address of the listener that serves Prometheus metrics on /metrics and, for the services
+	// without an HTTP API, the probes on /healthz and /readyz; empty disables it.
+	Metrics struct {
+		Addr string `yaml:"addr" env:"METRICS_ADDR"`
+	}
+
+	// Tracing selects where the spans of the service go: "otlp" sends them to the collector at Endpoint,
+	// "stdout" prints them and an empty exporter disables tracing. SampleRatio is the share of new traces
+	// that are recorded; all of them when it is not set.
+	Tracing struct {
+		Exporter    string  `yaml:"exporter" env:"TRACING_EXPORTER"`
+		Endpoint    string  `yaml:"endpoint" env:"TRACING_ENDPOINT"`
+		Insecure    bool    `yaml:"insecure" env:"TRACING_INSECURE"`
+		SampleRatio float64 `yaml:"sampleRatio" env:"TRACING_SAMPLE_RATIO"`
 	}
 )
 
@@ -1,10 +1,52 @@
 package config
 
+import "time"
+
 type (
 	SenderConfig struct {
 		RabbitMQ  `yaml:"rabbitmq"`
 		Log       `yaml:"log"`
-		QueueName string `yaml:"queueName"`
+		Metrics   `yaml:"metrics"`
+		Tracing   `yaml:"tracing"`
+		QueueName string   `yaml:"queueName"`
+		Notifier  Notifier `yaml:"notifier"`
+	}
+
+	// Notifier selects how notifications reach users: through the "file", "smtp" or "webhook" channel.
+	// Channel is used for users not listed in Users; a user's Address is the email address or the
+	// webhook URL the notifications are sent to.
+	Notifier struct {
+		Channel string                  `yaml:"channel" env:"NOTIFIER_CHANNEL"`
+		File    FileChannel             `yaml:"file"`
+		SMTP    SMTPChannel             `yaml:"smtp"`
+		Webhook WebhookChannel          `yaml:"webhook"`
+		Users   map[string]NotifierUser `yaml:"users"`
+	}
+
+	// FileChannel appends notifications to Path as JSON lines, or writes them to stdout when Path is empty.
+	FileChannel struct {
+		Path string `yaml:"path" env:"NOTIFIER_FILE"`
+	}
+
+	SMTPChannel struct {
+		Host     string        `yaml:"host" env:"SMTP_HOST"`
+		Port     string        `yaml:"port" env:"SMTP_PORT"`
+		Username string        `yaml:"username" env:"SMTP_USERNAME"`
+		Password string        `yaml:"password" env:"SMTP_PASSWORD"`
+		From     string        `yaml:"from" env:"SMTP_FROM"`
+		Timeout  time.Duration `yaml:"timeout"`
+	}
+
+	// WebhookChannel posts notifications to URL, or to the user's address, signed with Secret.
+	WebhookChannel struct {
+		URL     string        `yaml:"url" env:"WEBHOOK_URL"`
+		Secret  string        `yaml:"secret" env:"WEBHOOK_SECRET"`
+		Timeout time.Duration `yaml:"timeout"`
+	}
+
+	NotifierUser struct {
+		Channel string `yaml:"channel"`
+		Address string `yaml:"address"`
 	}
 )
 
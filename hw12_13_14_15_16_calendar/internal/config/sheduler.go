@@ -8,11 +8,27 @@ type (
 		Database  `yaml:"database"`
 		Scheduler `yaml:"scheduler"`
 		Log       `yaml:"log"`
+		Metrics   `yaml:"metrics"`
+		Tracing   `yaml:"tracing"`
 	}
 
 	Scheduler struct {
 		Interval        time.Duration `yaml:"interval" env:"INTERVAL"`
 		RetentionPeriod time.Duration `yaml:"retentionPeriod"`
+		// ResendAfter is how long a published notification may stay unconfirmed before it is published
+		// again; zero disables resending.
+		ResendAfter time.Duration `yaml:"resendAfter" env:"RESEND_AFTER"`
+		// CatchUp is how far back a missed reminder is still scheduled, e.g. after downtime or for an event
+		// created shortly before it starts; it defaults to Interval.
+		CatchUp        time.Duration  `yaml:"catchUp" env:"CATCH_UP"`
+		LeaderElection LeaderElection `yaml:"leaderElection"`
+	}
+
+	// LeaderElection lets one of the scheduler replicas tick: they campaign for the lock with the key
+	// every Interval, and the leader checks that it still holds it as often.
+	LeaderElection struct {
+		Interval time.Duration `yaml:"interval"`
+		LockKey  int64         `yaml:"lockKey" env:"SCHEDULER_LOCK_KEY"`
 	}
 )
 
@@ -0,0 +1,226 @@
+package freebusy
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/types"
+)
+
+const (
+	MaxUsers     = 50
+	MaxWindow    = 92 * 24 * time.Hour
+	DefaultStep  = 30 * time.Minute
+	DefaultLimit = 20
+	MaxLimit     = 500
+)
+
+var ErrInvalidQuery = fmt.Errorf("invalid free/busy query")
+
+// Validate checks the query and fills in the default step and limit.
+func Validate(q types.FreeBusyQuery) (types.FreeBusyQuery, error) {
+	switch {
+	case len(q.UserIDs) == 0:
+		return q, fmt.Errorf("%w: at least one user is required", ErrInvalidQuery)
+	case len(q.UserIDs) > MaxUsers:
+		return q, fmt.Errorf("%w: at most %d users are allowed", ErrInvalidQuery, MaxUsers)
+	case !q.From.Before(q.To):
+		return q, fmt.Errorf("%w: from must be before to", ErrInvalidQuery)
+	case q.To.Sub(q.From) > MaxWindow:
+		return q, fmt.Errorf("%w: window must not exceed %s", ErrInvalidQuery, MaxWindow)
+	case q.Duration <= 0:
+		return q, fmt.Errorf("%w: duration must be positive", ErrInvalidQuery)
+	case q.Step < 0 || q.Limit < 0:
+		return q, fmt.Errorf("%w: step and limit must not be negative", ErrInvalidQuery)
+	}
+	for _, userID := range q.UserIDs {
+		if userID == "" {
+			return q, fmt.Errorf("%w: empty user ID", ErrInvalidQuery)
+		}
+	}
+	if q.WorkingHours != nil {
+		wh := *q.WorkingHours
+		if wh.Start < 0 || wh.End > 24*time.Hour || wh.Start >= wh.End {
+			return q, fmt.Errorf("%w: working hours must be a range within a day", ErrInvalidQuery)
+		}
+		if wh.Location == nil {
+			wh.Location = time.UTC
+		}
+		q.WorkingHours = &wh
+	}
+
+	if q.Step == 0 {
+		q.Step = DefaultStep
+	}
+	if q.Limit == 0 {
+		q.Limit = DefaultLimit
+	}
+	if q.Limit > MaxLimit {
+		q.Limit = MaxLimit
+	}
+	return q, nil
+}
+
+// Merge clips the intervals to [from, to) and joins overlapping and adjacent ones.
+func Merge(intervals []types.Interval, from, to time.Time) []types.Interval {
+	clipped := make([]types.Interval, 0, len(intervals))
+	for _, in := range intervals {
+		if in.Start.Before(from) {
+			in.Start = from
+		}
+		if in.End.After(to) {
+			in.End = to
+		}
+		if in.Start.Before(in.End) {
+			clipped = append(clipped, in)
+		}
+	}
+	sort.Slice(clipped, func(i, j int) bool { return clipped[i].Start.Before(clipped[j].Start) })
+
+	merged := make([]types.Interval, 0, len(clipped))
+	for _, in := range clipped {
+		last := len(merged) - 1
+		if last >= 0 && !in.Start.After(merged[last].End) {
+			if in.End.After(merged[last].End) {
+				merged[last].End = in.End
+			}
+			continue
+		}
+		merged = append(merged, in)
+	}
+	return merged
+}
+
+// Free returns the gaps of [from, to) not covered by the merged busy intervals.
+func Free(busy []types.Interval, from, to time.Time) []types.Interval {
+	free := make([]types.Interval, 0, len(busy)+1)
+	cursor := from
+	for _, in := range busy {
+		if in.Start.After(cursor) {
+			free = append(free, types.Interval{Start: cursor, End: in.Start})
+		}
+		if in.End.After(cursor) {
+			cursor = in.End
+		}
+	}
+	if cursor.Before(to) {
+		free = append(free, types.Interval{Start: cursor, End: to})
+	}
+	return free
+}
+
+// Slots returns up to q.Limit meeting slots of q.Duration, soonest first, that start
+// every q.Step inside the common free time and within the working hours.
+func Slots(q types.FreeBusyQuery, busy map[string][]types.Interval) []types.Interval {
+	all := make([]types.Interval, 0)
+	for _, intervals := range busy {
+		all = append(all, intervals...)
+	}
+	common := Merge(all, q.From, q.To)
+
+	slots := make([]types.Interval, 0)
+	for _, window := range workingWindows(q) {
+		for _, gap := range Free(Merge(common, window.Start, window.End), window.Start, window.End) {
+			for start := gap.Start; !start.Add(q.Duration).After(gap.End); start = start.Add(q.Step) {
+				slots = append(slots, types.Interval{Start: start, End: start.Add(q.Duration)})
+				if len(slots) == q.Limit {
+					return slots
+				}
+			}
+		}
+	}
+	return slots
+}
+
+// workingWindows splits [q.From, q.To) into the working hours of each day.
+func workingWindows(q types.FreeBusyQuery) []types.Interval {
+	wh := q.WorkingHours
+	if wh == nil {
+		return []types.Interval{{Start: q.From, End: q.To}}
+	}
+
+	windows := make([]types.Interval, 0)
+	local := q.From.In(wh.Location)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, wh.Location)
+	for ; day.Before(q.To); day = day.AddDate(0, 0, 1) {
+		if !workday(wh, day.Weekday()) {
+			continue
+		}
+		start, end := atClock(day, wh.Start), atClock(day, wh.End)
+		if start.Before(q.From) {
+			start = q.From
+		}
+		if end.After(q.To) {
+			end = q.To
+		}
+		if start.Before(end) {
+			windows = append(windows, types.Interval{Start: start, End: end})
+		}
+	}
+	return windows
+}
+
+func workday(wh *types.WorkingHours, weekday time.Weekday) bool {
+	if len(wh.Weekdays) == 0 {
+		return true
+	}
+	for _, d := range wh.Weekdays {
+		if d == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// atClock returns the wall clock offset of the day, so working hours keep
+// their local time across daylight saving shifts.
+func atClock(day time.Time, offset time.Duration) time.Time {
+	if offset == 24*time.Hour {
+		return day.AddDate(0, 0, 1)
+	}
+	hours, minutes := int(offset/time.Hour), int(offset%time.Hour/time.Minute)
+	return time.Date(day.Year(), day.Month(), day.Day(), hours, minutes, 0, 0, day.Location())
+}
+
+// ParseClock parses a "HH:MM" clock time into an offset from midnight; "24:00" is the end of the day.
+func ParseClock(s string) (time.Duration, error) {
+	hh, mm, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, fmt.Errorf("%w: clock time %q must be HH:MM", ErrInvalidQuery, s)
+	}
+	hours, errH := strconv.Atoi(hh)
+	minutes, errM := strconv.Atoi(mm)
+	if errH != nil || errM != nil || hours < 0 || minutes < 0 || minutes > 59 || hours*60+minutes > 24*60 {
+		return 0, fmt.Errorf("%w: clock time %q must be HH:MM", ErrInvalidQuery, s)
+	}
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+}
+
+// ParseWorkingHours builds working hours from "HH:MM" bounds, an IANA zone name
+// (UTC when empty) and ISO weekday numbers where Monday is 1 and Sunday is 7.
+func ParseWorkingHours(start, end, timeZone string, weekdays []int) (*types.WorkingHours, error) {
+	from, err := ParseClock(start)
+	if err != nil {
+		return nil, err
+	}
+	to, err := ParseClock(end)
+	if err != nil {
+		return nil, err
+	}
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown time zone %q", ErrInvalidQuery, timeZone)
+	}
+
+	days := make([]time.Weekday, 0, len(weekdays))
+	for _, d := range weekdays {
+		if d < 1 || d > 7 {
+			return nil, fmt.Errorf("%w: weekday %d must be within 1..7", ErrInvalidQuery, d)
+		}
+		days = append(days, time.Weekday(d%7))
+	}
+	return &types.WorkingHours{Start: from, End: to, Location: location, Weekdays: days}, nil
+}
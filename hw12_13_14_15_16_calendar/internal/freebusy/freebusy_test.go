@@ -0,0 +1,148 @@
+package freebusy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+var base = time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC) // Monday
+
+func at(hour, minute int) time.Time {
+	return base.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+func TestMerge(t *testing.T) {
+	got := Merge([]types.Interval{
+		{Start: at(11, 0), End: at(12, 0)},
+		{Start: at(7, 0), End: at(9, 30)},
+		{Start: at(9, 0), End: at(10, 0)},
+		{Start: at(10, 0), End: at(10, 30)},
+		{Start: at(11, 15), End: at(11, 45)},
+		{Start: at(23, 0), End: at(25, 0)},
+	}, at(8, 0), at(24, 0))
+
+	require.Equal(t, []types.Interval{
+		{Start: at(8, 0), End: at(10, 30)},
+		{Start: at(11, 0), End: at(12, 0)},
+		{Start: at(23, 0), End: at(24, 0)},
+	}, got)
+
+	require.Equal(t, []types.Interval{
+		{Start: at(10, 30), End: at(11, 0)},
+		{Start: at(12, 0), End: at(23, 0)},
+	}, Free(got, at(8, 0), at(24, 0)))
+}
+
+func TestSlots(t *testing.T) {
+	busy := map[string][]types.Interval{
+		"alice": {{Start: at(9, 0), End: at(10, 0)}},
+		"bob":   {{Start: at(10, 30), End: at(12, 0)}, {Start: at(13, 0), End: at(17, 0)}},
+	}
+
+	tests := []struct {
+		name  string
+		query types.FreeBusyQuery
+		want  []types.Interval
+	}{
+		{
+			name: "no working hours",
+			query: types.FreeBusyQuery{
+				From: at(9, 0), To: at(14, 0), Duration: time.Hour, Step: 30 * time.Minute, Limit: 10,
+			},
+			want: []types.Interval{{Start: at(12, 0), End: at(13, 0)}},
+		},
+		{
+			name: "short meetings",
+			query: types.FreeBusyQuery{
+				From: at(9, 0), To: at(13, 0), Duration: 30 * time.Minute, Step: 15 * time.Minute, Limit: 10,
+			},
+			want: []types.Interval{
+				{Start: at(10, 0), End: at(10, 30)},
+				{Start: at(12, 0), End: at(12, 30)},
+				{Start: at(12, 15), End: at(12, 45)},
+				{Start: at(12, 30), End: at(13, 0)},
+			},
+		},
+		{
+			name: "limit",
+			query: types.FreeBusyQuery{
+				From: at(9, 0), To: at(13, 0), Duration: 30 * time.Minute, Step: 15 * time.Minute, Limit: 2,
+			},
+			want: []types.Interval{{Start: at(10, 0), End: at(10, 30)}, {Start: at(12, 0), End: at(12, 30)}},
+		},
+		{
+			name: "working hours skip weekend",
+			query: types.FreeBusyQuery{
+				From: base.AddDate(0, 0, -2), To: at(24, 0), Duration: time.Hour, Step: time.Hour, Limit: 10,
+				WorkingHours: &types.WorkingHours{
+					Start: 8 * time.Hour, End: 13 * time.Hour, Location: time.UTC,
+					Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+				},
+			},
+			want: []types.Interval{{Start: at(8, 0), End: at(9, 0)}, {Start: at(12, 0), End: at(13, 0)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Slots(tt.query, busy))
+		})
+	}
+}
+
+func TestSlots_WorkingHoursAcrossDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// Clocks move forward on 2025-03-30; working hours stay 09:00-10:00 local time.
+	query, err := Validate(types.FreeBusyQuery{
+		UserIDs:      []string{"alice"},
+		From:         time.Date(2025, 3, 29, 0, 0, 0, 0, berlin),
+		To:           time.Date(2025, 3, 31, 0, 0, 0, 0, berlin),
+		Duration:     time.Hour,
+		WorkingHours: &types.WorkingHours{Start: 9 * time.Hour, End: 10 * time.Hour, Location: berlin},
+	})
+	require.NoError(t, err)
+
+	slots := Slots(query, map[string][]types.Interval{})
+	require.Len(t, slots, 2)
+	require.Equal(t, time.Date(2025, 3, 29, 8, 0, 0, 0, time.UTC), slots[0].Start.UTC())
+	require.Equal(t, time.Date(2025, 3, 30, 7, 0, 0, 0, time.UTC), slots[1].Start.UTC())
+}
+
+func TestValidate(t *testing.T) {
+	valid := types.FreeBusyQuery{UserIDs: []string{"alice"}, From: at(0, 0), To: at(24, 0), Duration: time.Hour}
+
+	query, err := Validate(valid)
+	require.NoError(t, err)
+	require.Equal(t, DefaultStep, query.Step)
+	require.Equal(t, DefaultLimit, query.Limit)
+
+	invalid := []func(q *types.FreeBusyQuery){
+		func(q *types.FreeBusyQuery) { q.UserIDs = nil },
+		func(q *types.FreeBusyQuery) { q.To = q.From },
+		func(q *types.FreeBusyQuery) { q.To = q.From.Add(MaxWindow + time.Hour) },
+		func(q *types.FreeBusyQuery) { q.Duration = 0 },
+		func(q *types.FreeBusyQuery) {
+			q.WorkingHours = &types.WorkingHours{Start: 18 * time.Hour, End: 9 * time.Hour}
+		},
+	}
+	for _, modify := range invalid {
+		q := valid
+		modify(&q)
+		_, err := Validate(q)
+		require.ErrorIs(t, err, ErrInvalidQuery)
+	}
+
+	_, err = ParseWorkingHours("09:00", "25:00", "", nil)
+	require.ErrorIs(t, err, ErrInvalidQuery)
+	_, err = ParseWorkingHours("09:00", "18:00", "Mars/Olympus", nil)
+	require.ErrorIs(t, err, ErrInvalidQuery)
+	hours, err := ParseWorkingHours("09:30", "24:00", "", []int{1, 7})
+	require.NoError(t, err)
+	require.Equal(t, 9*time.Hour+30*time.Minute, hours.Start)
+	require.Equal(t, []time.Weekday{time.Monday, time.Sunday}, hours.Weekdays)
+}
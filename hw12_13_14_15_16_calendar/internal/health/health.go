@@ -0,0 +1,164 @@
+// Package health serves the probes of the calendar services: /healthz reports that the process is alive
+// and /readyz that the dependencies it needs to do its work are usable.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/rmq"
+)
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+
+	// DefaultTimeout bounds a check that does not answer, so that a hanging dependency fails the probe
+	// instead of outliving it.
+	DefaultTimeout = 2 * time.Second
+)
+
+// Check reports why a dependency is not usable, or nil when it is.
+type Check func(ctx context.Context) error
+
+// Report is the body of the probes: the overall status and the outcome of every check.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Checker runs the readiness checks. A checker without checks, including a nil one, is always ready.
+type Checker struct {
+	timeout time.Duration
+	names   []string
+	checks  []Check
+}
+
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Checker{timeout: timeout}
+}
+
+// Add registers a check under the name it is reported with.
+func (c *Checker) Add(name string, check Check) *Checker {
+	c.names = append(c.names, name)
+	c.checks = append(c.checks, check)
+	return c
+}
+
+// Check runs the checks concurrently and reports the service unavailable when any of them fails.
+func (c *Checker) Check(ctx context.Context) Report {
+	report := Report{Status: StatusOK}
+	if c == nil || len(c.checks) == 0 {
+		return report
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	results := make([]error, len(c.checks))
+	var wg sync.WaitGroup
+	for idx, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[idx] = run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report.Checks = make(map[string]string, len(c.checks))
+	for idx, err := range results {
+		if err != nil {
+			report.Status = StatusUnavailable
+			report.Checks[c.names[idx]] = err.Error()
+			continue
+		}
+		report.Checks[c.names[idx]] = StatusOK
+	}
+	return report
+}
+
+// run returns when the check does or when the context is done, whichever comes first.
+func run(ctx context.Context, check Check) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("check timed out: %w", ctx.Err())
+	}
+}
+
+// Register serves the liveness probe on /healthz and the readiness probe on /readyz.
+func (c *Checker) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		writeReport(w, Report{Status: StatusOK})
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, c.Check(r.Context()))
+	})
+}
+
+func writeReport(w http.ResponseWriter, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if report.Status != StatusOK {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+// IsProbe reports whether the path is one of the probes.
+func IsProbe(path string) bool {
+	return path == "/healthz" || path == "/readyz"
+}
+
+// RabbitMQ fails while the client is not connected to the broker.
+func RabbitMQ(client interface{ State() rmq.State }) Check {
+	return func(context.Context) error {
+		if state := client.State(); state != rmq.StateConnected {
+			return fmt.Errorf("rabbitmq connection is %s", state)
+		}
+		return nil
+	}
+}
+
+// Logger is the part of the service logger the listener reports to.
+type Logger interface {
+	Infof(string, ...interface{})
+	Errorf(string, ...interface{})
+}
+
+// Serve exposes the probes and the metrics on addr until the context is done; it is the listener of
+// the services that serve no HTTP API of their own.
+func Serve(ctx context.Context, addr string, checker *Checker, logger Logger) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	checker.Register(mux)
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	logger.Infof("Serving metrics and probes on %s", addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Errorf("Metrics and probes server failed: %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/health"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/rmq"
+	"github.com/stretchr/testify/require"
+)
+
+type stateFunc func() rmq.State
+
+func (f stateFunc) State() rmq.State {
+	return f()
+}
+
+func ok(context.Context) error {
+	return nil
+}
+
+func TestChecker(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	hanging := func(context.Context) error {
+		<-release
+		return nil
+	}
+	reconnecting := health.RabbitMQ(stateFunc(func() rmq.State { return rmq.StateReconnecting }))
+
+	tests := []struct {
+		name    string
+		checker *health.Checker
+		want    health.Report
+	}{
+		{
+			name: "without checks",
+			want: health.Report{Status: health.StatusOK},
+		},
+		{
+			name:    "all pass",
+			checker: health.NewChecker(0).Add("storage", ok).Add("grpc", ok),
+			want: health.Report{
+				Status: health.StatusOK,
+				Checks: map[string]string{"storage": "ok", "grpc": "ok"},
+			},
+		},
+		{
+			name:    "one fails",
+			checker: health.NewChecker(0).Add("storage", ok).Add("rabbitmq", reconnecting),
+			want: health.Report{
+				Status: health.StatusUnavailable,
+				Checks: map[string]string{"storage": "ok", "rabbitmq": "rabbitmq connection is reconnecting"},
+			},
+		},
+		{
+			name:    "times out",
+			checker: health.NewChecker(10*time.Millisecond).Add("storage", hanging),
+			want: health.Report{
+				Status: health.StatusUnavailable,
+				Checks: map[string]string{"storage": "check timed out: context deadline exceeded"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			tt.checker.Register(mux)
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			wantCode := http.StatusOK
+			if tt.want.Status != health.StatusOK {
+				wantCode = http.StatusServiceUnavailable
+			}
+			require.Equal(t, wantCode, w.Code)
+
+			var report health.Report
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+			require.Equal(t, tt.want, report)
+
+			w = httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+			require.Equal(t, http.StatusOK, w.Code)
+		})
+	}
+}
+
+func TestRabbitMQ(t *testing.T) {
+	state := rmq.StateConnected
+	check := health.RabbitMQ(stateFunc(func() rmq.State { return state }))
+	require.NoError(t, check(context.Background()))
+
+	state = rmq.StateClosed
+	require.EqualError(t, check(context.Background()), "rabbitmq connection is closed")
+}
@@ -0,0 +1,306 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/recurrence"
+	storagecommon "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/types"
+)
+
+var (
+	ErrInvalidCalendar = fmt.Errorf("invalid iCalendar document")
+	ErrInvalidEvent    = fmt.Errorf("invalid VEVENT")
+)
+
+var durationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// Item is a VEVENT parsed from an imported document. Err is set when the
+// component could not be converted to an event; other items are still usable.
+type Item struct {
+	UID   string
+	Event types.Event
+	Err   error
+}
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Decode parses the VEVENT components of a VCALENDAR document.
+func Decode(r io.Reader) ([]Item, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		items        []Item
+		seenCalendar bool
+		inCalendar   bool
+		inEvent      bool
+		inAlarm      bool
+		current      []property
+		alarm        []property
+		trigger      string
+	)
+
+	for _, raw := range lines {
+		prop, err := parseLine(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VCALENDAR"):
+			seenCalendar, inCalendar = true, true
+		case prop.name == "END" && strings.EqualFold(prop.value, "VCALENDAR"):
+			inCalendar = false
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT"):
+			inEvent, current, trigger = true, nil, ""
+		case prop.name == "END" && strings.EqualFold(prop.value, "VEVENT"):
+			items = append(items, buildItem(current, trigger))
+			inEvent = false
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VALARM"):
+			inAlarm, alarm = true, nil
+		case prop.name == "END" && strings.EqualFold(prop.value, "VALARM"):
+			if value, ok := alarmTrigger(alarm); ok && inEvent && trigger == "" {
+				trigger = value
+			}
+			inAlarm = false
+		case inAlarm:
+			alarm = append(alarm, prop)
+		case inEvent:
+			current = append(current, prop)
+		}
+	}
+
+	if !seenCalendar {
+		return nil, fmt.Errorf("%w: missing VCALENDAR", ErrInvalidCalendar)
+	}
+	if inCalendar || inEvent {
+		return nil, fmt.Errorf("%w: unterminated component", ErrInvalidCalendar)
+	}
+	return items, nil
+}
+
+func buildItem(props []property, trigger string) Item {
+	var (
+		item     Item
+		start    time.Time
+		end      time.Time
+		duration time.Duration
+		allDay   bool
+		hasEnd   bool
+		err      error
+	)
+
+	for _, p := range props {
+		switch p.name {
+		case "UID":
+			item.UID = unescapeText(p.value)
+		case "SUMMARY":
+			item.Event.Title = unescapeText(p.value)
+		case "DESCRIPTION":
+			item.Event.Description = unescapeText(p.value)
+		case "DTSTART":
+			start, allDay, err = parseTime(p)
+			item.Event.AllDay = allDay
+			item.Event.TimeZone = p.params["TZID"]
+		case "DTEND":
+			end, _, err = parseTime(p)
+			hasEnd = true
+		case "DURATION":
+			duration, err = parseDuration(p.value)
+			hasEnd = true
+		case "RRULE":
+			var rule recurrence.Rule
+			if rule, err = recurrence.Parse(p.value); err == nil {
+				item.Event.RRule = rule.String()
+			}
+		case "EXDATE":
+			for _, value := range strings.Split(p.value, ",") {
+				var t time.Time
+				if t, _, err = parseTime(property{name: p.name, params: p.params, value: value}); err != nil {
+					break
+				}
+				item.Event.ExDates = append(item.Event.ExDates, t)
+			}
+		}
+
+		if err != nil {
+			item.Err = fmt.Errorf("%w: %s: %w", ErrInvalidEvent, p.name, err)
+			return item
+		}
+	}
+
+	if trigger != "" {
+		offset, err := parseDuration(trigger)
+		if err != nil {
+			item.Err = fmt.Errorf("%w: TRIGGER: %w", ErrInvalidEvent, err)
+			return item
+		}
+		if offset < 0 {
+			item.Event.NotifyBefore = int(-offset / time.Second)
+		}
+	}
+
+	if start.IsZero() {
+		item.Err = fmt.Errorf("%w: DTSTART is required", ErrInvalidEvent)
+		return item
+	}
+
+	switch {
+	case duration != 0:
+		end = start.Add(duration)
+	case !hasEnd && allDay:
+		end = start.AddDate(0, 0, 1)
+	case !hasEnd:
+		end = start
+	}
+
+	item.Event.StartTime = start
+	item.Event.EndTime = end
+	return item
+}
+
+// alarmTrigger returns the start-relative trigger of a VALARM, which maps to
+// NotifyBefore; absolute and end-relative triggers are not supported.
+func alarmTrigger(alarm []property) (string, bool) {
+	for _, p := range alarm {
+		if p.name == "TRIGGER" && !strings.EqualFold(p.params["VALUE"], "DATE-TIME") &&
+			!strings.EqualFold(p.params["RELATED"], "END") {
+			return p.value, true
+		}
+	}
+	return "", false
+}
+
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCalendar, err)
+	}
+	return lines, nil
+}
+
+func parseLine(line string) (property, error) {
+	idx := valueSeparator(line)
+	if idx < 0 {
+		return property{}, fmt.Errorf("%w: malformed line %q", ErrInvalidCalendar, line)
+	}
+
+	head, value := line[:idx], line[idx+1:]
+	parts := strings.Split(head, ";")
+	prop := property{
+		name:   strings.ToUpper(parts[0]),
+		params: make(map[string]string, len(parts)-1),
+		value:  value,
+	}
+	for _, param := range parts[1:] {
+		key, val, _ := strings.Cut(param, "=")
+		prop.params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+	}
+	return prop, nil
+}
+
+// valueSeparator finds the colon separating the property head from its value,
+// skipping colons inside quoted parameter values.
+func valueSeparator(line string) int {
+	quoted := false
+	for i, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ':' && !quoted:
+			return i
+		}
+	}
+	return -1
+}
+
+func parseTime(p property) (t time.Time, allDay bool, err error) {
+	value := strings.TrimSpace(p.value)
+
+	if strings.HasSuffix(value, "Z") {
+		t, err = time.Parse(dateTimeLayout, value)
+		return t, false, err
+	}
+
+	loc, err := storagecommon.LoadLocation(p.params["TZID"])
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("unknown TZID %q", p.params["TZID"])
+	}
+
+	if strings.EqualFold(p.params["VALUE"], "DATE") || len(value) == len(dateLayout) {
+		t, err = time.ParseInLocation(dateLayout, value, loc)
+		return t, true, err
+	}
+	t, err = time.ParseInLocation(localTimeLayout, value, loc)
+	return t, false, err
+}
+
+func parseDuration(value string) (time.Duration, error) {
+	m := durationPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if m[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+2])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		d += time.Duration(n) * unit
+	}
+
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+func unescapeText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
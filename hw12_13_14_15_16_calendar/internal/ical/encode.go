@@ -0,0 +1,152 @@
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	storagecommon "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/types"
+)
+
+const (
+	ContentType = "text/calendar; charset=utf-8"
+
+	prodID          = "-//dimryb//go-hw calendar//EN"
+	dateTimeLayout  = "20060102T150405Z"
+	localTimeLayout = "20060102T150405"
+	dateLayout      = "20060102"
+	maxLineOctets   = 75
+)
+
+// Encode renders the events as a VCALENDAR document.
+func Encode(events []types.Event) []byte {
+	w := &writer{}
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:" + prodID)
+	w.line("CALSCALE:GREGORIAN")
+
+	stamp := formatTime(time.Now())
+	for _, event := range events {
+		w.line("BEGIN:VEVENT")
+		w.line("UID:" + escapeText(event.ID))
+		w.line("DTSTAMP:" + stamp)
+		w.line("DTSTART" + formatEventTime(event, event.StartTime))
+		w.line("DTEND" + formatEventTime(event, event.EndTime))
+		w.line("SUMMARY:" + escapeText(event.Title))
+		if event.Description != "" {
+			w.line("DESCRIPTION:" + escapeText(event.Description))
+		}
+		if event.RRule != "" {
+			w.line("RRULE:" + event.RRule)
+		}
+		if len(event.ExDates) > 0 {
+			exdates := make([]string, 0, len(event.ExDates))
+			for _, t := range event.ExDates {
+				exdates = append(exdates, formatLocalTime(event, t))
+			}
+			w.line("EXDATE" + timeParams(event) + ":" + strings.Join(exdates, ","))
+		}
+		if event.NotifyBefore > 0 {
+			w.line("BEGIN:VALARM")
+			w.line("ACTION:DISPLAY")
+			w.line("DESCRIPTION:" + escapeText(event.Title))
+			w.line(fmt.Sprintf("TRIGGER:-PT%dS", event.NotifyBefore))
+			w.line("END:VALARM")
+		}
+		w.line("END:VEVENT")
+	}
+
+	w.line("END:VCALENDAR")
+	return w.buf.Bytes()
+}
+
+// SelectSeries returns the series from all that have at least one occurrence
+// among the expanded occurrences of a range query.
+func SelectSeries(all, occurrences []types.Event) []types.Event {
+	ids := make(map[string]struct{}, len(occurrences))
+	for _, o := range occurrences {
+		ids[o.ID] = struct{}{}
+	}
+
+	series := make([]types.Event, 0, len(ids))
+	for _, e := range all {
+		if _, ok := ids[e.ID]; ok {
+			series = append(series, e)
+		}
+	}
+	return series
+}
+
+type writer struct {
+	buf bytes.Buffer
+}
+
+// line writes a content line folded at 75 octets without splitting UTF-8 sequences.
+func (w *writer) line(s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(s[cut]) {
+			cut--
+		}
+		w.buf.WriteString(s[:cut])
+		w.buf.WriteString("\r\n ")
+		s = s[cut:]
+		limit = maxLineOctets - 1
+	}
+	w.buf.WriteString(s)
+	w.buf.WriteString("\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout)
+}
+
+// formatEventTime renders the parameters and value of a DTSTART or DTEND property:
+// a DATE for all-day events, a local time with TZID for zoned events and UTC otherwise.
+func formatEventTime(event types.Event, t time.Time) string {
+	return timeParams(event) + ":" + formatLocalTime(event, t)
+}
+
+func timeParams(event types.Event) string {
+	switch {
+	case event.AllDay:
+		return ";VALUE=DATE"
+	case event.TimeZone != "":
+		return ";TZID=" + event.TimeZone
+	default:
+		return ""
+	}
+}
+
+func formatLocalTime(event types.Event, t time.Time) string {
+	if !event.AllDay && event.TimeZone == "" {
+		return formatTime(t)
+	}
+
+	loc, err := storagecommon.LoadLocation(event.TimeZone)
+	if err != nil {
+		return formatTime(t)
+	}
+	if event.AllDay {
+		return t.In(loc).Format(dateLayout)
+	}
+	return t.In(loc).Format(localTimeLayout)
+}
+
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
@@ -0,0 +1,139 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	events := []types.Event{
+		{
+			ID:           "event-1",
+			UserID:       "user1",
+			Title:        "Standup; daily, short",
+			Description:  "Line one\nLine two with a long tail that definitely needs folding at seventy five octets",
+			StartTime:    start,
+			EndTime:      start.Add(15 * time.Minute),
+			NotifyBefore: 600,
+			RRule:        "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+			ExDates:      []time.Time{start.AddDate(0, 0, 7)},
+		},
+	}
+
+	data := Encode(events)
+	for _, line := range strings.Split(strings.TrimSuffix(string(data), "\r\n"), "\r\n") {
+		require.LessOrEqual(t, len(line), maxLineOctets)
+	}
+
+	items, err := Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.NoError(t, items[0].Err)
+	require.Equal(t, "event-1", items[0].UID)
+
+	got := items[0].Event
+	require.Equal(t, events[0].Title, got.Title)
+	require.Equal(t, events[0].Description, got.Description)
+	require.True(t, events[0].StartTime.Equal(got.StartTime))
+	require.True(t, events[0].EndTime.Equal(got.EndTime))
+	require.Equal(t, events[0].NotifyBefore, got.NotifyBefore)
+	require.Equal(t, events[0].RRule, got.RRule)
+	require.Len(t, got.ExDates, 1)
+	require.True(t, events[0].ExDates[0].Equal(got.ExDates[0]))
+}
+
+func TestEncodeDecode_TimeZones(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	zoned := types.Event{
+		ID: "zoned", Title: "Sync", TimeZone: "Europe/Berlin", RRule: "FREQ=WEEKLY",
+		StartTime: time.Date(2025, 3, 24, 9, 0, 0, 0, berlin), EndTime: time.Date(2025, 3, 24, 10, 0, 0, 0, berlin),
+		ExDates: []time.Time{time.Date(2025, 3, 31, 9, 0, 0, 0, berlin)},
+	}
+	allDay := types.Event{
+		ID: "holiday", Title: "Holiday", TimeZone: "Europe/Berlin", AllDay: true,
+		StartTime: time.Date(2025, 6, 2, 0, 0, 0, 0, berlin), EndTime: time.Date(2025, 6, 4, 0, 0, 0, 0, berlin),
+	}
+
+	data := string(Encode([]types.Event{zoned, allDay}))
+	require.Contains(t, data, "DTSTART;TZID=Europe/Berlin:20250324T090000\r\n")
+	require.Contains(t, data, "EXDATE;TZID=Europe/Berlin:20250331T090000\r\n")
+	require.Contains(t, data, "DTSTART;VALUE=DATE:20250602\r\n")
+	require.Contains(t, data, "DTEND;VALUE=DATE:20250604\r\n")
+
+	items, err := Decode(strings.NewReader(data))
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+
+	require.Equal(t, "Europe/Berlin", items[0].Event.TimeZone)
+	require.False(t, items[0].Event.AllDay)
+	require.True(t, items[0].Event.StartTime.Equal(zoned.StartTime))
+	require.True(t, items[0].Event.ExDates[0].Equal(zoned.ExDates[0]))
+
+	require.True(t, items[1].Event.AllDay)
+	require.Equal(t, time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC), items[1].Event.EndTime)
+}
+
+func TestDecode(t *testing.T) {
+	doc := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Google Inc//Google Calendar 70.9054//EN",
+		"BEGIN:VEVENT",
+		"UID:all-day@example.com",
+		"DTSTART;VALUE=DATE:20250610",
+		"SUMMARY:Holiday",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:zoned@example.com",
+		"DTSTART;TZID=UTC:20250611T100000",
+		"DURATION:PT1H30M",
+		"SUMMARY:Plan",
+		" ning",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"TRIGGER:-PT15M",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:broken@example.com",
+		"DTSTART:20250612T100000Z",
+		"RRULE:FREQ=SOMETIMES",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	items, err := Decode(strings.NewReader(doc))
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+
+	require.NoError(t, items[0].Err)
+	require.Equal(t, time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC), items[0].Event.StartTime)
+	require.Equal(t, time.Date(2025, 6, 11, 0, 0, 0, 0, time.UTC), items[0].Event.EndTime)
+
+	require.NoError(t, items[1].Err)
+	require.Equal(t, "Planning", items[1].Event.Title)
+	require.Equal(t, 90*time.Minute, items[1].Event.EndTime.Sub(items[1].Event.StartTime))
+	require.Equal(t, 900, items[1].Event.NotifyBefore)
+
+	require.ErrorIs(t, items[2].Err, ErrInvalidEvent)
+	require.Equal(t, StatusInvalid, ImportStatus(items[2].Err))
+}
+
+func TestDecode_InvalidDocument(t *testing.T) {
+	_, err := Decode(strings.NewReader("BEGIN:VEVENT\r\nEND:VEVENT\r\n"))
+	require.ErrorIs(t, err, ErrInvalidCalendar)
+
+	_, err = Decode(strings.NewReader("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n"))
+	require.ErrorIs(t, err, ErrInvalidCalendar)
+
+	_, err = Decode(strings.NewReader("BEGIN:VCALENDAR\r\ngarbage\r\nEND:VCALENDAR\r\n"))
+	require.ErrorIs(t, err, ErrInvalidCalendar)
+}
@@ -0,0 +1,31 @@
+package ical
+
+import (
+	"errors"
+
+	storagecommon "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
+)
+
+const (
+	StatusCreated   = "created"
+	StatusDuplicate = "duplicate"
+	StatusOverlap   = "overlap"
+	StatusInvalid   = "invalid"
+	StatusFailed    = "failed"
+)
+
+// ImportStatus classifies the outcome of creating a single imported event.
+func ImportStatus(err error) string {
+	switch {
+	case err == nil:
+		return StatusCreated
+	case errors.Is(err, storagecommon.ErrAlreadyExists):
+		return StatusDuplicate
+	case errors.Is(err, storagecommon.ErrConflictOverlap):
+		return StatusOverlap
+	case errors.Is(err, storagecommon.ErrInvalidEvent), errors.Is(err, ErrInvalidEvent):
+		return StatusInvalid
+	default:
+		return StatusFailed
+	}
+}
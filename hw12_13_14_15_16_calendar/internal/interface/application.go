@@ -4,18 +4,31 @@ import (
 	"context"
 	"time"
 
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/changefeed"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/types"
 )
 
 //go:generate mockgen -source=application.go -package=mocks -destination=../../mocks/mock_application.go
 type Application interface {
 	CreateEvent(context.Context, types.Event) (string, error)
-	UpdateEvent(context.Context, types.Event) error
-	DeleteEvent(context.Context, string) error
+	UpdateEvent(context.Context, types.Event) (int64, error)
+	DeleteEvent(ctx context.Context, id string, version int64) error
 	GetEventByID(context.Context, string) (types.Event, error)
-	ListEvents(context.Context) ([]types.Event, error)
+	ListEvents(context.Context, types.ListEventsQuery) (types.EventPage, error)
 	ListEventsByUser(context.Context, string) ([]types.Event, error)
 	ListEventsByUserInRange(context.Context, string, time.Time, time.Time) ([]types.Event, error)
 	DeleteOlderThan(context.Context, time.Time) error
-	ListEventsDueBefore(context.Context, time.Time) ([]types.Event, error)
+	WatchEvents(context.Context, string, string) (*changefeed.Subscription, error)
+	FreeBusy(context.Context, types.FreeBusyQuery) (types.FreeBusy, error)
+
+	InviteAttendee(context.Context, types.Attendee) error
+	RespondToInvitation(context.Context, types.Attendee) error
+	RemoveAttendee(ctx context.Context, eventID, userID string) error
+	ListAttendees(ctx context.Context, eventID string) ([]types.Attendee, error)
+	ListNotifications(ctx context.Context, eventID string) ([]types.Notification, error)
+
+	ScheduleNotifications(ctx context.Context, from, to time.Time) (int, error)
+	PendingNotifications(ctx context.Context, resendBefore time.Time, limit int) ([]types.Notification, error)
+	MarkNotificationPublished(ctx context.Context, id string) error
+	RecordNotificationStatus(ctx context.Context, status types.NotificationStatus) error
 }
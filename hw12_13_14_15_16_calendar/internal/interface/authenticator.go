@@ -0,0 +1,8 @@
+package interfaces
+
+import "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/auth"
+
+//go:generate mockgen -source=authenticator.go -package=mocks -destination=../../mocks/mock_authenticator.go
+type Authenticator interface {
+	Authenticate(token string) (auth.Identity, error)
+}
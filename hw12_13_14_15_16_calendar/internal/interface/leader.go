@@ -0,0 +1,19 @@
+package interfaces
+
+import "context"
+
+// LeaderLock is held by at most one scheduler replica at a time.
+//
+//go:generate mockgen -source=leader.go -package=mocks -destination=../../mocks/mock_leader.go
+type LeaderLock interface {
+	// TryLock takes the lock without waiting and reports whether it did.
+	TryLock(ctx context.Context) (bool, error)
+	// Check fails once the lock may have been lost, e.g. with the database session that held it.
+	Check(ctx context.Context) error
+	Unlock(ctx context.Context) error
+}
+
+// Leader tells whether this replica is the one to run the periodic jobs.
+type Leader interface {
+	IsLeader() bool
+}
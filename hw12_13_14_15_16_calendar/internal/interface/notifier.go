@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/rmq"
+)
+
+//go:generate mockgen -source=notifier.go -package=mocks -destination=../../mocks/mock_notifier.go
+type Notifier interface {
+	Notify(ctx context.Context, notification rmq.Notification) error
+}
@@ -1,8 +1,15 @@
 package interfaces
 
+import (
+	"context"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/rmq"
+)
+
 //go:generate mockgen -source=rmq_client.go -package=mocks -destination=../../mocks/mock_rmq_client.go
 type RmqClient interface {
-	Publish(routingKey string, body []byte) error
+	Publish(ctx context.Context, routingKey string, body []byte) error
 	Close() error
-	Consume(queueName string) (<-chan []byte, error)
+	Consume(queueName string) (<-chan rmq.Delivery, error)
+	State() rmq.State
 }
@@ -1,20 +1,57 @@
 package interfaces
 
 import (
+	"context"
 	"time"
 
 	storagecommon "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
 )
 
+// Storage methods fail with the context's error once it is canceled or its deadline passes.
+//
 //go:generate mockgen -source=storage.go -package=mocks -destination=../../mocks/mock_storage.go
 type Storage interface {
-	Create(event storagecommon.Event) (string, error)
-	Update(event storagecommon.Event) error
-	Delete(id string) error
-	DeleteOlder(t time.Time) error
+	Create(ctx context.Context, event storagecommon.Event) (string, error)
+	// Update and Delete reject a non-zero version that differs from the stored one;
+	// Update returns the new version.
+	Update(ctx context.Context, event storagecommon.Event) (int64, error)
+	Delete(ctx context.Context, id string, version int64) error
+	DeleteOlder(ctx context.Context, t time.Time) error
 
-	GetByID(id string) (storagecommon.Event, error)
-	List() ([]storagecommon.Event, error)
-	ListByUser(userID string) ([]storagecommon.Event, error)
-	ListByUserInRange(userID string, from, to time.Time) ([]storagecommon.Event, error)
+	GetByID(ctx context.Context, id string) (storagecommon.Event, error)
+	List(ctx context.Context) ([]storagecommon.Event, error)
+	ListPage(ctx context.Context, query storagecommon.ListQuery) (storagecommon.EventPage, error)
+	ListByUser(ctx context.Context, userID string) ([]storagecommon.Event, error)
+	ListByUserInRange(ctx context.Context, userID string, from, to time.Time) ([]storagecommon.Event, error)
+	// ListDueOccurrences returns the occurrences, series expanded, whose reminder is due in [from, to),
+	// the earliest reminder first.
+	ListDueOccurrences(ctx context.Context, from, to time.Time) ([]storagecommon.Event, error)
+
+	AddAttendee(ctx context.Context, attendee storagecommon.Attendee) error
+	UpdateAttendee(ctx context.Context, attendee storagecommon.Attendee) error
+	RemoveAttendee(ctx context.Context, eventID, userID string) error
+	ListAttendees(ctx context.Context, eventID string) ([]storagecommon.Attendee, error)
+
+	// AddNotifications stores pending notifications and returns how many were added; it skips
+	// occurrences that already have one and events that no longer exist.
+	AddNotifications(ctx context.Context, notifications []storagecommon.Notification) (int, error)
+	GetNotification(ctx context.Context, id string) (storagecommon.Notification, error)
+	// ListNotifications returns the notifications about the occurrences of an event, the earliest first.
+	ListNotifications(ctx context.Context, eventID string) ([]storagecommon.Notification, error)
+	// ListPendingNotifications returns up to limit notifications to publish, the earliest NotifyAt first:
+	// the pending ones and the published ones last updated before resendBefore.
+	ListPendingNotifications(
+		ctx context.Context,
+		resendBefore time.Time,
+		limit int,
+	) ([]storagecommon.Notification, error)
+	// SetNotificationState moves the notification to a later state or, given the current one, updates
+	// its time; an earlier state is ignored.
+	SetNotificationState(ctx context.Context, id, state string, at time.Time) error
+
+	// AddNotificationStatus records a status reported for a notification; a delivered status also moves
+	// the notification to NotificationDelivered.
+	AddNotificationStatus(ctx context.Context, status storagecommon.NotificationStatus) error
+	// ListNotificationStatuses returns the statuses reported for the notifications of an event, oldest first.
+	ListNotificationStatuses(ctx context.Context, eventID string) ([]storagecommon.NotificationStatus, error)
 }
@@ -0,0 +1,101 @@
+package leader
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	i "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/interface"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/metrics"
+)
+
+const (
+	// DefaultInterval is how often a follower campaigns and the leader checks its lock.
+	DefaultInterval = 5 * time.Second
+	// releaseTimeout bounds the release of the lock on shutdown, when the context is already done.
+	releaseTimeout = 5 * time.Second
+)
+
+// Elector campaigns for the lock on every interval. A replica that crashes releases the lock with its
+// database session, so a follower takes over within an interval; a leader that cannot confirm it still
+// holds the lock steps down.
+type Elector struct {
+	lock     i.LeaderLock
+	interval time.Duration
+	logger   i.Logger
+	leading  atomic.Bool
+}
+
+func NewElector(lock i.LeaderLock, interval time.Duration, logger i.Logger) *Elector {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Elector{lock: lock, interval: interval, logger: logger}
+}
+
+func (e *Elector) IsLeader() bool {
+	return e.leading.Load()
+}
+
+// Run campaigns until the context is done and then releases the lock.
+func (e *Elector) Run(ctx context.Context) error {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	e.campaign(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			e.resign()
+			return ctx.Err()
+		case <-ticker.C:
+			e.campaign(ctx)
+		}
+	}
+}
+
+func (e *Elector) campaign(ctx context.Context) {
+	if e.IsLeader() {
+		if err := e.lock.Check(ctx); err != nil {
+			e.setLeading(false, "lost")
+			e.logger.Warnf("Lost scheduler leadership: %v", err)
+			_ = e.lock.Unlock(ctx)
+		}
+		return
+	}
+
+	acquired, err := e.lock.TryLock(ctx)
+	if err != nil {
+		e.logger.Warnf("Failed to campaign for scheduler leadership: %v", err)
+		return
+	}
+	if acquired {
+		e.setLeading(true, "acquired")
+		e.logger.Infof("Became scheduler leader")
+	}
+}
+
+func (e *Elector) resign() {
+	if !e.IsLeader() {
+		return
+	}
+	e.setLeading(false, "released")
+
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+	if err := e.lock.Unlock(ctx); err != nil {
+		e.logger.Warnf("Failed to release scheduler leadership: %v", err)
+		return
+	}
+	e.logger.Infof("Released scheduler leadership")
+}
+
+func (e *Elector) setLeading(leading bool, change string) {
+	e.leading.Store(leading)
+	if leading {
+		metrics.SchedulerLeader.Set(1)
+	} else {
+		metrics.SchedulerLeader.Set(0)
+	}
+	metrics.LeadershipChanges.WithLabelValues(change).Inc()
+}
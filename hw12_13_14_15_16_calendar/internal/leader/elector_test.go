@@ -0,0 +1,87 @@
+package leader_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/leader"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/mocks"
+	"github.com/golang/mock/gomock" //nolint:depguard
+	"github.com/stretchr/testify/require"
+)
+
+func TestElector_Failover(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := mocks.NewMockLogger(gomock.NewController(t))
+	logger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+	logger.EXPECT().Warnf(gomock.Any(), gomock.Any()).AnyTimes()
+
+	const key = 1
+	first := leader.NewElector(leader.NewMemoryLock(key), time.Millisecond, logger)
+	second := leader.NewElector(leader.NewMemoryLock(key), time.Millisecond, logger)
+
+	firstCtx, stopFirst := context.WithCancel(ctx)
+	firstDone := make(chan error)
+	go func() {
+		firstDone <- first.Run(firstCtx)
+	}()
+	require.Eventually(t, first.IsLeader, time.Second, time.Millisecond)
+
+	secondDone := make(chan error)
+	go func() {
+		secondDone <- second.Run(ctx)
+	}()
+	require.Never(t, second.IsLeader, 20*time.Millisecond, time.Millisecond)
+
+	stopFirst()
+	require.ErrorIs(t, <-firstDone, context.Canceled)
+	require.False(t, first.IsLeader())
+	require.Eventually(t, second.IsLeader, time.Second, time.Millisecond)
+
+	cancel()
+	require.ErrorIs(t, <-secondDone, context.Canceled)
+	require.False(t, second.IsLeader())
+}
+
+func TestElector_StepsDownWhenLockIsLost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	lock := mocks.NewMockLeaderLock(ctrl)
+	logger := mocks.NewMockLogger(ctrl)
+	logger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+	logger.EXPECT().Warnf(gomock.Any(), gomock.Any()).AnyTimes()
+
+	lost := make(chan struct{})
+	retried := make(chan struct{})
+	gomock.InOrder(
+		lock.EXPECT().TryLock(gomock.Any()).Return(false, errors.New("connection refused")),
+		lock.EXPECT().TryLock(gomock.Any()).Return(true, nil),
+		lock.EXPECT().Check(gomock.Any()).Return(nil),
+		lock.EXPECT().Check(gomock.Any()).Return(errors.New("connection reset")),
+		lock.EXPECT().Unlock(gomock.Any()).DoAndReturn(func(context.Context) error {
+			close(lost)
+			return nil
+		}),
+		lock.EXPECT().TryLock(gomock.Any()).DoAndReturn(func(context.Context) (bool, error) {
+			close(retried)
+			return false, nil
+		}),
+		lock.EXPECT().TryLock(gomock.Any()).Return(false, nil).AnyTimes(),
+	)
+
+	elector := leader.NewElector(lock, time.Millisecond, logger)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- elector.Run(ctx)
+	}()
+
+	<-lost
+	<-retried
+	require.False(t, elector.IsLeader())
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+}
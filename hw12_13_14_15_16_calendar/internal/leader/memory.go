@@ -0,0 +1,62 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrNotHeld is returned by Check and Unlock when the lock is held by another owner or by nobody.
+var ErrNotHeld = errors.New("leader lock is not held")
+
+var memoryLocks = struct {
+	mu     sync.Mutex
+	owners map[int64]*MemoryLock
+}{owners: make(map[int64]*MemoryLock)}
+
+// MemoryLock is a lock shared by the instances in one process. It stands in for the database lock
+// when the storage has none, so it does not exclude the schedulers of other processes.
+type MemoryLock struct {
+	key int64
+}
+
+func NewMemoryLock(key int64) *MemoryLock {
+	return &MemoryLock{key: key}
+}
+
+func (l *MemoryLock) TryLock(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	memoryLocks.mu.Lock()
+	defer memoryLocks.mu.Unlock()
+
+	owner, taken := memoryLocks.owners[l.key]
+	if taken {
+		return owner == l, nil
+	}
+	memoryLocks.owners[l.key] = l
+	return true, nil
+}
+
+func (l *MemoryLock) Check(_ context.Context) error {
+	memoryLocks.mu.Lock()
+	defer memoryLocks.mu.Unlock()
+
+	if memoryLocks.owners[l.key] != l {
+		return ErrNotHeld
+	}
+	return nil
+}
+
+func (l *MemoryLock) Unlock(_ context.Context) error {
+	memoryLocks.mu.Lock()
+	defer memoryLocks.mu.Unlock()
+
+	if memoryLocks.owners[l.key] != l {
+		return ErrNotHeld
+	}
+	delete(memoryLocks.owners, l.key)
+	return nil
+}
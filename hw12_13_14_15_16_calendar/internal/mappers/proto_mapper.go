@@ -3,6 +3,7 @@ package mappers
 import (
 	"time"
 
+	storagecommon "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/types"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/proto/calendar"
 )
@@ -16,6 +17,11 @@ func ProtoToDomain(event *calendar.Event) types.Event {
 		StartTime:    time.Unix(event.StartTime, 0),
 		EndTime:      time.Unix(event.EndTime, 0),
 		NotifyBefore: int(event.NotifyBefore),
+		RRule:        event.Rrule,
+		ExDates:      UnixToTimes(event.Exdates),
+		TimeZone:     event.TimeZone,
+		AllDay:       event.AllDay,
+		Version:      event.Version,
 	}
 }
 
@@ -28,5 +34,78 @@ func DomainToProto(event types.Event) *calendar.Event {
 		StartTime:    event.StartTime.Unix(),
 		EndTime:      event.EndTime.Unix(),
 		NotifyBefore: int64(event.NotifyBefore),
+		Rrule:        event.RRule,
+		Exdates:      TimesToUnix(event.ExDates),
+		TimeZone:     event.TimeZone,
+		AllDay:       event.AllDay,
+		Version:      event.Version,
+	}
+}
+
+func UnixToTimes(values []int64) []time.Time {
+	if len(values) == 0 {
+		return nil
+	}
+	times := make([]time.Time, 0, len(values))
+	for _, v := range values {
+		times = append(times, time.Unix(v, 0))
+	}
+	return times
+}
+
+func TimesToUnix(times []time.Time) []int64 {
+	if len(times) == 0 {
+		return nil
+	}
+	values := make([]int64, 0, len(times))
+	for _, t := range times {
+		values = append(values, t.Unix())
+	}
+	return values
+}
+
+var attendeeStatuses = map[string]calendar.AttendeeStatus{
+	storagecommon.AttendeeNeedsAction: calendar.AttendeeStatus_ATTENDEE_STATUS_NEEDS_ACTION,
+	storagecommon.AttendeeAccepted:    calendar.AttendeeStatus_ATTENDEE_STATUS_ACCEPTED,
+	storagecommon.AttendeeDeclined:    calendar.AttendeeStatus_ATTENDEE_STATUS_DECLINED,
+	storagecommon.AttendeeTentative:   calendar.AttendeeStatus_ATTENDEE_STATUS_TENTATIVE,
+}
+
+// AttendeeStatusFromProto returns false for ATTENDEE_STATUS_UNSPECIFIED and unknown values.
+func AttendeeStatusFromProto(s calendar.AttendeeStatus) (string, bool) {
+	for status, value := range attendeeStatuses {
+		if value == s {
+			return status, true
+		}
+	}
+	return "", false
+}
+
+func AttendeeToProto(a types.Attendee) *calendar.Attendee {
+	return &calendar.Attendee{
+		EventId: a.EventID,
+		UserId:  a.UserID,
+		Status:  attendeeStatuses[a.Status],
+	}
+}
+
+var notificationStates = map[string]calendar.NotificationState{
+	storagecommon.NotificationPending:   calendar.NotificationState_NOTIFICATION_STATE_PENDING,
+	storagecommon.NotificationPublished: calendar.NotificationState_NOTIFICATION_STATE_PUBLISHED,
+	storagecommon.NotificationDelivered: calendar.NotificationState_NOTIFICATION_STATE_DELIVERED,
+}
+
+func NotificationToProto(n types.Notification) *calendar.Notification {
+	statuses := make([]*calendar.NotificationStatus, 0, len(n.Statuses))
+	for _, s := range n.Statuses {
+		statuses = append(statuses, &calendar.NotificationStatus{Status: s.Status, Timestamp: s.ReportedAt.Unix()})
+	}
+	return &calendar.Notification{
+		Id:         n.ID,
+		EventId:    n.EventID,
+		Occurrence: n.Occurrence.Unix(),
+		NotifyAt:   n.NotifyAt.Unix(),
+		State:      notificationStates[n.State],
+		Statuses:   statuses,
 	}
 }
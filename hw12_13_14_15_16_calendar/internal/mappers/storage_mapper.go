@@ -5,7 +5,12 @@ import (
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/types"
 )
 
+// ToDomainEvent returns the times of an event with a time zone in that zone.
 func ToDomainEvent(e storagecommon.Event) types.Event {
+	if e.TimeZone != "" {
+		loc := e.Location()
+		e.StartTime, e.EndTime = e.StartTime.In(loc), e.EndTime.In(loc)
+	}
 	return types.Event{
 		ID:           e.ID,
 		Title:        e.Title,
@@ -14,6 +19,11 @@ func ToDomainEvent(e storagecommon.Event) types.Event {
 		EndTime:      e.EndTime,
 		UserID:       e.UserID,
 		NotifyBefore: e.NotifyBefore,
+		RRule:        e.RRule,
+		ExDates:      e.ExDates,
+		TimeZone:     e.TimeZone,
+		AllDay:       e.AllDay,
+		Version:      e.Version,
 	}
 }
 
@@ -34,5 +44,89 @@ func FromDomainEvent(e types.Event) storagecommon.Event {
 		EndTime:      e.EndTime,
 		UserID:       e.UserID,
 		NotifyBefore: e.NotifyBefore,
+		RRule:        e.RRule,
+		ExDates:      e.ExDates,
+		TimeZone:     e.TimeZone,
+		AllDay:       e.AllDay,
+		Version:      e.Version,
+	}
+}
+
+func FromDomainListQuery(q types.ListEventsQuery) storagecommon.ListQuery {
+	return storagecommon.ListQuery{
+		UserID:    q.UserID,
+		Title:     q.Title,
+		From:      q.From,
+		To:        q.To,
+		PageSize:  q.PageSize,
+		PageToken: q.PageToken,
+	}
+}
+
+func ToDomainEventPage(p storagecommon.EventPage) types.EventPage {
+	return types.EventPage{
+		Events:        ToDomainEvents(p.Events),
+		NextPageToken: p.NextPageToken,
+	}
+}
+
+func ToDomainAttendees(attendees []storagecommon.Attendee) []types.Attendee {
+	result := make([]types.Attendee, 0, len(attendees))
+	for _, a := range attendees {
+		result = append(result, types.Attendee{EventID: a.EventID, UserID: a.UserID, Status: a.Status})
+	}
+	return result
+}
+
+func FromDomainAttendee(a types.Attendee) storagecommon.Attendee {
+	return storagecommon.Attendee{EventID: a.EventID, UserID: a.UserID, Status: a.Status}
+}
+
+// ToDomainNotification returns the times of the occurrence in the time zone of its event.
+func ToDomainNotification(n storagecommon.Notification) types.Notification {
+	if n.TimeZone != "" {
+		loc := storagecommon.Event{TimeZone: n.TimeZone}.Location()
+		n.Occurrence, n.NotifyAt = n.Occurrence.In(loc), n.NotifyAt.In(loc)
+	}
+	return types.Notification{
+		ID:          n.ID,
+		EventID:     n.EventID,
+		UserID:      n.UserID,
+		Title:       n.Title,
+		Description: n.Description,
+		Occurrence:  n.Occurrence,
+		NotifyAt:    n.NotifyAt,
+		TimeZone:    n.TimeZone,
+		AllDay:      n.AllDay,
+		State:       n.State,
+		UpdatedAt:   n.UpdatedAt,
+	}
+}
+
+func ToDomainNotifications(notifications []storagecommon.Notification) []types.Notification {
+	result := make([]types.Notification, 0, len(notifications))
+	for _, n := range notifications {
+		result = append(result, ToDomainNotification(n))
+	}
+	return result
+}
+
+func ToDomainNotificationStatus(s storagecommon.NotificationStatus) types.NotificationStatus {
+	return types.NotificationStatus{
+		NotificationID: s.NotificationID,
+		EventID:        s.EventID,
+		UserID:         s.UserID,
+		Status:         s.Status,
+		ReportedAt:     s.ReportedAt,
+	}
+}
+
+func FromDomainNotificationStatus(s types.NotificationStatus) storagecommon.NotificationStatus {
+	return storagecommon.NotificationStatus{
+		NotificationID: s.NotificationID,
+		EventID:        s.EventID,
+		UserID:         s.UserID,
+		Status:         s.Status,
+		ReportedAt:     s.ReportedAt,
 	}
 }
@@ -0,0 +1,111 @@
+// Package metrics holds the Prometheus collectors of the calendar services. They are registered with the
+// default registry, which Handler exposes.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"          //nolint:depguard
+	"github.com/prometheus/client_golang/prometheus/promauto" //nolint:depguard
+	"github.com/prometheus/client_golang/prometheus/promhttp" //nolint:depguard
+)
+
+const namespace = "calendar"
+
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests by route, method and status code.",
+	}, []string{"route", "method", "code"})
+	HTTPDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by route and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	GRPCRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "requests_total",
+		Help:      "gRPC calls by method and status code.",
+	}, []string{"method", "code"})
+	GRPCDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "request_duration_seconds",
+		Help:      "gRPC call latency by method; streams are measured until they end.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	StorageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "storage",
+		Name:      "operation_duration_seconds",
+		Help:      "Storage operation latency by operation and result: ok or error.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "result"})
+
+	NotificationsPublished = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "notifications_published_total",
+		Help:      "Notifications confirmed by the broker.",
+	})
+	NotificationPublishFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "notification_publish_failures_total",
+		Help: "Notifications that failed to be published by reason: " +
+			"unroutable, rejected, unconfirmed, disconnected or error.",
+	}, []string{"reason"})
+	SchedulerTickDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "tick_duration_seconds",
+		Help:      "Time the leader spends scheduling, publishing and cleaning up on a tick.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	})
+	SchedulerLeader = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "leader",
+		Help:      "Whether this scheduler replica is the leader.",
+	})
+	LeadershipChanges = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "leadership_changes_total",
+		Help:      "Leadership changes of this scheduler replica by kind: acquired, lost or released.",
+	}, []string{"change"})
+
+	NotificationsSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "sender",
+		Name:      "notifications_total",
+		Help:      "Notifications handled by the sender by result: delivered, duplicate, failed or malformed.",
+	}, []string{"result"})
+
+	ConsumeLag = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "rmq",
+		Name:      "consume_lag_seconds",
+		Help:      "Time between publishing a message and handing it to the consumer, by queue.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 300},
+	}, []string{"queue"})
+)
+
+// Result labels an outcome as ok or error.
+func Result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,75 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/rmq"
+)
+
+const (
+	ChannelFile    = "file"
+	ChannelSMTP    = "smtp"
+	ChannelWebhook = "webhook"
+)
+
+var (
+	ErrUnknownChannel = errors.New("unknown notification channel")
+	ErrNoAddress      = errors.New("no address to send the notification to")
+)
+
+// Channel delivers a notification to an address whose meaning depends on the channel: an email
+// address for SMTP, a URL for webhooks. An empty address selects the channel's default, if it has one.
+type Channel interface {
+	Send(ctx context.Context, address string, notification rmq.Notification) error
+}
+
+// Route sends the notifications of a user through the named channel to the address.
+type Route struct {
+	Channel string
+	Address string
+}
+
+// Router sends every notification through the channel chosen for its user.
+type Router struct {
+	channels map[string]Channel
+	routes   map[string]Route
+	fallback Route
+}
+
+// NewRouter checks that the default channel and every route refer to one of the channels.
+func NewRouter(channels map[string]Channel, fallback string, routes map[string]Route) (*Router, error) {
+	if _, ok := channels[fallback]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownChannel, fallback)
+	}
+	for userID, route := range routes {
+		if _, ok := channels[route.Channel]; !ok {
+			return nil, fmt.Errorf("%w: %q for user %s", ErrUnknownChannel, route.Channel, userID)
+		}
+	}
+	return &Router{channels: channels, routes: routes, fallback: Route{Channel: fallback}}, nil
+}
+
+func (r *Router) Notify(ctx context.Context, notification rmq.Notification) error {
+	route, ok := r.routes[notification.UserID]
+	if !ok {
+		route = r.fallback
+	}
+	if err := r.channels[route.Channel].Send(ctx, route.Address, notification); err != nil {
+		return fmt.Errorf("%s: %w", route.Channel, err)
+	}
+	return nil
+}
+
+// Close closes the channels that hold resources.
+func (r *Router) Close() error {
+	var errs []error
+	for _, channel := range r.channels {
+		if closer, ok := channel.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
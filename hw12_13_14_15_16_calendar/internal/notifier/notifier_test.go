@@ -0,0 +1,88 @@
+package notifier_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/notifier"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/rmq"
+	"github.com/stretchr/testify/require"
+)
+
+type sent struct {
+	channel string
+	address string
+	id      string
+}
+
+type recordingChannel struct {
+	name string
+	log  *[]sent
+	err  error
+}
+
+func (c recordingChannel) Send(_ context.Context, address string, notification rmq.Notification) error {
+	*c.log = append(*c.log, sent{channel: c.name, address: address, id: notification.ID})
+	return c.err
+}
+
+func TestRouter(t *testing.T) {
+	var log []sent
+	channels := map[string]notifier.Channel{
+		notifier.ChannelFile:    recordingChannel{name: notifier.ChannelFile, log: &log},
+		notifier.ChannelSMTP:    recordingChannel{name: notifier.ChannelSMTP, log: &log},
+		notifier.ChannelWebhook: recordingChannel{name: notifier.ChannelWebhook, log: &log, err: errors.New("timeout")},
+	}
+	routes := map[string]notifier.Route{
+		"alice": {Channel: notifier.ChannelSMTP, Address: "alice@example.com"},
+		"bob":   {Channel: notifier.ChannelWebhook, Address: "https://bob.example.com/hook"},
+	}
+
+	router, err := notifier.NewRouter(channels, notifier.ChannelFile, routes)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, router.Notify(ctx, rmq.Notification{ID: "1", UserID: "alice"}))
+	require.NoError(t, router.Notify(ctx, rmq.Notification{ID: "2", UserID: "carol"}))
+	require.ErrorContains(t, router.Notify(ctx, rmq.Notification{ID: "3", UserID: "bob"}), "webhook: timeout")
+	require.Equal(t, []sent{
+		{channel: notifier.ChannelSMTP, address: "alice@example.com", id: "1"},
+		{channel: notifier.ChannelFile, id: "2"},
+		{channel: notifier.ChannelWebhook, address: "https://bob.example.com/hook", id: "3"},
+	}, log)
+
+	_, err = notifier.NewRouter(channels, "sms", nil)
+	require.ErrorIs(t, err, notifier.ErrUnknownChannel)
+	_, err = notifier.NewRouter(channels, notifier.ChannelFile, map[string]notifier.Route{"dave": {Channel: "sms"}})
+	require.ErrorIs(t, err, notifier.ErrUnknownChannel)
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.log")
+
+	file, err := notifier.NewFile(path)
+	require.NoError(t, err)
+	for _, id := range []string{"1", "2"} {
+		require.NoError(t, file.Send(context.Background(), "", rmq.Notification{ID: id, UserID: "alice"}))
+	}
+	require.NoError(t, file.Close())
+
+	written, err := os.Open(path)
+	require.NoError(t, err)
+	defer written.Close()
+
+	ids := make([]string, 0)
+	scanner := bufio.NewScanner(written)
+	for scanner.Scan() {
+		var notification rmq.Notification
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &notification))
+		ids = append(ids, notification.ID)
+	}
+	require.NoError(t, scanner.Err())
+	require.Equal(t, []string{"1", "2"}, ids)
+}
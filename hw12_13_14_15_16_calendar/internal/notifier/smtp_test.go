@@ -0,0 +1,124 @@
+package notifier_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/notifier"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/rmq"
+	"github.com/stretchr/testify/require"
+)
+
+type mail struct {
+	from string
+	to   []string
+	data string
+}
+
+// fakeSMTP accepts one session per connection and records the messages; recipients in reject are
+// refused.
+func fakeSMTP(t *testing.T, reject string) (host, port string, mails <-chan mail) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan mail, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(conn, reject, received)
+		}
+	}()
+
+	host, port, err = net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	return host, port, received
+}
+
+func serveSMTP(conn net.Conn, reject string, received chan<- mail) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	reply := func(line string) { _ = text.PrintfLine("%s", line) }
+
+	var current mail
+	reply("220 localhost ESMTP fake")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch command {
+		case "EHLO", "HELO":
+			reply("250 localhost")
+		case "MAIL":
+			current = mail{from: strings.TrimSuffix(strings.TrimPrefix(line, "MAIL FROM:<"), ">")}
+			reply("250 OK")
+		case "RCPT":
+			to := strings.TrimSuffix(strings.TrimPrefix(line, "RCPT TO:<"), ">")
+			if to == reject {
+				reply("550 no such user")
+				continue
+			}
+			current.to = append(current.to, to)
+			reply("250 OK")
+		case "DATA":
+			reply("354 end data with <CR><LF>.<CR><LF>")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			current.data = string(data)
+			received <- current
+			reply("250 OK")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func TestSMTP(t *testing.T) {
+	host, port, mails := fakeSMTP(t, "nobody@example.com")
+
+	smtp, err := notifier.NewSMTP(notifier.SMTPConfig{
+		Host: host, Port: port, From: "calendar@example.com", Timeout: time.Second,
+	})
+	require.NoError(t, err)
+
+	notification := rmq.Notification{
+		ID: "notification-1", Title: "Team Meeting", Description: "Discuss roadmap",
+		Time: "2025-06-02T09:00:00Z", TimeZone: "Europe/Moscow",
+	}
+	require.NoError(t, smtp.Send(context.Background(), "alice@example.com", notification))
+
+	got := <-mails
+	require.Equal(t, "calendar@example.com", got.from)
+	require.Equal(t, []string{"alice@example.com"}, got.to)
+
+	message, err := textproto.NewReader(bufio.NewReader(strings.NewReader(got.data))).ReadMIMEHeader()
+	require.NoError(t, err)
+	require.Equal(t, "Reminder: Team Meeting", message.Get("Subject"))
+	require.Equal(t, "<notification-1@calendar>", message.Get("Message-Id"))
+	require.Contains(t, got.data, "Team Meeting starts at 2025-06-02T09:00:00Z (Europe/Moscow).")
+	require.Contains(t, got.data, "Discuss roadmap")
+
+	err = smtp.Send(context.Background(), "nobody@example.com", notification)
+	require.ErrorContains(t, err, "550")
+	require.ErrorIs(t, smtp.Send(context.Background(), "", notification), notifier.ErrNoAddress)
+
+	_, err = notifier.NewSMTP(notifier.SMTPConfig{Host: host})
+	require.Error(t, err)
+}
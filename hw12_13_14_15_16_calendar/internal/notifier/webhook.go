@@ -0,0 +1,93 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/rmq"
+)
+
+const (
+	defaultWebhookTimeout = 10 * time.Second
+
+	// SignatureHeader carries "sha256=" and the hex HMAC of the timestamp, a dot and the body.
+	SignatureHeader = "X-Calendar-Signature"
+	TimestampHeader = "X-Calendar-Timestamp"
+)
+
+type WebhookConfig struct {
+	URL     string
+	Secret  string
+	Timeout time.Duration
+}
+
+// Webhook posts notifications as JSON to the address, or to the configured URL when the address is empty.
+// The request is signed so that the receiver can check where it comes from; a response outside
+// the 2xx range fails the delivery.
+type Webhook struct {
+	cfg    WebhookConfig
+	client *http.Client
+}
+
+func NewWebhook(cfg WebhookConfig) (*Webhook, error) {
+	if cfg.Secret == "" {
+		return nil, fmt.Errorf("webhook secret is required")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultWebhookTimeout
+	}
+	return &Webhook{cfg: cfg, client: &http.Client{Timeout: cfg.Timeout}}, nil
+}
+
+func (wh *Webhook) Send(ctx context.Context, address string, notification rmq.Notification) error {
+	if address == "" {
+		address = wh.cfg.URL
+	}
+	if address == "" {
+		return ErrNoAddress
+	}
+
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, address, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(wh.cfg.Secret, timestamp, body))
+
+	resp, err := wh.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the signature a webhook request with the timestamp and body carries.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,56 @@
+package notifier_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/notifier"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/rmq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhook(t *testing.T) {
+	received := make(chan rmq.Notification, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		signature := notifier.Sign("secret", r.Header.Get(notifier.TimestampHeader), body)
+		if r.Header.Get(notifier.SignatureHeader) != signature {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+
+		var notification rmq.Notification
+		require.NoError(t, json.Unmarshal(body, &notification))
+		received <- notification
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook, err := notifier.NewWebhook(notifier.WebhookConfig{URL: server.URL + "/default", Secret: "secret"})
+	require.NoError(t, err)
+
+	notification := rmq.Notification{ID: "notification-1", UserID: "alice", Title: "Team Meeting"}
+	require.NoError(t, webhook.Send(context.Background(), "", notification))
+	require.Equal(t, notification, <-received)
+
+	err = webhook.Send(context.Background(), server.URL+"/gone", notification)
+	require.ErrorContains(t, err, "status 410")
+
+	forged, err := notifier.NewWebhook(notifier.WebhookConfig{URL: server.URL, Secret: "guess"})
+	require.NoError(t, err)
+	require.ErrorContains(t, forged.Send(context.Background(), "", notification), "status 401")
+
+	unset, err := notifier.NewWebhook(notifier.WebhookConfig{Secret: "secret"})
+	require.NoError(t, err)
+	require.ErrorIs(t, unset.Send(context.Background(), "", notification), notifier.ErrNoAddress)
+}
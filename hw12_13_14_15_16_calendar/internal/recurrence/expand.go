@@ -0,0 +1,206 @@
+package recurrence
+
+import (
+	"sort"
+	"time"
+)
+
+// maxPeriods guards against rules whose BYDAY filter never matches.
+const maxPeriods = 100000
+
+// Between returns the start times of the occurrences of a series beginning at dtstart
+// that fall into [after, before]. Dates listed in exdates are skipped, but still count
+// towards COUNT as required by RFC 5545. Calendar arithmetic is done in dtstart's location,
+// so occurrences keep their wall clock time across daylight saving shifts.
+func (r Rule) Between(dtstart time.Time, exdates []time.Time, after, before time.Time) []time.Time {
+	if before.Before(dtstart) || (!r.Until.IsZero() && after.After(r.Until)) {
+		return nil
+	}
+
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	start := 0
+	if r.Count == 0 {
+		start = r.periodsBefore(dtstart, after, interval)
+	}
+
+	result := make([]time.Time, 0)
+	count := 0
+	for period := start; period < start+maxPeriods; period++ {
+		for _, t := range r.candidates(dtstart, period*interval) {
+			if t.Before(dtstart) {
+				continue
+			}
+			if t.After(before) || (!r.Until.IsZero() && t.After(r.Until)) {
+				return result
+			}
+
+			count++
+			if !t.Before(after) && !isExcluded(t, exdates) {
+				result = append(result, t)
+			}
+			if r.Count > 0 && count >= r.Count {
+				return result
+			}
+		}
+	}
+	return result
+}
+
+// Last returns the start of the final occurrence of a bounded series.
+func (r Rule) Last(dtstart time.Time) (time.Time, bool) {
+	if !r.Bounded() {
+		return time.Time{}, false
+	}
+
+	var occurrences []time.Time
+	if r.Count > 0 {
+		occurrences = r.Between(dtstart, nil, dtstart, time.Unix(1<<62, 0))
+	} else {
+		occurrences = r.Between(dtstart, nil, r.Until.AddDate(-r.Interval-1, 0, 0), r.Until)
+		if len(occurrences) == 0 {
+			occurrences = r.Between(dtstart, nil, dtstart, r.Until)
+		}
+	}
+
+	if len(occurrences) == 0 {
+		return dtstart, true
+	}
+	return occurrences[len(occurrences)-1], true
+}
+
+func (r Rule) candidates(dtstart time.Time, offset int) []time.Time {
+	switch r.Freq {
+	case Daily:
+		day := dtstart.AddDate(0, 0, offset)
+		if len(r.ByDay) > 0 && !r.hasWeekday(day.Weekday()) {
+			return nil
+		}
+		return []time.Time{day}
+	case Weekly:
+		weekStart := dtstart.AddDate(0, 0, -mondayOffset(dtstart.Weekday())+7*offset)
+		if len(r.ByDay) == 0 {
+			return []time.Time{weekStart.AddDate(0, 0, mondayOffset(dtstart.Weekday()))}
+		}
+		days := make([]time.Time, 0, len(r.ByDay))
+		for _, d := range r.ByDay {
+			days = append(days, weekStart.AddDate(0, 0, mondayOffset(d.Weekday)))
+		}
+		return sortUnique(days)
+	case Monthly:
+		return r.monthCandidates(dtstart, offset)
+	case Yearly:
+		h, m, s := dtstart.Clock()
+		day := time.Date(dtstart.Year()+offset, dtstart.Month(), dtstart.Day(), h, m, s, dtstart.Nanosecond(),
+			dtstart.Location())
+		if day.Month() != dtstart.Month() {
+			return nil
+		}
+		return []time.Time{day}
+	default:
+		return nil
+	}
+}
+
+func (r Rule) monthCandidates(dtstart time.Time, offset int) []time.Time {
+	h, m, s := dtstart.Clock()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, h, m, s, dtstart.Nanosecond(), dtstart.Location())
+	}
+
+	first := at(dtstart.Year(), dtstart.Month()+time.Month(offset), 1)
+	year, month := first.Year(), first.Month()
+
+	if len(r.ByDay) == 0 {
+		day := at(year, month, dtstart.Day())
+		if day.Month() != month {
+			return nil
+		}
+		return []time.Time{day}
+	}
+
+	daysInMonth := at(year, month+1, 0).Day()
+	days := make([]time.Time, 0)
+	for _, d := range r.ByDay {
+		matches := make([]int, 0, 5)
+		for day := 1; day <= daysInMonth; day++ {
+			if at(year, month, day).Weekday() == d.Weekday {
+				matches = append(matches, day)
+			}
+		}
+
+		switch {
+		case d.Ordinal == 0:
+			for _, day := range matches {
+				days = append(days, at(year, month, day))
+			}
+		case d.Ordinal > 0 && d.Ordinal <= len(matches):
+			days = append(days, at(year, month, matches[d.Ordinal-1]))
+		case d.Ordinal < 0 && -d.Ordinal <= len(matches):
+			days = append(days, at(year, month, matches[len(matches)+d.Ordinal]))
+		}
+	}
+	return sortUnique(days)
+}
+
+// periodsBefore returns a conservative number of whole periods that can be skipped
+// before the first occurrence that may fall after the given time.
+func (r Rule) periodsBefore(dtstart, after time.Time, interval int) int {
+	if !after.After(dtstart) {
+		return 0
+	}
+
+	var units int
+	switch r.Freq {
+	case Daily:
+		units = int(after.Sub(dtstart).Hours() / 24)
+	case Weekly:
+		units = int(after.Sub(dtstart).Hours() / 24 / 7)
+	case Monthly:
+		units = (after.Year()-dtstart.Year())*12 + int(after.Month()-dtstart.Month())
+	case Yearly:
+		units = after.Year() - dtstart.Year()
+	}
+
+	periods := units/interval - 1
+	if periods < 0 {
+		return 0
+	}
+	return periods
+}
+
+func (r Rule) hasWeekday(wd time.Weekday) bool {
+	for _, d := range r.ByDay {
+		if d.Weekday == wd {
+			return true
+		}
+	}
+	return false
+}
+
+func mondayOffset(wd time.Weekday) int {
+	return (int(wd) + 6) % 7
+}
+
+func sortUnique(days []time.Time) []time.Time {
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	result := make([]time.Time, 0, len(days))
+	for _, d := range days {
+		if len(result) == 0 || !d.Equal(result[len(result)-1]) {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+func isExcluded(t time.Time, exdates []time.Time) bool {
+	for _, ex := range exdates {
+		if ex.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,208 @@
+package recurrence
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+const untilLayout = "20060102T150405Z"
+
+var ErrInvalidRule = fmt.Errorf("invalid recurrence rule")
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// WeekdayNum is a BYDAY entry: a weekday with an optional ordinal (e.g. 2TU, -1FR).
+type WeekdayNum struct {
+	Ordinal int
+	Weekday time.Weekday
+}
+
+// Rule is the subset of an RFC 5545 RRULE supported by the calendar.
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	ByDay    []WeekdayNum
+	Count    int
+	Until    time.Time
+}
+
+// Parse parses an RRULE value such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10".
+// An optional "RRULE:" prefix is accepted.
+func Parse(s string) (Rule, error) {
+	rule := Rule{Interval: 1}
+
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return Rule{}, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return Rule{}, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq, err = parseFreq(value)
+		case "INTERVAL":
+			rule.Interval, err = parsePositive(key, value)
+		case "COUNT":
+			rule.Count, err = parsePositive(key, value)
+		case "UNTIL":
+			rule.Until, err = parseUntil(value)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(value)
+		case "WKST":
+			if value != "MO" {
+				err = fmt.Errorf("%w: only WKST=MO is supported", ErrInvalidRule)
+			}
+		default:
+			err = fmt.Errorf("%w: unsupported part %q", ErrInvalidRule, key)
+		}
+		if err != nil {
+			return Rule{}, err
+		}
+	}
+
+	if err := rule.validate(); err != nil {
+		return Rule{}, err
+	}
+	return rule, nil
+}
+
+func (r Rule) validate() error {
+	switch r.Freq {
+	case Daily, Weekly, Monthly, Yearly:
+	case "":
+		return fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	default:
+		return fmt.Errorf("%w: unsupported FREQ %q", ErrInvalidRule, r.Freq)
+	}
+
+	if r.Count > 0 && !r.Until.IsZero() {
+		return fmt.Errorf("%w: COUNT and UNTIL are mutually exclusive", ErrInvalidRule)
+	}
+
+	for _, d := range r.ByDay {
+		if d.Ordinal != 0 && r.Freq != Monthly {
+			return fmt.Errorf("%w: BYDAY ordinals are only supported with FREQ=MONTHLY", ErrInvalidRule)
+		}
+	}
+	if len(r.ByDay) > 0 && r.Freq == Yearly {
+		return fmt.Errorf("%w: BYDAY is not supported with FREQ=YEARLY", ErrInvalidRule)
+	}
+
+	return nil
+}
+
+// Bounded reports whether the rule produces a finite number of occurrences.
+func (r Rule) Bounded() bool {
+	return r.Count > 0 || !r.Until.IsZero()
+}
+
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, d := range r.ByDay {
+			days = append(days, d.String())
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilLayout))
+	}
+	return strings.Join(parts, ";")
+}
+
+func (d WeekdayNum) String() string {
+	code := weekdayNames[d.Weekday]
+	if d.Ordinal == 0 {
+		return code
+	}
+	return strconv.Itoa(d.Ordinal) + code
+}
+
+func parseFreq(value string) (Frequency, error) {
+	freq := Frequency(strings.ToUpper(value))
+	switch freq {
+	case Daily, Weekly, Monthly, Yearly:
+		return freq, nil
+	default:
+		return "", fmt.Errorf("%w: unsupported FREQ %q", ErrInvalidRule, value)
+	}
+}
+
+func parsePositive(key, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%w: %s must be a positive integer", ErrInvalidRule, key)
+	}
+	return n, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{untilLayout, "20060102T150405", "20060102"} {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: invalid UNTIL %q", ErrInvalidRule, value)
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	items := strings.Split(value, ",")
+	days := make([]WeekdayNum, 0, len(items))
+	for _, item := range items {
+		item = strings.ToUpper(strings.TrimSpace(item))
+		if len(item) < 2 {
+			return nil, fmt.Errorf("%w: invalid BYDAY %q", ErrInvalidRule, item)
+		}
+
+		wd, ok := weekdayCodes[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid BYDAY %q", ErrInvalidRule, item)
+		}
+
+		ordinal := 0
+		if prefix := item[:len(item)-2]; prefix != "" {
+			n, err := strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, fmt.Errorf("%w: invalid BYDAY %q", ErrInvalidRule, item)
+			}
+			ordinal = n
+		}
+
+		days = append(days, WeekdayNum{Ordinal: ordinal, Weekday: wd})
+	}
+	return days, nil
+}
@@ -0,0 +1,196 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Rule
+		wantErr bool
+	}{
+		{
+			name:  "weekly with byday and count",
+			input: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10",
+			want: Rule{
+				Freq:     Weekly,
+				Interval: 2,
+				ByDay:    []WeekdayNum{{Weekday: time.Monday}, {Weekday: time.Wednesday}},
+				Count:    10,
+			},
+		},
+		{
+			name:  "rrule prefix and until",
+			input: "RRULE:FREQ=DAILY;UNTIL=20250610T000000Z",
+			want: Rule{
+				Freq:     Daily,
+				Interval: 1,
+				Until:    time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "monthly with ordinal",
+			input: "FREQ=MONTHLY;BYDAY=-1FR",
+			want: Rule{
+				Freq:     Monthly,
+				Interval: 1,
+				ByDay:    []WeekdayNum{{Ordinal: -1, Weekday: time.Friday}},
+			},
+		},
+		{name: "missing freq", input: "COUNT=3", wantErr: true},
+		{name: "unknown freq", input: "FREQ=HOURLY", wantErr: true},
+		{name: "count and until", input: "FREQ=DAILY;COUNT=3;UNTIL=20250610T000000Z", wantErr: true},
+		{name: "ordinal in weekly", input: "FREQ=WEEKLY;BYDAY=2MO", wantErr: true},
+		{name: "bad interval", input: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{name: "unsupported part", input: "FREQ=DAILY;BYHOUR=9", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidRule)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+
+			reparsed, err := Parse(got.String())
+			require.NoError(t, err)
+			require.Equal(t, got, reparsed)
+		})
+	}
+}
+
+func TestRule_Between(t *testing.T) {
+	// Monday.
+	dtstart := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		rule    string
+		dtstart time.Time
+		exdates []time.Time
+		after   time.Time
+		before  time.Time
+		want    []time.Time
+	}{
+		{
+			name:   "daily count",
+			rule:   "FREQ=DAILY;COUNT=3",
+			after:  dtstart,
+			before: dtstart.AddDate(1, 0, 0),
+			want:   []time.Time{dtstart, dtstart.AddDate(0, 0, 1), dtstart.AddDate(0, 0, 2)},
+		},
+		{
+			name:   "weekly byday within window",
+			rule:   "FREQ=WEEKLY;BYDAY=MO,WE",
+			after:  dtstart.AddDate(0, 0, 7),
+			before: dtstart.AddDate(0, 0, 13),
+			want:   []time.Time{dtstart.AddDate(0, 0, 7), dtstart.AddDate(0, 0, 9)},
+		},
+		{
+			name:   "biweekly",
+			rule:   "FREQ=WEEKLY;INTERVAL=2",
+			after:  dtstart,
+			before: dtstart.AddDate(0, 0, 35),
+			want:   []time.Time{dtstart, dtstart.AddDate(0, 0, 14), dtstart.AddDate(0, 0, 28)},
+		},
+		{
+			name:    "exdate still counts",
+			rule:    "FREQ=DAILY;COUNT=3",
+			exdates: []time.Time{dtstart.AddDate(0, 0, 1)},
+			after:   dtstart,
+			before:  dtstart.AddDate(1, 0, 0),
+			want:    []time.Time{dtstart, dtstart.AddDate(0, 0, 2)},
+		},
+		{
+			name:   "until is inclusive",
+			rule:   "FREQ=DAILY;UNTIL=20250604T090000Z",
+			after:  dtstart,
+			before: dtstart.AddDate(1, 0, 0),
+			want:   []time.Time{dtstart, dtstart.AddDate(0, 0, 1), dtstart.AddDate(0, 0, 2)},
+		},
+		{
+			name:   "monthly last friday",
+			rule:   "FREQ=MONTHLY;BYDAY=-1FR;COUNT=2",
+			after:  dtstart,
+			before: dtstart.AddDate(1, 0, 0),
+			want: []time.Time{
+				time.Date(2025, 6, 27, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 7, 25, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:    "monthly skips short months",
+			rule:    "FREQ=MONTHLY;COUNT=3",
+			dtstart: time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC),
+			after:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			before:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 3, 31, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 5, 31, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "far window of open-ended rule",
+			rule:   "FREQ=DAILY",
+			after:  dtstart.AddDate(10, 0, 0),
+			before: dtstart.AddDate(10, 0, 1),
+			want:   []time.Time{dtstart.AddDate(10, 0, 0), dtstart.AddDate(10, 0, 1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			require.NoError(t, err)
+
+			start := tt.dtstart
+			if start.IsZero() {
+				start = dtstart
+			}
+
+			got := rule.Between(start, tt.exdates, tt.after, tt.before)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRule_BetweenKeepsWallClockAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone database is not available")
+	}
+
+	rule, err := Parse("FREQ=WEEKLY;COUNT=2")
+	require.NoError(t, err)
+
+	dtstart := time.Date(2025, 3, 27, 9, 0, 0, 0, loc)
+	got := rule.Between(dtstart, nil, dtstart, dtstart.AddDate(0, 1, 0))
+
+	require.Len(t, got, 2)
+	require.Equal(t, 9, got[1].Hour())
+	require.Equal(t, 167*time.Hour, got[1].Sub(got[0]))
+}
+
+func TestRule_Last(t *testing.T) {
+	dtstart := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	rule, err := Parse("FREQ=WEEKLY;COUNT=4")
+	require.NoError(t, err)
+	last, ok := rule.Last(dtstart)
+	require.True(t, ok)
+	require.Equal(t, dtstart.AddDate(0, 0, 21), last)
+
+	rule, err = Parse("FREQ=DAILY")
+	require.NoError(t, err)
+	_, ok = rule.Last(dtstart)
+	require.False(t, ok)
+}
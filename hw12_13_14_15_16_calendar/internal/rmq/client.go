@@ -1,33 +1,175 @@
 package rmq
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/tracing"
 	"github.com/streadway/amqp" //nolint:depguard
 )
 
+const (
+	// NotificationsQueue receives the notifications published with NotificationRoutingKey.
+	NotificationsQueue = "notifications"
+	// StatusQueue receives the delivery statuses published with StatusRoutingKey.
+	StatusQueue      = "notification_status"
+	StatusRoutingKey = "status.notification"
+)
+
+// NotificationRoutingKey is the routing key of the notifications addressed to the user.
+func NotificationRoutingKey(userID string) string {
+	return "notifications." + userID
+}
+
+// Config describes the broker connection. Prefetch bounds the unsettled deliveries a consumer holds;
+// zero leaves it unbounded. A lost connection is re-established with delays that double from
+// ReconnectInterval up to MaxReconnectInterval. Publishes wait up to ConfirmTimeout for the broker to confirm
+// the message.
+type Config struct {
+	URL                  string
+	Exchange             string
+	Prefetch             int
+	Retry                RetryPolicy
+	ReconnectInterval    time.Duration
+	MaxReconnectInterval time.Duration
+	ConfirmTimeout       time.Duration
+	Logger               Logger
+}
+
+// Logger receives the reports of lost and restored connections; nil discards them.
+type Logger interface {
+	Infof(string, ...interface{})
+	Warnf(string, ...interface{})
+}
+
+// State is the state of the connection to the broker.
+type State string
+
+const (
+	StateConnected    State = "connected"
+	StateReconnecting State = "reconnecting"
+	StateClosed       State = "closed"
+)
+
+var (
+	ErrNotConnected = errors.New("rabbitmq connection is down")
+	ErrClosed       = errors.New("rabbitmq client is closed")
+)
+
+const (
+	defaultReconnectInterval    = time.Second
+	defaultMaxReconnectInterval = 30 * time.Second
+)
+
 type Client interface {
-	Publish(routingKey string, body []byte) error
-	Consume(queueName string) (<-chan []byte, error)
+	// Publish returns once the broker confirms the message. It fails with ErrUnroutable when no queue is
+	// bound to the routing key, with ErrNacked or ErrConfirmTimeout when the broker does not take the message
+	// and with ErrNotConnected while the connection is down. The trace context of ctx travels in the headers.
+	Publish(ctx context.Context, routingKey string, body []byte) error
+	// Consume delivers the messages of the queue, across reconnections, until the client is closed.
+	Consume(queueName string) (<-chan Delivery, error)
+	// DeadLetters returns up to limit messages from the dead-letter queue of the queue and leaves them there.
+	DeadLetters(queueName string, limit int) ([]DeadLetter, error)
+	// Replay moves up to limit messages from the dead-letter queue back to the queue, with their
+	// attempts reset, and returns how many were moved.
+	Replay(queueName string, limit int) (int, error)
+	State() State
 	Close() error
 }
 
+// client keeps a connection to the broker: when the connection or its channel closes, it reconnects,
+// declares the topology again and resumes the consumers. Publishing while the connection is down fails
+// with ErrNotConnected instead of waiting for the broker. Every message, including retries and dead
+// letters, is published as mandatory and waits for the confirmation of the broker.
 type client struct {
-	channel  *amqp.Channel
-	exchange string
+	cfg    Config
+	retry  RetryPolicy
+	logger Logger
+
+	mu        sync.RWMutex
+	conn      *amqp.Connection
+	channel   *amqp.Channel
+	publisher *publisher
+	state     State
+	consumers []*consumer
+	delays    delayQueues
+
+	closed    chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+type consumer struct {
+	queue string
+	out   chan Delivery
 }
 
-func NewClient(amqpURL, exchange string) (Client, error) {
-	conn, err := amqp.Dial(amqpURL)
+// NewClient fails when the broker cannot be reached; once connected, the client survives outages.
+func NewClient(cfg Config) (Client, error) {
+	if cfg.ReconnectInterval <= 0 {
+		cfg.ReconnectInterval = defaultReconnectInterval
+	}
+	if cfg.MaxReconnectInterval < cfg.ReconnectInterval {
+		cfg.MaxReconnectInterval = max(defaultMaxReconnectInterval, cfg.ReconnectInterval)
+	}
+	if cfg.ConfirmTimeout <= 0 {
+		cfg.ConfirmTimeout = defaultConfirmTimeout
+	}
+
+	c := &client{
+		cfg:    cfg,
+		retry:  cfg.Retry.withDefaults(),
+		logger: cfg.Logger,
+		delays: delayQueues{declared: make(map[string]bool)},
+		closed: make(chan struct{}),
+	}
+
+	conn, ch, pub, err := c.connect()
 	if err != nil {
 		return nil, err
 	}
+	c.attach(conn, ch, pub)
 
+	go c.supervise(conn, ch)
+	return c, nil
+}
+
+// connect opens a connection and a channel in confirm mode and declares the exchanges and the queues
+// of the services.
+func (c *client) connect() (*amqp.Connection, *amqp.Channel, *publisher, error) {
+	conn, err := amqp.Dial(c.cfg.URL)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var pub *publisher
 	ch, err := conn.Channel()
+	if err == nil {
+		err = declareTopology(ch, c.cfg)
+	}
+	if err == nil {
+		pub, err = newPublisher(ch, c.cfg.ConfirmTimeout)
+	}
 	if err != nil {
-		return nil, err
+		_ = conn.Close()
+		return nil, nil, nil, err
 	}
+	return conn, ch, pub, nil
+}
 
-	err = ch.ExchangeDeclare(
-		exchange,
+func declareTopology(ch *amqp.Channel, cfg Config) error {
+	if cfg.Prefetch > 0 {
+		if err := ch.Qos(cfg.Prefetch, 0, false); err != nil {
+			return err
+		}
+	}
+
+	err := ch.ExchangeDeclare(
+		cfg.Exchange,
 		"topic",
 		true,
 		false,
@@ -36,11 +178,12 @@ func NewClient(amqpURL, exchange string) (Client, error) {
 		nil,
 	)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	_, err = ch.QueueDeclare(
-		"notifications",
+	err = ch.ExchangeDeclare(
+		DeadLetterExchange(cfg.Exchange),
+		"direct",
 		true,
 		false,
 		false,
@@ -48,14 +191,11 @@ func NewClient(amqpURL, exchange string) (Client, error) {
 		nil,
 	)
 	if err != nil {
-		return nil, err
-	}
-	if err := ch.QueueBind("notifications", "notifications", exchange, false, nil); err != nil {
-		return nil, err
+		return err
 	}
 
 	_, err = ch.QueueDeclare(
-		"notification_status",
+		NotificationsQueue,
 		true,
 		false,
 		false,
@@ -63,34 +203,14 @@ func NewClient(amqpURL, exchange string) (Client, error) {
 		nil,
 	)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	if err := ch.QueueBind("notification_status", "status.#", exchange, false, nil); err != nil {
-		return nil, err
+	if err := ch.QueueBind(NotificationsQueue, "notifications.#", cfg.Exchange, false, nil); err != nil {
+		return err
 	}
 
-	return &client{
-		channel:  ch,
-		exchange: exchange,
-	}, nil
-}
-
-func (c *client) Publish(routingKey string, body []byte) error {
-	return c.channel.Publish(
-		c.exchange,
-		routingKey,
-		false,
-		false,
-		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        body,
-		},
-	)
-}
-
-func (c *client) Consume(queueName string) (<-chan []byte, error) {
-	queue, err := c.channel.QueueDeclare(
-		queueName,
+	_, err = ch.QueueDeclare(
+		StatusQueue,
 		true,
 		false,
 		false,
@@ -98,42 +218,335 @@ func (c *client) Consume(queueName string) (<-chan []byte, error) {
 		nil,
 	)
 	if err != nil {
+		return err
+	}
+	return ch.QueueBind(StatusQueue, "status.#", cfg.Exchange, false, nil)
+}
+
+func (c *client) attach(conn *amqp.Connection, ch *amqp.Channel, pub *publisher) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.conn, c.channel, c.publisher, c.state = conn, ch, pub, StateConnected
+	c.delays.reset()
+}
+
+// supervise waits for the connection or the channel to close and connects again until it succeeds
+// or the client is closed.
+func (c *client) supervise(conn *amqp.Connection, ch *amqp.Channel) {
+	for {
+		connClosed := conn.NotifyClose(make(chan *amqp.Error, 1))
+		chClosed := ch.NotifyClose(make(chan *amqp.Error, 1))
+
+		var reason *amqp.Error
+		select {
+		case <-c.closed:
+			return
+		case reason = <-connClosed:
+		case reason = <-chClosed:
+		}
+
+		c.mu.Lock()
+		if c.state == StateClosed {
+			c.mu.Unlock()
+			return
+		}
+		c.conn, c.channel, c.publisher, c.state = nil, nil, nil, StateReconnecting
+		c.mu.Unlock()
+		_ = conn.Close()
+		c.warnf("RabbitMQ connection lost: %v; reconnecting", reason)
+
+		var ok bool
+		if conn, ch, ok = c.reconnect(); !ok {
+			return
+		}
+		c.infof("RabbitMQ connection restored")
+	}
+}
+
+// reconnect returns false when the client is closed before a connection is established.
+func (c *client) reconnect() (*amqp.Connection, *amqp.Channel, bool) {
+	delay := c.cfg.ReconnectInterval
+	for attempt := 1; ; attempt++ {
+		timer := time.NewTimer(delay)
+		select {
+		case <-c.closed:
+			timer.Stop()
+			return nil, nil, false
+		case <-timer.C:
+		}
+
+		conn, ch, pub, err := c.connect()
+		if err == nil {
+			if err = c.resume(conn, ch, pub); err == nil {
+				return conn, ch, true
+			}
+			_ = conn.Close()
+		}
+
+		c.warnf("RabbitMQ reconnection attempt %d failed: %v", attempt, err)
+		delay = min(delay*2, c.cfg.MaxReconnectInterval)
+	}
+}
+
+// resume attaches the new connection and restarts the consumers on it.
+func (c *client) resume(conn *amqp.Connection, ch *amqp.Channel, pub *publisher) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == StateClosed {
+		return ErrClosed
+	}
+	for _, cons := range c.consumers {
+		if err := c.startConsumer(ch, cons); err != nil {
+			return fmt.Errorf("failed to resume consumer of %s: %w", cons.queue, err)
+		}
+	}
+	c.conn, c.channel, c.publisher, c.state = conn, ch, pub, StateConnected
+	c.delays.reset()
+	return nil
+}
+
+// current returns the channel and the publisher of the live connection.
+func (c *client) current() (*amqp.Channel, *publisher, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	switch c.state {
+	case StateClosed:
+		return nil, nil, ErrClosed
+	case StateReconnecting:
+		return nil, nil, ErrNotConnected
+	default:
+		return c.channel, c.publisher, nil
+	}
+}
+
+func (c *client) publish(exchange, routingKey string, msg amqp.Publishing) error {
+	_, pub, err := c.current()
+	if err != nil {
+		return err
+	}
+	return pub.publish(exchange, routingKey, msg)
+}
+
+func (c *client) State() State {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.state
+}
+
+func (c *client) Publish(ctx context.Context, routingKey string, body []byte) (err error) {
+	ctx, span := startPublishSpan(ctx, c.cfg.Exchange, routingKey)
+	defer func() { tracing.End(span, err) }()
+
+	headers := amqp.Table{}
+	tracing.Inject(ctx, headerCarrier(headers))
+	return c.publish(c.cfg.Exchange, routingKey, amqp.Publishing{
+		ContentType: "application/json",
+		Headers:     headers,
+		Body:        body,
+	})
+}
+
+// Consume delivers the messages of the queue until the client is closed. Deliveries must be settled
+// by the consumer; the ones left unsettled when the connection is lost are delivered again.
+func (c *client) Consume(queueName string) (<-chan Delivery, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch c.state {
+	case StateClosed:
+		return nil, ErrClosed
+	case StateReconnecting:
+		return nil, ErrNotConnected
+	}
+
+	cons := &consumer{queue: queueName, out: make(chan Delivery)}
+	if err := c.startConsumer(c.channel, cons); err != nil {
 		return nil, err
 	}
+	c.consumers = append(c.consumers, cons)
+	return cons.out, nil
+}
 
-	err = c.channel.QueueBind(
-		queue.Name,
-		"#",
-		c.exchange,
+// startConsumer declares the queue of the consumer and forwards its messages from the channel
+// until the channel closes.
+func (c *client) startConsumer(ch *amqp.Channel, cons *consumer) error {
+	queue, err := ch.QueueDeclare(
+		cons.queue,
+		true,
+		false,
+		false,
 		false,
 		nil,
 	)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if err := c.declareDeadLetterQueue(ch, queue.Name); err != nil {
+		return err
 	}
 
-	msgs, err := c.channel.Consume(
+	msgs, err := ch.Consume(
 		queue.Name,
 		"",
-		true,
+		false,
 		false,
 		false,
 		false,
 		nil,
 	)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	out := make(chan []byte)
+	c.wg.Add(1)
 	go func() {
+		defer c.wg.Done()
 		for msg := range msgs {
-			out <- msg.Body
+			attempt := max(headerInt(msg.Headers, attemptHeader), 1)
+			observeLag(queue.Name, msg, attempt)
+			span := startProcessSpan(queue.Name, msg)
+			delivery := Delivery{
+				Body:         msg.Body,
+				Attempt:      attempt,
+				Acknowledger: acknowledger{c: c, queue: queue.Name, msg: msg, attempt: attempt, span: span},
+				span:         span,
+			}
+			select {
+			case cons.out <- delivery:
+			case <-c.closed:
+				span.End()
+				return
+			}
 		}
 	}()
-	return out, nil
+	return nil
+}
+
+// DeadLetters returns the messages to the queue with a single negative acknowledgement that covers every
+// unsettled delivery of the channel, so it is meant for clients that do not consume.
+func (c *client) DeadLetters(queueName string, limit int) ([]DeadLetter, error) {
+	ch, _, err := c.current()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.declareDeadLetterQueue(ch, queueName); err != nil {
+		return nil, err
+	}
+
+	letters := make([]DeadLetter, 0)
+	var last amqp.Delivery
+	for limit <= 0 || len(letters) < limit {
+		msg, ok, err := ch.Get(DeadLetterQueue(queueName), false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read dead letters: %w", err)
+		}
+		if !ok {
+			break
+		}
+		letters = append(letters, toDeadLetter(msg))
+		last = msg
+	}
+
+	if len(letters) > 0 {
+		if err := last.Nack(true, true); err != nil {
+			return nil, fmt.Errorf("failed to return dead letters: %w", err)
+		}
+	}
+	return letters, nil
+}
+
+func (c *client) Replay(queueName string, limit int) (int, error) {
+	ch, pub, err := c.current()
+	if err != nil {
+		return 0, err
+	}
+	if err := c.declareDeadLetterQueue(ch, queueName); err != nil {
+		return 0, err
+	}
+
+	replayed := 0
+	for limit <= 0 || replayed < limit {
+		msg, ok, err := ch.Get(DeadLetterQueue(queueName), false)
+		if err != nil {
+			return replayed, fmt.Errorf("failed to read dead letters: %w", err)
+		}
+		if !ok {
+			break
+		}
+
+		err = pub.publish("", queueName, amqp.Publishing{
+			ContentType:  msg.ContentType,
+			DeliveryMode: amqp.Persistent,
+			Body:         msg.Body,
+		})
+		if err != nil {
+			_ = msg.Nack(false, true)
+			return replayed, fmt.Errorf("failed to replay message: %w", err)
+		}
+		if err := msg.Ack(false); err != nil {
+			return replayed, fmt.Errorf("failed to remove replayed message: %w", err)
+		}
+		replayed++
+	}
+	return replayed, nil
+}
+
+func (c *client) declareDeadLetterQueue(ch *amqp.Channel, queueName string) error {
+	name := DeadLetterQueue(queueName)
+	if _, err := ch.QueueDeclare(name, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare dead-letter queue: %w", err)
+	}
+	if err := ch.QueueBind(name, queueName, DeadLetterExchange(c.cfg.Exchange), false, nil); err != nil {
+		return fmt.Errorf("failed to bind dead-letter queue: %w", err)
+	}
+	return nil
 }
 
+// Close stops reconnecting, closes the connection and then the channels returned by Consume.
+// Closing again does nothing.
 func (c *client) Close() error {
-	return c.channel.Close()
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.closed)
+
+		c.mu.Lock()
+		conn := c.conn
+		c.conn, c.channel, c.publisher, c.state = nil, nil, nil, StateClosed
+		consumers := c.consumers
+		c.mu.Unlock()
+
+		if conn != nil {
+			err = conn.Close()
+		}
+		c.wg.Wait()
+		for _, cons := range consumers {
+			close(cons.out)
+		}
+	})
+	return err
+}
+
+// observeLag measures the lag of first deliveries only; retries include the delay they waited for.
+func observeLag(queue string, msg amqp.Delivery, attempt int) {
+	publishedAt, ok := msg.Headers[publishedAtHeader].(int64)
+	if !ok || attempt > 1 {
+		return
+	}
+	lag := time.Since(time.UnixMilli(publishedAt))
+	metrics.ConsumeLag.WithLabelValues(queue).Observe(max(lag, 0).Seconds())
+}
+
+func (c *client) infof(format string, args ...interface{}) {
+	if c.logger != nil {
+		c.logger.Infof(format, args...)
+	}
+}
+
+func (c *client) warnf(format string, args ...interface{}) {
+	if c.logger != nil {
+		c.logger.Warnf(format, args...)
+	}
 }
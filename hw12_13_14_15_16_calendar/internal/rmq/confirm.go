@@ -0,0 +1,118 @@
+package rmq
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/streadway/amqp" //nolint:depguard
+)
+
+var (
+	// ErrUnroutable means that no queue is bound to the routing key of the message and the broker returned it.
+	ErrUnroutable = errors.New("message is unroutable")
+	// ErrNacked means that the broker failed to take responsibility for the message.
+	ErrNacked = errors.New("message is rejected by the broker")
+	// ErrConfirmTimeout means that the broker did not confirm the message in time; it may still be delivered.
+	ErrConfirmTimeout = errors.New("message confirmation timed out")
+)
+
+const (
+	defaultConfirmTimeout = 5 * time.Second
+	// notifyBuffer holds the confirmations and returns left over by publishes that timed out.
+	notifyBuffer = 64
+)
+
+// publisher publishes on a channel in confirm mode. Publishes are serialized so that every message waits
+// for its own confirmation; the message ID is set to the delivery tag to match the returned messages.
+type publisher struct {
+	mu       sync.Mutex
+	ch       *amqp.Channel
+	confirms chan amqp.Confirmation
+	returns  chan amqp.Return
+	tag      uint64
+	timeout  time.Duration
+}
+
+func newPublisher(ch *amqp.Channel, timeout time.Duration) (*publisher, error) {
+	if err := ch.Confirm(false); err != nil {
+		return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+	return &publisher{
+		ch:       ch,
+		confirms: ch.NotifyPublish(make(chan amqp.Confirmation, notifyBuffer)),
+		returns:  ch.NotifyReturn(make(chan amqp.Return, notifyBuffer)),
+		timeout:  timeout,
+	}, nil
+}
+
+// publish sends a mandatory message and waits until the broker confirms it.
+func (p *publisher) publish(exchange, routingKey string, msg amqp.Publishing) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// The channel counts only the messages it has sent, so the tag advances after a successful publish.
+	tag := p.tag + 1
+	msg.MessageId = strconv.FormatUint(tag, 10)
+	msg.Headers = copyHeaders(msg.Headers)
+	msg.Headers[publishedAtHeader] = time.Now().UnixMilli()
+	if err := p.ch.Publish(exchange, routingKey, true, false, msg); err != nil {
+		if errors.Is(err, amqp.ErrClosed) {
+			return fmt.Errorf("%w: %w", ErrNotConnected, err)
+		}
+		return err
+	}
+	p.tag = tag
+	return awaitConfirm(p.confirms, p.returns, tag, p.timeout)
+}
+
+// awaitConfirm waits for the confirmation of the delivery tag, skipping the ones of earlier publishes.
+// The broker returns an unroutable message before confirming it, so its return is already buffered
+// when the confirmation arrives.
+func awaitConfirm(
+	confirms <-chan amqp.Confirmation,
+	returns <-chan amqp.Return,
+	tag uint64,
+	timeout time.Duration,
+) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case confirm, ok := <-confirms:
+			if !ok {
+				return ErrNotConnected
+			}
+			if confirm.DeliveryTag < tag {
+				continue
+			}
+			if !confirm.Ack {
+				return ErrNacked
+			}
+			return returned(returns, strconv.FormatUint(tag, 10))
+		case <-timer.C:
+			return ErrConfirmTimeout
+		}
+	}
+}
+
+// returned drains the buffered returns and reports whether the message was among them.
+func returned(returns <-chan amqp.Return, messageID string) error {
+	var err error
+	for {
+		select {
+		case ret, ok := <-returns:
+			if !ok {
+				return err
+			}
+			if ret.MessageId == messageID {
+				err = fmt.Errorf("%w: %s", ErrUnroutable, ret.ReplyText)
+			}
+		default:
+			return err
+		}
+	}
+}
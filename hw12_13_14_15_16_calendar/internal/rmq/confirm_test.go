@@ -0,0 +1,78 @@
+package rmq
+
+import (
+	"testing"
+	"time"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAwaitConfirm(t *testing.T) {
+	tests := []struct {
+		name     string
+		confirms []amqp.Confirmation
+		returns  []amqp.Return
+		closed   bool
+		want     error
+	}{
+		{
+			name:     "confirmed",
+			confirms: []amqp.Confirmation{{DeliveryTag: 3, Ack: true}},
+		},
+		{
+			name:     "skips earlier confirmations",
+			confirms: []amqp.Confirmation{{DeliveryTag: 2, Ack: false}, {DeliveryTag: 3, Ack: true}},
+		},
+		{
+			name:     "ignores earlier returns",
+			confirms: []amqp.Confirmation{{DeliveryTag: 3, Ack: true}},
+			returns:  []amqp.Return{{MessageId: "2", ReplyText: "NO_ROUTE"}},
+		},
+		{
+			name:     "returned",
+			confirms: []amqp.Confirmation{{DeliveryTag: 3, Ack: true}},
+			returns:  []amqp.Return{{MessageId: "2"}, {MessageId: "3", ReplyText: "NO_ROUTE"}},
+			want:     ErrUnroutable,
+		},
+		{
+			name:     "nacked",
+			confirms: []amqp.Confirmation{{DeliveryTag: 3, Ack: false}},
+			want:     ErrNacked,
+		},
+		{
+			name:   "channel closed",
+			closed: true,
+			want:   ErrNotConnected,
+		},
+		{
+			name:     "timed out",
+			confirms: []amqp.Confirmation{{DeliveryTag: 2, Ack: true}},
+			want:     ErrConfirmTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			confirms := make(chan amqp.Confirmation, len(tt.confirms))
+			for _, confirm := range tt.confirms {
+				confirms <- confirm
+			}
+			if tt.closed {
+				close(confirms)
+			}
+			returns := make(chan amqp.Return, len(tt.returns))
+			for _, ret := range tt.returns {
+				returns <- ret
+			}
+
+			err := awaitConfirm(confirms, returns, 3, 10*time.Millisecond)
+			if tt.want == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tt.want)
+			}
+			require.Empty(t, returns)
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package rmq
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/tracing"
+	"github.com/streadway/amqp"      //nolint:depguard
+	"go.opentelemetry.io/otel/codes" //nolint:depguard
+	"go.opentelemetry.io/otel/trace" //nolint:depguard
+)
+
+const (
+	attemptHeader    = "x-attempt"
+	errorHeader      = "x-error"
+	deadAtHeader     = "x-dead-at"
+	routingKeyHeader = "x-original-routing-key"
+	// publishedAtHeader holds the time of publishing in Unix milliseconds, to measure the consume lag.
+	publishedAtHeader = "x-published-at"
+)
+
+// Delivery is a consumed message. The consumer settles it with one of Ack, Retry or DeadLetter; until
+// then the broker keeps it and delivers it again if the consumer goes away.
+type Delivery struct {
+	Body []byte
+	// Attempt is 1 on the first delivery and grows with every retry.
+	Attempt int
+	Acknowledger
+
+	span trace.Span
+}
+
+// WithTrace returns ctx carrying the span of processing the message, so that the spans of the handler
+// join the trace of the publisher.
+func (d Delivery) WithTrace(ctx context.Context) context.Context {
+	if d.span == nil {
+		return ctx
+	}
+	return trace.ContextWithSpan(ctx, d.span)
+}
+
+//go:generate mockgen -source=delivery.go -package=mocks -destination=../../mocks/mock_acknowledger.go
+type Acknowledger interface {
+	// Ack removes the message from the queue.
+	Ack() error
+	// Retry delivers the message again after the backoff of its attempt, or moves it to the
+	// dead-letter queue when the retries are exhausted.
+	Retry(reason string) error
+	// DeadLetter moves the message to the dead-letter queue, for messages no retry can help.
+	DeadLetter(reason string) error
+}
+
+// DeadLetter is a message that was given up on, as kept in the dead-letter queue.
+type DeadLetter struct {
+	Body       []byte
+	RoutingKey string
+	Attempts   int
+	Reason     string
+	DeadAt     time.Time
+}
+
+// DeadLetterExchange receives the messages given up on; each consumed queue has a dead-letter queue
+// bound to it with the queue name as the routing key.
+func DeadLetterExchange(exchange string) string {
+	return exchange + ".dlx"
+}
+
+func DeadLetterQueue(queue string) string {
+	return queue + ".dead"
+}
+
+// delayQueue holds the messages to retry after delay; expired messages return to the queue.
+// The delay is part of the name, so changing the policy declares new queues instead of clashing
+// with the arguments of the old ones.
+func delayQueue(queue string, delay time.Duration) string {
+	return fmt.Sprintf("%s.retry.%d", queue, delay.Milliseconds())
+}
+
+type acknowledger struct {
+	c       *client
+	queue   string
+	msg     amqp.Delivery
+	attempt int
+	span    trace.Span
+}
+
+func (a acknowledger) Ack() error {
+	err := a.msg.Ack(false)
+	tracing.End(a.span, err)
+	return err
+}
+
+func (a acknowledger) Retry(reason string) (err error) {
+	if a.attempt > a.c.retry.MaxRetries {
+		return a.DeadLetter(reason)
+	}
+	defer func() { a.fail(reason, err) }()
+
+	delay := a.c.retry.Delay(a.attempt)
+	name, err := a.c.declareDelayQueue(a.queue, delay)
+	if err != nil {
+		return err
+	}
+
+	headers := copyHeaders(a.msg.Headers)
+	headers[attemptHeader] = int64(a.attempt + 1)
+	headers[errorHeader] = reason
+	if err := a.c.publish("", name, amqp.Publishing{
+		ContentType:  a.msg.ContentType,
+		DeliveryMode: amqp.Persistent,
+		Headers:      headers,
+		Body:         a.msg.Body,
+	}); err != nil {
+		return fmt.Errorf("failed to schedule retry: %w", err)
+	}
+	return a.msg.Ack(false)
+}
+
+func (a acknowledger) DeadLetter(reason string) (err error) {
+	defer func() { a.fail(reason, err) }()
+
+	headers := copyHeaders(a.msg.Headers)
+	headers[attemptHeader] = int64(a.attempt)
+	headers[errorHeader] = reason
+	headers[deadAtHeader] = time.Now().UTC()
+	if _, ok := headers[routingKeyHeader]; !ok {
+		headers[routingKeyHeader] = a.msg.RoutingKey
+	}
+	if err := a.c.publish(DeadLetterExchange(a.c.cfg.Exchange), a.queue, amqp.Publishing{
+		ContentType:  a.msg.ContentType,
+		DeliveryMode: amqp.Persistent,
+		Headers:      headers,
+		Body:         a.msg.Body,
+	}); err != nil {
+		return fmt.Errorf("failed to dead-letter message: %w", err)
+	}
+	return a.msg.Ack(false)
+}
+
+// fail ends the span of processing the message as failed for the reason.
+func (a acknowledger) fail(reason string, err error) {
+	if err == nil {
+		a.span.SetStatus(codes.Error, reason)
+	}
+	tracing.End(a.span, err)
+}
+
+// delayQueues remembers the delay queues declared since the connection was established.
+type delayQueues struct {
+	mu       sync.Mutex
+	declared map[string]bool
+}
+
+func (d *delayQueues) reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.declared = make(map[string]bool)
+}
+
+func (c *client) declareDelayQueue(queue string, delay time.Duration) (string, error) {
+	name := delayQueue(queue, delay)
+	ch, _, err := c.current()
+	if err != nil {
+		return "", err
+	}
+
+	c.delays.mu.Lock()
+	defer c.delays.mu.Unlock()
+
+	if c.delays.declared[name] {
+		return name, nil
+	}
+	_, err = ch.QueueDeclare(name, true, false, false, false, amqp.Table{
+		"x-message-ttl":             delay.Milliseconds(),
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": queue,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to declare retry queue: %w", err)
+	}
+	c.delays.declared[name] = true
+	return name, nil
+}
+
+func copyHeaders(headers amqp.Table) amqp.Table {
+	result := make(amqp.Table, len(headers)+3)
+	for k, v := range headers {
+		result[k] = v
+	}
+	return result
+}
+
+func headerInt(headers amqp.Table, key string) int {
+	switch v := headers[key].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int16:
+		return int(v)
+	case int:
+		return v
+	default:
+		return 0
+	}
+}
+
+func toDeadLetter(msg amqp.Delivery) DeadLetter {
+	letter := DeadLetter{Body: msg.Body, Attempts: headerInt(msg.Headers, attemptHeader)}
+	letter.RoutingKey, _ = msg.Headers[routingKeyHeader].(string)
+	letter.Reason, _ = msg.Headers[errorHeader].(string)
+	letter.DeadAt, _ = msg.Headers[deadAtHeader].(time.Time)
+	return letter
+}
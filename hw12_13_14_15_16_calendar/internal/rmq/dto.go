@@ -1,10 +1,20 @@
 package rmq
 
+// Notification is a reminder about one occurrence of an event. ID identifies the reminder, so
+// a consumer can recognize a notification published again.
+//
+// Delivery is at least once. The scheduler publishes a notification again when the broker does not
+// confirm it or its delivered status does not come back in time, and a sender recognizes only the copies
+// of the notifications it delivered since it started; copies reaching another replica, or a restarted
+// one, are delivered again.
 type Notification struct {
 	ID          string `json:"id"`
+	EventID     string `json:"eventId"`
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	UserID      string `json:"userId"`
 	Time        string `json:"time"`
 	NotifyAt    string `json:"notifyAt"`
+	TimeZone    string `json:"timeZone,omitempty"`
+	AllDay      bool   `json:"allDay,omitempty"`
 }
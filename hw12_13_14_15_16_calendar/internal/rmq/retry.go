@@ -0,0 +1,46 @@
+package rmq
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	defaultInitialInterval = time.Second
+	defaultMaxInterval     = 5 * time.Minute
+	defaultMultiplier      = 2
+)
+
+// RetryPolicy spaces the redeliveries of a message out exponentially. A message is delivered at most
+// MaxRetries+1 times before it goes to the dead-letter queue, so zero MaxRetries dead-letters it on the
+// first failure. Zero intervals and multiplier take the defaults.
+type RetryPolicy struct {
+	MaxRetries      int
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Multiplier      float64
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.InitialInterval <= 0 {
+		p.InitialInterval = defaultInitialInterval
+	}
+	if p.MaxInterval <= 0 {
+		p.MaxInterval = defaultMaxInterval
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = defaultMultiplier
+	}
+	return p
+}
+
+// Delay returns how long to wait before the delivery that follows the given attempt, counted from 1.
+// Delays are whole milliseconds, the precision of message TTLs.
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	p = p.withDefaults()
+	delay := float64(p.InitialInterval) * math.Pow(p.Multiplier, float64(max(attempt-1, 0)))
+	if delay > float64(p.MaxInterval) {
+		return p.MaxInterval.Truncate(time.Millisecond)
+	}
+	return time.Duration(delay).Truncate(time.Millisecond)
+}
@@ -0,0 +1,36 @@
+package rmq_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/rmq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := rmq.RetryPolicy{InitialInterval: time.Second, MaxInterval: 10 * time.Second, Multiplier: 3}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 0, want: time.Second},
+		{attempt: 1, want: time.Second},
+		{attempt: 2, want: 3 * time.Second},
+		{attempt: 3, want: 9 * time.Second},
+		{attempt: 4, want: 10 * time.Second},
+		{attempt: 100, want: 10 * time.Second},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, policy.Delay(tt.attempt), "attempt %d", tt.attempt)
+	}
+
+	defaults := rmq.RetryPolicy{}
+	require.Equal(t, time.Second, defaults.Delay(1))
+	require.Equal(t, 2*time.Second, defaults.Delay(2))
+	require.Equal(t, 5*time.Minute, defaults.Delay(20))
+
+	fractional := rmq.RetryPolicy{InitialInterval: 100 * time.Millisecond, Multiplier: 1.5}
+	require.Equal(t, 225*time.Millisecond, fractional.Delay(3))
+}
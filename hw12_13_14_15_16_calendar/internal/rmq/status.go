@@ -2,6 +2,11 @@ package rmq
 
 import "time"
 
+const (
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
 type NotificationStatus struct {
 	NotificationID string    `json:"notificationId"`
 	EventID        string    `json:"eventId"`
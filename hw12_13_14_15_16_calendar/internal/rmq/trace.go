@@ -0,0 +1,59 @@
+package rmq
+
+import (
+	"context"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/tracing"
+	"github.com/streadway/amqp"                        //nolint:depguard
+	semconv "go.opentelemetry.io/otel/semconv/v1.30.0" //nolint:depguard
+	"go.opentelemetry.io/otel/trace"                   //nolint:depguard
+)
+
+// headerCarrier carries the trace context in the headers of a message, so that the consumer continues
+// the trace of the publisher.
+type headerCarrier amqp.Table
+
+func (c headerCarrier) Get(key string) string {
+	value, _ := c[key].(string)
+	return value
+}
+
+func (c headerCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func startPublishSpan(ctx context.Context, exchange, routingKey string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "publish "+exchange,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitmq,
+			semconv.MessagingOperationTypePublish,
+			semconv.MessagingDestinationName(exchange),
+			semconv.MessagingRabbitmqDestinationRoutingKey(routingKey),
+		),
+	)
+}
+
+// startProcessSpan starts the span of processing the message under the span of its publisher. The span
+// ends when the consumer settles the delivery.
+func startProcessSpan(queue string, msg amqp.Delivery) trace.Span {
+	ctx := tracing.Extract(context.Background(), headerCarrier(msg.Headers))
+	_, span := tracing.Start(ctx, "process "+queue,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitmq,
+			semconv.MessagingOperationTypeProcess,
+			semconv.MessagingDestinationName(queue),
+			semconv.MessagingRabbitmqDestinationRoutingKey(msg.RoutingKey),
+		),
+	)
+	return span
+}
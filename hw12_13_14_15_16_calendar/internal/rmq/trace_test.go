@@ -0,0 +1,32 @@
+package rmq
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/tracing"
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestHeaderCarrier(t *testing.T) {
+	_, err := tracing.Init(context.Background(), tracing.Config{})
+	require.NoError(t, err)
+
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1, 2, 3},
+		SpanID:     trace.SpanID{4, 5, 6},
+		TraceFlags: trace.FlagsSampled,
+	})
+	headers := amqp.Table{attemptHeader: int64(2)}
+	tracing.Inject(trace.ContextWithSpanContext(context.Background(), parent), headerCarrier(headers))
+
+	require.Equal(t, int64(2), headers[attemptHeader])
+	require.IsType(t, "", headers["traceparent"])
+
+	extracted := trace.SpanContextFromContext(tracing.Extract(context.Background(), headerCarrier(headers)))
+	require.Equal(t, parent.TraceID(), extracted.TraceID())
+	require.Equal(t, parent.SpanID(), extracted.SpanID())
+	require.True(t, extracted.IsRemote())
+}
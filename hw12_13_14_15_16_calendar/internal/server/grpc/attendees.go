@@ -0,0 +1,79 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/mappers"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/types"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/proto/calendar"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *CalendarService) InviteAttendee(
+	ctx context.Context,
+	req *calendar.InviteAttendeeRequest,
+) (*calendar.InviteAttendeeResponse, error) {
+	if req.EventId == "" || req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "event_id and user_id are required")
+	}
+
+	attendee := types.Attendee{EventID: req.EventId, UserID: req.UserId}
+	if err := s.app.InviteAttendee(ctx, attendee); err != nil {
+		return nil, translateError(err)
+	}
+	return &calendar.InviteAttendeeResponse{Success: true}, nil
+}
+
+func (s *CalendarService) RespondToInvitation(
+	ctx context.Context,
+	req *calendar.RespondToInvitationRequest,
+) (*calendar.RespondToInvitationResponse, error) {
+	if req.EventId == "" || req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "event_id and user_id are required")
+	}
+	attendeeStatus, ok := mappers.AttendeeStatusFromProto(req.Status)
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "status is required")
+	}
+
+	attendee := types.Attendee{EventID: req.EventId, UserID: req.UserId, Status: attendeeStatus}
+	if err := s.app.RespondToInvitation(ctx, attendee); err != nil {
+		return nil, translateError(err)
+	}
+	return &calendar.RespondToInvitationResponse{Success: true}, nil
+}
+
+func (s *CalendarService) RemoveAttendee(
+	ctx context.Context,
+	req *calendar.RemoveAttendeeRequest,
+) (*calendar.RemoveAttendeeResponse, error) {
+	if req.EventId == "" || req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "event_id and user_id are required")
+	}
+
+	if err := s.app.RemoveAttendee(ctx, req.EventId, req.UserId); err != nil {
+		return nil, translateError(err)
+	}
+	return &calendar.RemoveAttendeeResponse{Success: true}, nil
+}
+
+func (s *CalendarService) ListAttendees(
+	ctx context.Context,
+	req *calendar.ListAttendeesRequest,
+) (*calendar.ListAttendeesResponse, error) {
+	if req.EventId == "" {
+		return nil, status.Error(codes.InvalidArgument, "event_id is required")
+	}
+
+	attendees, err := s.app.ListAttendees(ctx, req.EventId)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	resp := &calendar.ListAttendeesResponse{Attendees: make([]*calendar.Attendee, 0, len(attendees))}
+	for _, a := range attendees {
+		resp.Attendees = append(resp.Attendees, mappers.AttendeeToProto(a))
+	}
+	return resp, nil
+}
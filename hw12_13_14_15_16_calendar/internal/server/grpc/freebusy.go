@@ -0,0 +1,59 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/freebusy"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/types"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/proto/calendar"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *CalendarService) FindFreeBusy(
+	ctx context.Context,
+	req *calendar.FreeBusyRequest,
+) (*calendar.FreeBusyResponse, error) {
+	query := types.FreeBusyQuery{
+		UserIDs:  req.UserIds,
+		From:     time.Unix(req.From, 0),
+		To:       time.Unix(req.To, 0),
+		Duration: time.Duration(req.Duration) * time.Second,
+		Step:     time.Duration(req.Step) * time.Second,
+		Limit:    int(req.Limit),
+	}
+	if wh := req.WorkingHours; wh != nil {
+		weekdays := make([]int, 0, len(wh.Weekdays))
+		for _, d := range wh.Weekdays {
+			weekdays = append(weekdays, int(d))
+		}
+		hours, err := freebusy.ParseWorkingHours(wh.Start, wh.End, wh.TimeZone, weekdays)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		query.WorkingHours = hours
+	}
+
+	result, err := s.app.FreeBusy(ctx, query)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	resp := &calendar.FreeBusyResponse{
+		Busy:  make(map[string]*calendar.BusyIntervals, len(result.Busy)),
+		Slots: intervalsToProto(result.Slots),
+	}
+	for userID, intervals := range result.Busy {
+		resp.Busy[userID] = &calendar.BusyIntervals{Intervals: intervalsToProto(intervals)}
+	}
+	return resp, nil
+}
+
+func intervalsToProto(intervals []types.Interval) []*calendar.Interval {
+	result := make([]*calendar.Interval, 0, len(intervals))
+	for _, in := range intervals {
+		result = append(result, &calendar.Interval{Start: in.Start.Unix(), End: in.End.Unix()})
+	}
+	return result
+}
@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/ical"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/proto/calendar"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *CalendarService) ExportEvents(
+	ctx context.Context,
+	req *calendar.ExportEventsRequest,
+) (*calendar.ExportEventsResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	events, err := s.app.ListEventsByUser(ctx, req.UserId)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	if req.From != 0 || req.To != 0 {
+		occurrences, err := s.app.ListEventsByUserInRange(ctx, req.UserId, time.Unix(req.From, 0), time.Unix(req.To, 0))
+		if err != nil {
+			return nil, translateError(err)
+		}
+		events = ical.SelectSeries(events, occurrences)
+	}
+
+	return &calendar.ExportEventsResponse{Calendar: string(ical.Encode(events))}, nil
+}
+
+func (s *CalendarService) ImportEvents(
+	ctx context.Context,
+	req *calendar.ImportEventsRequest,
+) (*calendar.ImportEventsResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	items, err := ical.Decode(bytes.NewBufferString(req.Calendar))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	resp := &calendar.ImportEventsResponse{Results: make([]*calendar.ImportEventResult, 0, len(items))}
+	for _, item := range items {
+		result := &calendar.ImportEventResult{Uid: item.UID}
+
+		err := item.Err
+		if err == nil {
+			item.Event.UserID = req.UserId
+			result.Id, err = s.app.CreateEvent(ctx, item.Event)
+		}
+
+		result.Status = ical.ImportStatus(err)
+		if err != nil {
+			result.Error = err.Error()
+			resp.Failed++
+		} else {
+			resp.Created++
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	return resp, nil
+}
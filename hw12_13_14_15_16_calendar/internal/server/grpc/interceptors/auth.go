@@ -0,0 +1,124 @@
+package interceptors
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type Authenticator interface {
+	Authenticate(token string) (auth.Identity, error)
+}
+
+// publicServices are served without authentication; the health service answers probes and load
+// balancers, which carry no token.
+var publicServices = []string{
+	"/grpc.reflection.",
+	"/grpc.health.v1.Health/",
+}
+
+func UnaryAuthInterceptor(authenticator Authenticator, log Logger) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if isPublicMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		ctx, err := authenticate(ctx, authenticator)
+		if err != nil {
+			log.Warnf("gRPC authentication failed: method=%s error=%v", info.FullMethod, err)
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func StreamAuthInterceptor(authenticator Authenticator, log Logger) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if isPublicMethod(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		ctx, err := authenticate(ss.Context(), authenticator)
+		if err != nil {
+			log.Warnf("gRPC authentication failed: method=%s error=%v", info.FullMethod, err)
+			return err
+		}
+		return handler(srv, &identityStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// UnaryTrustedInterceptor serves the calls when authentication is disabled, with every caller trusted.
+func UnaryTrustedInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		_ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		return handler(auth.WithSystem(ctx), req)
+	}
+}
+
+// StreamTrustedInterceptor serves the streams when authentication is disabled, with every caller trusted.
+func StreamTrustedInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		_ *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		return handler(srv, &identityStream{ServerStream: ss, ctx: auth.WithSystem(ss.Context())})
+	}
+}
+
+func authenticate(ctx context.Context, authenticator Authenticator) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+
+	token, err := auth.BearerToken(values[0])
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	identity, err := authenticator.Authenticate(token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid bearer token")
+	}
+	return auth.WithIdentity(ctx, identity), nil
+}
+
+func isPublicMethod(method string) bool {
+	for _, prefix := range publicServices {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// identityStream overrides the stream context with the authenticated one.
+type identityStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *identityStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,83 @@
+package interceptors
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/auth"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/logger"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryAuthInterceptor(t *testing.T) {
+	key := auth.Key{Secret: "secret"}
+	authenticator, err := auth.NewJWTAuthenticator(auth.JWTConfig{Keys: []auth.Key{key}})
+	require.NoError(t, err)
+
+	token, err := auth.Sign(auth.NewClaims("user1", []string{auth.RoleAdmin}, time.Hour), key)
+	require.NoError(t, err)
+
+	interceptor := UnaryAuthInterceptor(authenticator, logger.New("error"))
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		identity, ok := auth.FromContext(ctx)
+		require.True(t, ok)
+		return identity, nil
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		header   string
+		wantCode codes.Code
+	}{
+		{name: "valid token", method: "/calendar.CalendarService/GetEventByID", header: "Bearer " + token},
+		{name: "missing token", method: "/calendar.CalendarService/GetEventByID", wantCode: codes.Unauthenticated},
+		{
+			name:     "invalid token",
+			method:   "/calendar.CalendarService/GetEventByID",
+			header:   "Bearer " + token + "x",
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "wrong scheme",
+			method:   "/calendar.CalendarService/GetEventByID",
+			header:   "Basic " + token,
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.header != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.header))
+			}
+
+			resp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if tt.wantCode != codes.OK {
+				require.Equal(t, tt.wantCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, auth.Identity{UserID: "user1", Roles: []string{auth.RoleAdmin}}, resp)
+		})
+	}
+
+	public := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		_, ok := auth.FromContext(ctx)
+		return ok, nil
+	}
+	for _, method := range []string{
+		"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo",
+		"/grpc.health.v1.Health/Check",
+	} {
+		resp, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, public)
+		require.NoError(t, err)
+		require.Equal(t, false, resp)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"time"
 
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/metrics"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 )
 
 type Logger interface {
@@ -28,6 +30,7 @@ func UnaryLoggerInterceptor(log Logger) grpc.UnaryServerInterceptor {
 		log.Debugf("Request payload: %+v", req)
 
 		resp, err := handler(ctx, req)
+		observe(info.FullMethod, start, err)
 
 		duration := time.Since(start).Milliseconds()
 		status := "success"
@@ -41,3 +44,36 @@ func UnaryLoggerInterceptor(log Logger) grpc.UnaryServerInterceptor {
 		return resp, err
 	}
 }
+
+func StreamLoggerInterceptor(log Logger) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		start := time.Now()
+
+		log.Infof("gRPC stream started: %s", info.FullMethod)
+
+		err := handler(srv, ss)
+		observe(info.FullMethod, start, err)
+
+		duration := time.Since(start).Milliseconds()
+		status := "success"
+		if err != nil {
+			status = "error"
+		}
+
+		log.Infof("gRPC stream finished: method=%s duration=%dms status=%s error=%v",
+			info.FullMethod, duration, status, err)
+
+		return err
+	}
+}
+
+// observe counts the call in the metrics by its status code.
+func observe(method string, start time.Time, err error) {
+	metrics.GRPCRequests.WithLabelValues(method, status.Code(err).String()).Inc()
+	metrics.GRPCDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,31 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/mappers"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/proto/calendar"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListNotifications returns the notifications of an event with their delivery history.
+func (s *CalendarService) ListNotifications(
+	ctx context.Context,
+	req *calendar.ListNotificationsRequest,
+) (*calendar.ListNotificationsResponse, error) {
+	if req.EventId == "" {
+		return nil, status.Error(codes.InvalidArgument, "event_id is required")
+	}
+
+	notifications, err := s.app.ListNotifications(ctx, req.EventId)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	resp := &calendar.ListNotificationsResponse{Notifications: make([]*calendar.Notification, 0, len(notifications))}
+	for _, n := range notifications {
+		resp.Notifications = append(resp.Notifications, mappers.NotificationToProto(n))
+	}
+	return resp, nil
+}
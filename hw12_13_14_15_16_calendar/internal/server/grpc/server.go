@@ -1,31 +1,57 @@
 package grpc
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net"
+	"strings"
+	"sync"
+	"time"
 
 	i "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/interface"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/server/grpc/interceptors"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/proto/calendar"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
+// stopTimeout bounds the wait for the calls in progress on Stop.
+const stopTimeout = 5 * time.Second
+
 type Server struct {
-	app i.Application
-	cfg ServerConfig
-	log i.Logger
+	app    i.Application
+	cfg    ServerConfig
+	log    i.Logger
+	auth   i.Authenticator
+	health *health.Server
+
+	mu      sync.Mutex
+	server  *grpc.Server
+	stopped bool
 }
 
 type ServerConfig struct {
 	Port string
 }
 
-func NewServer(app i.Application, cfg ServerConfig, log i.Logger) *Server {
+// NewServer creates the gRPC server; a nil authenticator disables authentication, and every caller is
+// trusted then. The server answers the gRPC health checking protocol, reporting itself serving from the
+// start of Run until Stop.
+func NewServer(app i.Application, cfg ServerConfig, log i.Logger, authenticator i.Authenticator) *Server {
+	healthServer := health.NewServer()
+	for _, service := range []string{"", calendar.CalendarService_ServiceDesc.ServiceName} {
+		healthServer.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+
 	return &Server{
-		app: app,
-		cfg: cfg,
-		log: log,
+		app:    app,
+		cfg:    cfg,
+		log:    log,
+		auth:   authenticator,
+		health: healthServer,
 	}
 }
 
@@ -35,16 +61,81 @@ func (s *Server) Run() error {
 		return fmt.Errorf("failed to listen: %w", err)
 	}
 
+	unary := []grpc.UnaryServerInterceptor{
+		interceptors.UnaryTracingInterceptor(),
+		interceptors.UnaryLoggerInterceptor(s.log),
+	}
+	stream := []grpc.StreamServerInterceptor{
+		interceptors.StreamTracingInterceptor(),
+		interceptors.StreamLoggerInterceptor(s.log),
+	}
+	if s.auth != nil {
+		unary = append(unary, interceptors.UnaryAuthInterceptor(s.auth, s.log))
+		stream = append(stream, interceptors.StreamAuthInterceptor(s.auth, s.log))
+	} else {
+		unary = append(unary, interceptors.UnaryTrustedInterceptor())
+		stream = append(stream, interceptors.StreamTrustedInterceptor())
+	}
+
 	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(interceptors.UnaryLoggerInterceptor(s.log)),
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
 	)
 	calendar.RegisterCalendarServiceServer(grpcServer, NewCalendarService(s.app))
+	healthpb.RegisterHealthServer(grpcServer, s.health)
 
 	reflection.Register(grpcServer)
 
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		_ = lis.Close()
+		return nil
+	}
+	s.server = grpcServer
+	s.health.Resume()
+	s.mu.Unlock()
+
 	s.log.Infof("Starting gRPC server, port %s", s.cfg.Port)
-	if err := grpcServer.Serve(lis); err != nil {
+	if err := grpcServer.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
 		return fmt.Errorf("failed to serve: %w", err)
 	}
 	return nil
 }
+
+// Ready fails unless the server is serving; it is the readiness check of the gRPC API.
+func (s *Server) Ready(ctx context.Context) error {
+	resp, err := s.health.Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		return err
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("grpc server is %s", strings.ToLower(resp.GetStatus().String()))
+	}
+	return nil
+}
+
+// Stop reports the server not serving, so that clients watching its health move away, and then stops it
+// after the calls in progress finish. Watch streams last until their clients leave, so the server stops
+// regardless once stopTimeout passes.
+func (s *Server) Stop() {
+	s.mu.Lock()
+	s.stopped = true
+	s.health.Shutdown()
+	grpcServer := s.server
+	s.mu.Unlock()
+	if grpcServer == nil {
+		return
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(stopTimeout):
+		grpcServer.Stop()
+	}
+}
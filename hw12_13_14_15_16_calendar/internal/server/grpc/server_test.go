@@ -0,0 +1,70 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/auth"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/logger"
+	pb "github.com/dimryb/go-hw/hw12_13_14_15_calendar/proto/calendar"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestServerReadiness(t *testing.T) {
+	ctx := context.Background()
+	server := NewServer(nil, ServerConfig{Port: "0"}, logger.New("error"), nil)
+	require.Error(t, server.Ready(ctx))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Run()
+	}()
+	require.Eventually(t, func() bool { return server.Ready(ctx) == nil }, time.Second, 5*time.Millisecond)
+
+	server.Stop()
+	require.NoError(t, <-done)
+	require.ErrorContains(t, server.Ready(ctx), "not_serving")
+}
+
+func TestServerHealthWithAuth(t *testing.T) {
+	authenticator, err := auth.NewJWTAuthenticator(auth.JWTConfig{Keys: []auth.Key{{Secret: "secret"}}})
+	require.NoError(t, err)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := lis.Addr().(*net.TCPAddr)
+	require.NoError(t, lis.Close())
+
+	ctx := context.Background()
+	server := NewServer(nil, ServerConfig{Port: fmt.Sprint(addr.Port)}, logger.New("error"), authenticator)
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Run()
+	}()
+	defer func() {
+		server.Stop()
+		require.NoError(t, <-done)
+	}()
+	require.Eventually(t, func() bool { return server.Ready(ctx) == nil }, time.Second, 5*time.Millisecond)
+
+	conn, err := grpc.NewClient(addr.String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{
+		Service: pb.CalendarService_ServiceDesc.ServiceName,
+	})
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+
+	_, err = pb.NewCalendarServiceClient(conn).GetEventByID(ctx, &pb.GetEventByIDRequest{Id: "event-001"})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
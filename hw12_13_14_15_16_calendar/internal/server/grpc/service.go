@@ -5,6 +5,8 @@ import (
 	"errors"
 	"time"
 
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/auth"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/freebusy"
 	i "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/interface"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/mappers"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
@@ -15,10 +17,21 @@ import (
 )
 
 var (
-	ErrEventNotFound   = status.Error(codes.NotFound, "event not found")
-	ErrAlreadyExists   = status.Error(codes.AlreadyExists, "event already exists")
-	ErrConflictOverlap = status.Error(codes.FailedPrecondition, "event overlaps with existing one")
-	ErrInternal        = status.Error(codes.Internal, "internal server error")
+	ErrEventNotFound    = status.Error(codes.NotFound, "event not found")
+	ErrAlreadyExists    = status.Error(codes.AlreadyExists, "event already exists")
+	ErrConflictOverlap  = status.Error(codes.FailedPrecondition, "event overlaps with existing one")
+	ErrInvalidEvent     = status.Error(codes.InvalidArgument, "invalid event data")
+	ErrInvalidPageToken = status.Error(codes.InvalidArgument, "invalid page token")
+	ErrPermissionDenied = status.Error(codes.PermissionDenied, "access to the event is forbidden")
+	ErrUnauthenticated  = status.Error(codes.Unauthenticated, "unauthenticated")
+	ErrAttendeeNotFound = status.Error(codes.NotFound, "attendee not found")
+	ErrAttendeeExists   = status.Error(codes.AlreadyExists, "user is already invited")
+	ErrInvalidAttendee  = status.Error(codes.InvalidArgument, "invalid attendee")
+	ErrVersionConflict  = status.Error(codes.Aborted, "event version does not match")
+	ErrVersionRequired  = status.Error(codes.FailedPrecondition, "event version is required")
+	ErrDeadlineExceeded = status.Error(codes.DeadlineExceeded, "request deadline exceeded")
+	ErrCanceled         = status.Error(codes.Canceled, "request canceled")
+	ErrInternal         = status.Error(codes.Internal, "internal server error")
 )
 
 type Storage interface {
@@ -44,12 +57,34 @@ func NewCalendarService(app i.Application) *CalendarService {
 
 func translateError(err error) error {
 	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrDeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return ErrCanceled
 	case errors.Is(err, storagecommon.ErrEventNotFound):
 		return ErrEventNotFound
 	case errors.Is(err, storagecommon.ErrAlreadyExists):
 		return ErrAlreadyExists
 	case errors.Is(err, storagecommon.ErrConflictOverlap):
 		return ErrConflictOverlap
+	case errors.Is(err, storagecommon.ErrInvalidEvent):
+		return ErrInvalidEvent
+	case errors.Is(err, storagecommon.ErrInvalidPageToken):
+		return ErrInvalidPageToken
+	case errors.Is(err, auth.ErrForbidden):
+		return ErrPermissionDenied
+	case errors.Is(err, auth.ErrUnauthenticated):
+		return ErrUnauthenticated
+	case errors.Is(err, storagecommon.ErrAttendeeNotFound):
+		return ErrAttendeeNotFound
+	case errors.Is(err, storagecommon.ErrAttendeeExists):
+		return ErrAttendeeExists
+	case errors.Is(err, storagecommon.ErrInvalidAttendee):
+		return ErrInvalidAttendee
+	case errors.Is(err, storagecommon.ErrVersionConflict):
+		return ErrVersionConflict
+	case errors.Is(err, freebusy.ErrInvalidQuery):
+		return status.Error(codes.InvalidArgument, err.Error())
 	default:
 		return ErrInternal
 	}
@@ -74,18 +109,27 @@ func (s *CalendarService) UpdateEvent(
 	ctx context.Context,
 	event *calendar.Event,
 ) (*calendar.UpdateEventResponse, error) {
+	if event.Version == 0 {
+		return nil, ErrVersionRequired
+	}
+
 	domainEvent := mappers.ProtoToDomain(event)
-	if err := s.app.UpdateEvent(ctx, domainEvent); err != nil {
+	version, err := s.app.UpdateEvent(ctx, domainEvent)
+	if err != nil {
 		return nil, translateError(err)
 	}
-	return &calendar.UpdateEventResponse{Success: true}, nil
+	return &calendar.UpdateEventResponse{Success: true, Version: version}, nil
 }
 
 func (s *CalendarService) DeleteEvent(
 	ctx context.Context,
 	req *calendar.DeleteEventRequest,
 ) (*calendar.DeleteEventResponse, error) {
-	if err := s.app.DeleteEvent(ctx, req.Id); err != nil {
+	if req.Version == 0 {
+		return nil, ErrVersionRequired
+	}
+
+	if err := s.app.DeleteEvent(ctx, req.Id, req.Version); err != nil {
 		return nil, translateError(err)
 	}
 	return &calendar.DeleteEventResponse{Success: true}, nil
@@ -106,17 +150,33 @@ func (s *CalendarService) GetEventByID(
 
 func (s *CalendarService) ListEvents(
 	ctx context.Context,
-	_ *calendar.ListEventsRequest,
+	req *calendar.ListEventsRequest,
 ) (*calendar.ListEventsResponse, error) {
-	events, err := s.app.ListEvents(ctx)
+	if req.PageSize < 0 {
+		return nil, status.Error(codes.InvalidArgument, "page size must not be negative")
+	}
+
+	query := types.ListEventsQuery{
+		Title:     req.Title,
+		PageSize:  int(req.PageSize),
+		PageToken: req.PageToken,
+	}
+	if req.From != 0 {
+		query.From = time.Unix(req.From, 0)
+	}
+	if req.To != 0 {
+		query.To = time.Unix(req.To, 0)
+	}
+
+	page, err := s.app.ListEvents(ctx, query)
 	if err != nil {
 		return nil, translateError(err)
 	}
-	protoEvents := make([]*calendar.Event, 0, len(events))
-	for _, e := range events {
+	protoEvents := make([]*calendar.Event, 0, len(page.Events))
+	for _, e := range page.Events {
 		protoEvents = append(protoEvents, mappers.DomainToProto(e))
 	}
-	return &calendar.ListEventsResponse{Events: protoEvents}, nil
+	return &calendar.ListEventsResponse{Events: protoEvents, NextPageToken: page.NextPageToken}, nil
 }
 
 func (s *CalendarService) ListEventsByUser(
@@ -2,17 +2,25 @@ package grpc
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"testing"
 	"time"
 
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/changefeed"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/mappers"
 	storagecommon "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/types"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/mocks"
 	pb "github.com/dimryb/go-hw/hw12_13_14_15_calendar/proto/calendar"
 	"github.com/golang/mock/gomock" //nolint:depguard
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 )
 
 func TestCreateEvent(t *testing.T) {
@@ -83,39 +91,50 @@ func TestCreateEvent(t *testing.T) {
 }
 
 func TestUpdateEvent(t *testing.T) {
+	event := func(version int64) *pb.Event {
+		return &pb.Event{
+			Id:           "event-001",
+			UserId:       "user-001",
+			Title:        "Updated Title",
+			Description:  "Updated Description",
+			StartTime:    time.Now().Unix() + 3600,
+			EndTime:      time.Now().Unix() + 7200,
+			NotifyBefore: 3600,
+			Version:      version,
+		}
+	}
+
 	tests := []struct {
-		name        string
-		event       *pb.Event
-		mockError   error
-		expectError bool
+		name      string
+		event     *pb.Event
+		mockCall  bool
+		mockError error
+		wantCode  codes.Code
 	}{
 		{
-			name: "Valid Update",
-			event: &pb.Event{
-				Id:           "event-001",
-				UserId:       "user-001",
-				Title:        "Updated Title",
-				Description:  "Updated Description",
-				StartTime:    time.Now().Unix() + 3600,
-				EndTime:      time.Now().Unix() + 7200,
-				NotifyBefore: 3600,
-			},
-			mockError:   nil,
-			expectError: false,
+			name:     "Valid Update",
+			event:    event(1),
+			mockCall: true,
+			wantCode: codes.OK,
 		},
 		{
-			name: "NotFound",
-			event: &pb.Event{
-				Id:           "event-001",
-				UserId:       "user-001",
-				Title:        "Updated Title",
-				Description:  "Updated Description",
-				StartTime:    time.Now().Unix() + 3600,
-				EndTime:      time.Now().Unix() + 7200,
-				NotifyBefore: 3600,
-			},
-			mockError:   status.Error(codes.NotFound, "not found"),
-			expectError: true,
+			name:      "NotFound",
+			event:     event(1),
+			mockCall:  true,
+			mockError: storagecommon.ErrEventNotFound,
+			wantCode:  codes.NotFound,
+		},
+		{
+			name:      "Stale version",
+			event:     event(1),
+			mockCall:  true,
+			mockError: storagecommon.ErrVersionConflict,
+			wantCode:  codes.Aborted,
+		},
+		{
+			name:     "Missing version",
+			event:    event(0),
+			wantCode: codes.FailedPrecondition,
 		},
 	}
 
@@ -127,19 +146,22 @@ func TestUpdateEvent(t *testing.T) {
 			mockApp := mocks.NewMockApplication(ctrl)
 			service := &CalendarService{app: mockApp}
 
-			domainEvent := mappers.ProtoToDomain(tt.event)
-
-			mockApp.EXPECT().
-				UpdateEvent(gomock.Any(), domainEvent).
-				Return(tt.mockError)
+			if tt.mockCall {
+				var version int64
+				if tt.mockError == nil {
+					version = tt.event.Version + 1
+				}
+				mockApp.EXPECT().
+					UpdateEvent(gomock.Any(), mappers.ProtoToDomain(tt.event)).
+					Return(version, tt.mockError)
+			}
 
 			resp, err := service.UpdateEvent(context.Background(), tt.event)
 
-			if tt.expectError {
-				assert.Error(t, err)
-			} else {
-				assert.NoError(t, err)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode == codes.OK {
 				assert.True(t, resp.Success)
+				assert.Equal(t, int64(2), resp.Version)
 			}
 		})
 	}
@@ -147,22 +169,40 @@ func TestUpdateEvent(t *testing.T) {
 
 func TestDeleteEvent(t *testing.T) {
 	tests := []struct {
-		name        string
-		id          string
-		mockError   error
-		expectError bool
+		name      string
+		id        string
+		version   int64
+		mockCall  bool
+		mockError error
+		wantCode  codes.Code
 	}{
 		{
-			name:        "Success",
-			id:          "event-001",
-			mockError:   nil,
-			expectError: false,
+			name:     "Success",
+			id:       "event-001",
+			version:  1,
+			mockCall: true,
+			wantCode: codes.OK,
 		},
 		{
-			name:        "NotFound",
-			id:          "event-002",
-			mockError:   status.Error(codes.NotFound, "not found"),
-			expectError: true,
+			name:      "NotFound",
+			id:        "event-002",
+			version:   1,
+			mockCall:  true,
+			mockError: storagecommon.ErrEventNotFound,
+			wantCode:  codes.NotFound,
+		},
+		{
+			name:      "Stale version",
+			id:        "event-001",
+			version:   1,
+			mockCall:  true,
+			mockError: storagecommon.ErrVersionConflict,
+			wantCode:  codes.Aborted,
+		},
+		{
+			name:     "Missing version",
+			id:       "event-001",
+			wantCode: codes.FailedPrecondition,
 		},
 	}
 
@@ -174,17 +214,17 @@ func TestDeleteEvent(t *testing.T) {
 			mockApp := mocks.NewMockApplication(ctrl)
 			service := &CalendarService{app: mockApp}
 
-			mockApp.EXPECT().
-				DeleteEvent(gomock.Any(), tt.id).
-				Return(tt.mockError)
+			if tt.mockCall {
+				mockApp.EXPECT().
+					DeleteEvent(gomock.Any(), tt.id, tt.version).
+					Return(tt.mockError)
+			}
 
-			req := &pb.DeleteEventRequest{Id: tt.id}
+			req := &pb.DeleteEventRequest{Id: tt.id, Version: tt.version}
 			resp, err := service.DeleteEvent(context.Background(), req)
 
-			if tt.expectError {
-				assert.Error(t, err)
-			} else {
-				assert.NoError(t, err)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode == codes.OK {
 				assert.True(t, resp.Success)
 			}
 		})
@@ -198,6 +238,7 @@ func TestGetEventByID(t *testing.T) {
 		mockEvent   storagecommon.Event
 		mockError   error
 		expectError bool
+		wantCode    codes.Code
 	}{
 		{
 			name: "Found",
@@ -221,6 +262,13 @@ func TestGetEventByID(t *testing.T) {
 			mockError:   status.Error(codes.NotFound, "not found"),
 			expectError: true,
 		},
+		{
+			name:        "DeadlineExceeded",
+			id:          "event-003",
+			mockError:   fmt.Errorf("failed to get event: %w", context.DeadlineExceeded),
+			expectError: true,
+			wantCode:    codes.DeadlineExceeded,
+		},
 	}
 
 	for _, tt := range tests {
@@ -242,6 +290,9 @@ func TestGetEventByID(t *testing.T) {
 
 			if tt.expectError {
 				assert.Error(t, err)
+				if tt.wantCode != codes.OK {
+					assert.Equal(t, tt.wantCode, status.Code(err))
+				}
 			} else {
 				assert.NoError(t, err)
 				assert.NotNil(t, resp.Event)
@@ -468,8 +519,8 @@ func TestListEvents(t *testing.T) {
 			domainEvents := mappers.ToDomainEvents(tt.mockEvents)
 
 			mockApp.EXPECT().
-				ListEvents(gomock.Any()).
-				Return(domainEvents, tt.mockError)
+				ListEvents(gomock.Any(), gomock.Any()).
+				Return(types.EventPage{Events: domainEvents}, tt.mockError)
 
 			req := &pb.ListEventsRequest{}
 			resp, err := service.ListEvents(context.Background(), req)
@@ -483,3 +534,269 @@ func TestListEvents(t *testing.T) {
 		})
 	}
 }
+
+func TestListEvents_Query(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockApp := mocks.NewMockApplication(ctrl)
+	service := &CalendarService{app: mockApp}
+
+	mockApp.EXPECT().
+		ListEvents(gomock.Any(), types.ListEventsQuery{
+			Title:     "sync",
+			From:      time.Unix(1717290000, 0),
+			PageSize:  10,
+			PageToken: "token",
+		}).
+		Return(types.EventPage{NextPageToken: "next"}, nil)
+
+	resp, err := service.ListEvents(context.Background(), &pb.ListEventsRequest{
+		PageSize:  10,
+		PageToken: "token",
+		Title:     "sync",
+		From:      1717290000,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "next", resp.NextPageToken)
+
+	mockApp.EXPECT().
+		ListEvents(gomock.Any(), gomock.Any()).
+		Return(types.EventPage{}, storagecommon.ErrInvalidPageToken)
+
+	_, err = service.ListEvents(context.Background(), &pb.ListEventsRequest{PageToken: "bad"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = service.ListEvents(context.Background(), &pb.ListEventsRequest{PageSize: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestImportEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockApp := mocks.NewMockApplication(ctrl)
+	service := &CalendarService{app: mockApp}
+
+	doc := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\nUID:a\r\nDTSTART:20250602T100000Z\r\nDTEND:20250602T110000Z\r\nSUMMARY:A\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:b\r\nDTSTART:20250602T103000Z\r\nDTEND:20250602T113000Z\r\nSUMMARY:B\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	mockApp.EXPECT().
+		CreateEvent(gomock.Any(), gomock.Any()).
+		Return("event-001", nil)
+	mockApp.EXPECT().
+		CreateEvent(gomock.Any(), gomock.Any()).
+		Return("", storagecommon.ErrConflictOverlap)
+
+	resp, err := service.ImportEvents(context.Background(), &pb.ImportEventsRequest{
+		UserId:   "user-001",
+		Calendar: doc,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), resp.Created)
+	assert.Equal(t, int32(1), resp.Failed)
+	assert.Equal(t, "event-001", resp.Results[0].Id)
+	assert.Equal(t, "overlap", resp.Results[1].Status)
+
+	_, err = service.ImportEvents(context.Background(), &pb.ImportEventsRequest{UserId: "user-001"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestAttendees(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockApp := mocks.NewMockApplication(ctrl)
+	service := &CalendarService{app: mockApp}
+	ctx := context.Background()
+
+	mockApp.EXPECT().
+		InviteAttendee(gomock.Any(), types.Attendee{EventID: "event-001", UserID: "user-002"}).
+		Return(storagecommon.ErrAttendeeExists)
+	_, err := service.InviteAttendee(ctx, &pb.InviteAttendeeRequest{EventId: "event-001", UserId: "user-002"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	mockApp.EXPECT().
+		RespondToInvitation(gomock.Any(), types.Attendee{
+			EventID: "event-001", UserID: "user-002", Status: storagecommon.AttendeeAccepted,
+		}).
+		Return(nil)
+	resp, err := service.RespondToInvitation(ctx, &pb.RespondToInvitationRequest{
+		EventId: "event-001", UserId: "user-002", Status: pb.AttendeeStatus_ATTENDEE_STATUS_ACCEPTED,
+	})
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+
+	_, err = service.RespondToInvitation(ctx, &pb.RespondToInvitationRequest{EventId: "event-001", UserId: "user-002"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	mockApp.EXPECT().
+		RemoveAttendee(gomock.Any(), "event-001", "user-003").
+		Return(storagecommon.ErrAttendeeNotFound)
+	_, err = service.RemoveAttendee(ctx, &pb.RemoveAttendeeRequest{EventId: "event-001", UserId: "user-003"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	mockApp.EXPECT().
+		ListAttendees(gomock.Any(), "event-001").
+		Return([]types.Attendee{
+			{EventID: "event-001", UserID: "user-002", Status: storagecommon.AttendeeTentative},
+		}, nil)
+	list, err := service.ListAttendees(ctx, &pb.ListAttendeesRequest{EventId: "event-001"})
+	require.NoError(t, err)
+	require.Len(t, list.Attendees, 1)
+	assert.Equal(t, pb.AttendeeStatus_ATTENDEE_STATUS_TENTATIVE, list.Attendees[0].Status)
+}
+
+func TestListNotifications(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockApp := mocks.NewMockApplication(ctrl)
+	service := &CalendarService{app: mockApp}
+	ctx := context.Background()
+
+	occurrence := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	mockApp.EXPECT().
+		ListNotifications(gomock.Any(), "event-001").
+		Return([]types.Notification{{
+			ID:         "notification-001",
+			EventID:    "event-001",
+			Occurrence: occurrence,
+			NotifyAt:   occurrence.Add(-10 * time.Minute),
+			State:      storagecommon.NotificationDelivered,
+			Statuses:   []types.NotificationStatus{{Status: "delivered", ReportedAt: occurrence.Add(-9 * time.Minute)}},
+		}}, nil)
+	resp, err := service.ListNotifications(ctx, &pb.ListNotificationsRequest{EventId: "event-001"})
+	require.NoError(t, err)
+	require.Len(t, resp.Notifications, 1)
+	assert.Equal(t, pb.NotificationState_NOTIFICATION_STATE_DELIVERED, resp.Notifications[0].State)
+	assert.Equal(t, occurrence.Unix(), resp.Notifications[0].Occurrence)
+	require.Len(t, resp.Notifications[0].Statuses, 1)
+	assert.Equal(t, occurrence.Add(-9*time.Minute).Unix(), resp.Notifications[0].Statuses[0].Timestamp)
+
+	mockApp.EXPECT().ListNotifications(gomock.Any(), "missing").Return(nil, storagecommon.ErrEventNotFound)
+	_, err = service.ListNotifications(ctx, &pb.ListNotificationsRequest{EventId: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = service.ListNotifications(ctx, &pb.ListNotificationsRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestFindFreeBusy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockApp := mocks.NewMockApplication(ctrl)
+	service := &CalendarService{app: mockApp}
+
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	mockApp.EXPECT().
+		FreeBusy(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, q types.FreeBusyQuery) (types.FreeBusy, error) {
+			assert.Equal(t, []string{"user-001"}, q.UserIDs)
+			assert.Equal(t, time.Hour, q.Duration)
+			assert.Equal(t, 9*time.Hour, q.WorkingHours.Start)
+			return types.FreeBusy{
+				Busy:  map[string][]types.Interval{"user-001": {{Start: start, End: start.Add(time.Hour)}}},
+				Slots: []types.Interval{{Start: start.Add(time.Hour), End: start.Add(2 * time.Hour)}},
+			}, nil
+		})
+
+	resp, err := service.FindFreeBusy(context.Background(), &pb.FreeBusyRequest{
+		UserIds:      []string{"user-001"},
+		From:         start.Unix(),
+		To:           start.Add(8 * time.Hour).Unix(),
+		Duration:     3600,
+		WorkingHours: &pb.WorkingHours{Start: "09:00", End: "18:00"},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Busy["user-001"].Intervals, 1)
+	require.Len(t, resp.Slots, 1)
+	assert.Equal(t, start.Add(time.Hour).Unix(), resp.Slots[0].Start)
+
+	_, err = service.FindFreeBusy(context.Background(), &pb.FreeBusyRequest{
+		UserIds:      []string{"user-001"},
+		WorkingHours: &pb.WorkingHours{Start: "09:00", End: "18:00", TimeZone: "Nowhere/Land"},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestWatchEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	feed := changefeed.New(10)
+	mockApp := mocks.NewMockApplication(ctrl)
+	mockApp.EXPECT().
+		WatchEvents(gomock.Any(), "user-001", gomock.Any()).
+		DoAndReturn(func(_ context.Context, userID, revision string) (*changefeed.Subscription, error) {
+			return feed.Subscribe(userID, revision)
+		}).
+		AnyTimes()
+
+	client := newBufconnClient(t, &CalendarService{app: mockApp})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.WatchEvents(ctx, &pb.WatchEventsRequest{UserId: "user-001"})
+	require.NoError(t, err)
+	header, err := stream.Header()
+	require.NoError(t, err)
+	require.Len(t, header.Get(RevisionHeader), 1)
+
+	feed.Publish(changefeed.OpCreated, types.Event{ID: "event-001", UserID: "user-001", Title: "Created"})
+	feed.Publish(changefeed.OpCreated, types.Event{ID: "event-002", UserID: "user-002"})
+	feed.Publish(changefeed.OpDeleted, types.Event{ID: "event-001", UserID: "user-001"})
+
+	change, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, pb.ChangeType_CHANGE_TYPE_CREATED, change.Type)
+	assert.Equal(t, "Created", change.Event.Title)
+
+	change, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, pb.ChangeType_CHANGE_TYPE_DELETED, change.Type)
+	assert.Equal(t, "event-001", change.Event.Id)
+
+	resumed, err := client.WatchEvents(ctx, &pb.WatchEventsRequest{
+		UserId:   "user-001",
+		Revision: header.Get(RevisionHeader)[0],
+	})
+	require.NoError(t, err)
+	for _, want := range []pb.ChangeType{pb.ChangeType_CHANGE_TYPE_CREATED, pb.ChangeType_CHANGE_TYPE_DELETED} {
+		change, err := resumed.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, want, change.Type)
+	}
+
+	invalid, err := client.WatchEvents(ctx, &pb.WatchEventsRequest{UserId: "user-001", Revision: "garbage"})
+	require.NoError(t, err)
+	_, err = invalid.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func newBufconnClient(t *testing.T, service *CalendarService) pb.CalendarServiceClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pb.RegisterCalendarServiceServer(server, service)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return pb.NewCalendarServiceClient(conn)
+}
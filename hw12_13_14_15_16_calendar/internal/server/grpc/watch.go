@@ -0,0 +1,77 @@
+package grpc
+
+import (
+	"errors"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/changefeed"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/mappers"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/proto/calendar"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RevisionHeader carries the revision the stream starts from, so a client can resume
+// from it even if the stream ends before any change is received.
+const RevisionHeader = "x-revision"
+
+var changeTypes = map[changefeed.Op]calendar.ChangeType{
+	changefeed.OpCreated: calendar.ChangeType_CHANGE_TYPE_CREATED,
+	changefeed.OpUpdated: calendar.ChangeType_CHANGE_TYPE_UPDATED,
+	changefeed.OpDeleted: calendar.ChangeType_CHANGE_TYPE_DELETED,
+}
+
+func (s *CalendarService) WatchEvents(
+	req *calendar.WatchEventsRequest,
+	stream grpc.ServerStreamingServer[calendar.EventChange],
+) error {
+	if req.UserId == "" {
+		return status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	ctx := stream.Context()
+	sub, err := s.app.WatchEvents(ctx, req.UserId, req.Revision)
+	if err != nil {
+		return translateWatchError(err)
+	}
+	defer sub.Close()
+
+	if err := stream.SendHeader(metadata.Pairs(RevisionHeader, sub.Token())); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case change, ok := <-sub.Changes():
+			if !ok {
+				return translateWatchError(sub.Err())
+			}
+			err := stream.Send(&calendar.EventChange{
+				Type:     changeTypes[change.Op],
+				Event:    mappers.DomainToProto(change.Event),
+				Revision: change.Token,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func translateWatchError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, changefeed.ErrInvalidRevision):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, changefeed.ErrRevisionExpired):
+		return status.Error(codes.OutOfRange, err.Error())
+	case errors.Is(err, changefeed.ErrSlowSubscriber):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		return translateError(err)
+	}
+}
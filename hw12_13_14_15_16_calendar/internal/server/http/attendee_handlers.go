@@ -0,0 +1,169 @@
+package internalhttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	storagecommon "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/types"
+)
+
+// InviteAttendee godoc
+// @Summary      Invite a user to an event
+// @Description  Invite a user to an event owned by the caller; the invitation starts as needs-action
+// @Tags         attendees
+// @Accept       json
+// @Produce      json
+// @Param        attendee body InviteAttendeeRequest true "Invitation"
+// @Success      201 {object} map[string]string
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /event/attendees/invite [post].
+func (h *CalendarHandlers) InviteAttendee(w http.ResponseWriter, r *http.Request) {
+	var req InviteAttendeeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Invalid request body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.EventID == "" || req.UserID == "" {
+		http.Error(w, "EventID and UserID are required", http.StatusBadRequest)
+		return
+	}
+
+	attendee := types.Attendee{EventID: req.EventID, UserID: req.UserID}
+	if err := h.app.InviteAttendee(r.Context(), attendee); err != nil {
+		h.logger.Errorf("Failed to invite attendee: %v", err)
+		http.Error(w, fmt.Sprintf("Failed to invite attendee: %v", err), errorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	h.writeStatus(w, http.StatusCreated, "invited")
+}
+
+// RespondToInvitation godoc
+// @Summary      Respond to an invitation
+// @Description  Accept, decline or tentatively accept an invitation; accepting checks for overlaps
+// @Tags         attendees
+// @Accept       json
+// @Produce      json
+// @Param        response body RespondToInvitationRequest true "RSVP"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /event/attendees/respond [post].
+func (h *CalendarHandlers) RespondToInvitation(w http.ResponseWriter, r *http.Request) {
+	var req RespondToInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Invalid request body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.EventID == "" || req.UserID == "" {
+		http.Error(w, "EventID and UserID are required", http.StatusBadRequest)
+		return
+	}
+
+	if !storagecommon.ValidAttendeeStatus(req.Status) {
+		http.Error(w, "Invalid status", http.StatusBadRequest)
+		return
+	}
+
+	attendee := types.Attendee{EventID: req.EventID, UserID: req.UserID, Status: req.Status}
+	if err := h.app.RespondToInvitation(r.Context(), attendee); err != nil {
+		h.logger.Errorf("Failed to respond to invitation: %v", err)
+		http.Error(w, fmt.Sprintf("Failed to respond to invitation: %v", err),
+			errorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	h.writeStatus(w, http.StatusOK, req.Status)
+}
+
+// RemoveAttendee godoc
+// @Summary      Remove an attendee
+// @Description  Withdraw an invitation; allowed to the owner of the event and to the attendee
+// @Tags         attendees
+// @Produce      json
+// @Param        eventId query string true "Event ID"
+// @Param        userId  query string true "User ID of the attendee"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /event/attendees/remove [delete].
+func (h *CalendarHandlers) RemoveAttendee(w http.ResponseWriter, r *http.Request) {
+	eventID := r.URL.Query().Get("eventId")
+	userID := r.URL.Query().Get("userId")
+	if eventID == "" || userID == "" {
+		http.Error(w, "EventID and UserID are required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.app.RemoveAttendee(r.Context(), eventID, userID); err != nil {
+		h.logger.Errorf("Failed to remove attendee: %v", err)
+		http.Error(w, fmt.Sprintf("Failed to remove attendee: %v", err), errorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	h.writeStatus(w, http.StatusOK, "removed")
+}
+
+// ListAttendees godoc
+// @Summary      List attendees of an event
+// @Description  Retrieve the attendees of an event with their RSVP status
+// @Tags         attendees
+// @Produce      json
+// @Param        eventId query string true "Event ID"
+// @Success      200 {object} ListAttendeesResponse
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /event/attendees [get].
+func (h *CalendarHandlers) ListAttendees(w http.ResponseWriter, r *http.Request) {
+	eventID := r.URL.Query().Get("eventId")
+	if eventID == "" {
+		http.Error(w, "EventID is required", http.StatusBadRequest)
+		return
+	}
+
+	attendees, err := h.app.ListAttendees(r.Context(), eventID)
+	if err != nil {
+		h.logger.Errorf("Failed to list attendees: %v", err)
+		http.Error(w, fmt.Sprintf("Failed to list attendees: %v", err), errorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(ToListAttendeesResponse(attendees)); err != nil {
+		h.logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+func (h *CalendarHandlers) writeStatus(w http.ResponseWriter, code int, status string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(map[string]string{"status": status}); err != nil {
+		h.logger.Errorf("Failed to encode response: %v", err)
+	}
+}
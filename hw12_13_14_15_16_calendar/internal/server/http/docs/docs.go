@@ -16,35 +16,35 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/event/create": {
-            "post": {
-                "description": "Create a new calendar event",
-                "consumes": [
-                    "application/json"
+        "/event/attendees": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Retrieve the attendees of an event with their RSVP status",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "attendees"
                 ],
-                "summary": "Create a new event",
+                "summary": "List attendees of an event",
                 "parameters": [
                     {
-                        "description": "Event data",
-                        "name": "event",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internalhttp.CreateEventRequest"
-                        }
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "eventId",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internalhttp.CreateEventResponse"
+                            "$ref": "#/definitions/internalhttp.ListAttendeesResponse"
                         }
                     },
                     "400": {
@@ -56,6 +56,33 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -68,28 +95,38 @@ const docTemplate = `{
                 }
             }
         },
-        "/event/delete": {
-            "delete": {
-                "description": "Delete an event by ID",
+        "/event/attendees/invite": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Invite a user to an event owned by the caller; the invitation starts as needs-action",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "attendees"
                 ],
-                "summary": "Delete an event",
+                "summary": "Invite a user to an event",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Event ID",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
+                        "description": "Invitation",
+                        "name": "attendee",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internalhttp.InviteAttendeeRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -106,6 +143,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -115,6 +170,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -127,21 +191,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/event/get": {
-            "get": {
-                "description": "Retrieve an event from the database by its ID",
+        "/event/attendees/remove": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Withdraw an invitation; allowed to the owner of the event and to the attendee",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "attendees"
                 ],
-                "summary": "Get event by ID",
+                "summary": "Remove an attendee",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Event ID",
-                        "name": "id",
+                        "name": "eventId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID of the attendee",
+                        "name": "userId",
                         "in": "query",
                         "required": true
                     }
@@ -150,7 +226,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internalhttp.EventResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -162,6 +241,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -183,9 +280,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/event/update": {
+        "/event/attendees/respond": {
             "post": {
-                "description": "Update an event by its ID",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Accept, decline or tentatively accept an invitation; accepting checks for overlaps",
                 "consumes": [
                     "application/json"
                 ],
@@ -193,17 +295,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "attendees"
                 ],
-                "summary": "Update an existing event",
+                "summary": "Respond to an invitation",
                 "parameters": [
                     {
-                        "description": "Updated event data",
-                        "name": "event",
+                        "description": "RSVP",
+                        "name": "response",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internalhttp.UpdateEventRequest"
+                            "$ref": "#/definitions/internalhttp.RespondToInvitationRequest"
                         }
                     }
                 ],
@@ -226,6 +328,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -235,6 +355,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -247,21 +376,76 @@ const docTemplate = `{
                 }
             }
         },
-        "/events/list": {
-            "get": {
-                "description": "Retrieve a list of all events",
+        "/event/create": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new calendar event",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "events"
                 ],
-                "summary": "Get all events",
+                "summary": "Create a new event",
+                "parameters": [
+                    {
+                        "description": "Event data",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internalhttp.CreateEventRequest"
+                        }
+                    }
+                ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/internalhttp.ListEventsResponse"
+                            "$ref": "#/definitions/internalhttp.CreateEventResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
@@ -276,36 +460,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/events/range": {
-            "get": {
-                "description": "Retrieve a list of events for a specific user within a given time range",
+        "/event/delete": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete an event by ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "events"
                 ],
-                "summary": "Get events for a user in time range",
+                "summary": "Delete an event",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "userId",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Start time (Unix timestamp)",
-                        "name": "from",
+                        "description": "Event ID",
+                        "name": "id",
                         "in": "query",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "End time (Unix timestamp)",
-                        "name": "to",
-                        "in": "query",
+                        "type": "string",
+                        "description": "ETag of the event version being deleted, or *",
+                        "name": "If-Match",
+                        "in": "header",
                         "required": true
                     }
                 ],
@@ -313,7 +495,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internalhttp.ListEventsResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -325,20 +510,741 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/event/get": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve an event from the database by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get event by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalhttp.EventResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/event/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the notifications scheduled for an event with the delivery statuses reported for them",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "List notifications of an event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "eventId",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalhttp.ListNotificationsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/event/update": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an event by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Update an existing event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of the event version being updated, or *",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated event data",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internalhttp.UpdateEventRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalhttp.UpdateEventResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "ETag of the new version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/events/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Export user events as a VCALENDAR document, optionally only series occurring in a time range",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Export user events as iCalendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Start time (Unix timestamp)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "End time (Unix timestamp)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "VCALENDAR document",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/events/freebusy": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Return merged busy intervals of each user in the window and the slots of the given duration\nin which all of them are free, soonest first, optionally within working hours",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Free/busy lookup and meeting slot finder",
+                "parameters": [
+                    {
+                        "description": "Users, window and meeting duration (seconds)",
+                        "name": "query",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internalhttp.FreeBusyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalhttp.FreeBusyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/events/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create events for a user from the VEVENTs of a VCALENDAR document and report the outcome per event",
+                "consumes": [
+                    "text/calendar"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Import events from iCalendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "VCALENDAR document",
+                        "name": "calendar",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalhttp.ImportEventsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/events/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a page of events ordered by start time, optionally filtered by title and time range",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get all events",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of events in the page",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token of the page returned by the previous call",
+                        "name": "pageToken",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Case-insensitive title substring",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Window start (Unix timestamp)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Window end (Unix timestamp)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalhttp.ListEventsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/events/range": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a list of events for a specific user within a given time range",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get events for a user in time range",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Start time (Unix timestamp)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "End time (Unix timestamp)",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalhttp.ListEventsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
                 }
             }
         },
         "/events/user": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "Retrieve a list of events for a specific user",
                 "produces": [
                     "application/json"
@@ -372,6 +1278,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -386,10 +1310,28 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "internalhttp.AttendeeResponse": {
+            "description": "Represents an attendee of an event.",
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "needs-action"
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "id5678"
+                }
+            }
+        },
         "internalhttp.CreateEventRequest": {
             "description": "Represents the request to create an event.",
             "type": "object",
             "properties": {
+                "allDay": {
+                    "type": "boolean",
+                    "example": false
+                },
                 "description": {
                     "type": "string",
                     "example": "Discuss project roadmap"
@@ -398,14 +1340,31 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 1717293600
                 },
+                "exDates": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1717894800
+                    ]
+                },
                 "notifyBefore": {
                     "type": "integer",
                     "example": 600
                 },
+                "rrule": {
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10"
+                },
                 "startTime": {
                     "type": "integer",
                     "example": 1717290000
                 },
+                "timeZone": {
+                    "type": "string",
+                    "example": "Europe/Moscow"
+                },
                 "title": {
                     "type": "string",
                     "example": "Team Meeting"
@@ -435,26 +1394,179 @@ const docTemplate = `{
             "description": "Represents an event returned by the API.",
             "type": "object",
             "properties": {
+                "allDay": {
+                    "type": "boolean"
+                },
                 "description": {
                     "type": "string"
                 },
                 "endTime": {
                     "type": "integer"
                 },
+                "exDates": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
                 "id": {
                     "type": "string"
                 },
                 "notifyBefore": {
                     "type": "integer"
                 },
+                "rrule": {
+                    "type": "string"
+                },
                 "startTime": {
                     "type": "integer"
                 },
+                "timeZone": {
+                    "type": "string"
+                },
                 "title": {
                     "type": "string"
                 },
                 "userId": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internalhttp.FreeBusyRequest": {
+            "description": "Represents a free/busy lookup for a group of users.",
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "type": "integer",
+                    "example": 3600
+                },
+                "from": {
+                    "type": "integer",
+                    "example": 1717290000
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "step": {
+                    "type": "integer",
+                    "example": 900
+                },
+                "to": {
+                    "type": "integer",
+                    "example": 1717894800
+                },
+                "userIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "id1234",
+                        "id5678"
+                    ]
+                },
+                "workingHours": {
+                    "$ref": "#/definitions/internalhttp.WorkingHoursRequest"
+                }
+            }
+        },
+        "internalhttp.FreeBusyResponse": {
+            "description": "Holds busy intervals per user and common free slots, soonest first.",
+            "type": "object",
+            "properties": {
+                "busy": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/internalhttp.IntervalResponse"
+                        }
+                    }
+                },
+                "slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internalhttp.IntervalResponse"
+                    }
+                }
+            }
+        },
+        "internalhttp.ImportEventResult": {
+            "description": "Represents the outcome of importing a single VEVENT.",
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "created"
+                },
+                "uid": {
+                    "type": "string"
+                }
+            }
+        },
+        "internalhttp.ImportEventsResponse": {
+            "description": "Represents the result of an iCalendar import.",
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internalhttp.ImportEventResult"
+                    }
+                }
+            }
+        },
+        "internalhttp.IntervalResponse": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "integer",
+                    "example": 1717293600
+                },
+                "start": {
+                    "type": "integer",
+                    "example": 1717290000
+                }
+            }
+        },
+        "internalhttp.InviteAttendeeRequest": {
+            "description": "Represents the request to invite a user to an event.",
+            "type": "object",
+            "properties": {
+                "eventId": {
+                    "type": "string",
+                    "example": "12345678-1234-1234-1234-12345678abcd"
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "id5678"
+                }
+            }
+        },
+        "internalhttp.ListAttendeesResponse": {
+            "type": "object",
+            "properties": {
+                "attendees": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internalhttp.AttendeeResponse"
+                    }
                 }
             }
         },
@@ -466,6 +1578,91 @@ const docTemplate = `{
                     "items": {
                         "$ref": "#/definitions/internalhttp.EventResponse"
                     }
+                },
+                "nextPageToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "internalhttp.ListNotificationsResponse": {
+            "type": "object",
+            "properties": {
+                "notifications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internalhttp.NotificationResponse"
+                    }
+                }
+            }
+        },
+        "internalhttp.NotificationResponse": {
+            "description": "Represents a notification about one occurrence of an event with its delivery history.",
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "12345678-1234-1234-1234-12345678abcd"
+                },
+                "notifyAt": {
+                    "type": "integer",
+                    "example": 1717289400
+                },
+                "occurrence": {
+                    "type": "integer",
+                    "example": 1717290000
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "published",
+                        "delivered"
+                    ],
+                    "example": "delivered"
+                },
+                "statuses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internalhttp.NotificationStatusResponse"
+                    }
+                }
+            }
+        },
+        "internalhttp.NotificationStatusResponse": {
+            "description": "Represents a delivery status reported for a notification.",
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "delivered"
+                },
+                "timestamp": {
+                    "type": "integer",
+                    "example": 1717290001
+                }
+            }
+        },
+        "internalhttp.RespondToInvitationRequest": {
+            "description": "Represents the RSVP of an invited user.",
+            "type": "object",
+            "properties": {
+                "eventId": {
+                    "type": "string",
+                    "example": "12345678-1234-1234-1234-12345678abcd"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "needs-action",
+                        "accepted",
+                        "declined",
+                        "tentative"
+                    ],
+                    "example": "accepted"
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "id5678"
                 }
             }
         },
@@ -473,6 +1670,10 @@ const docTemplate = `{
             "description": "Represents the request to update an existing event.",
             "type": "object",
             "properties": {
+                "allDay": {
+                    "type": "boolean",
+                    "example": false
+                },
                 "description": {
                     "type": "string",
                     "example": "Updated description"
@@ -481,6 +1682,15 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 1717293600
                 },
+                "exDates": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1717894800
+                    ]
+                },
                 "id": {
                     "type": "string",
                     "example": "12345678-1234-1234-1234-12345678abcd"
@@ -489,10 +1699,18 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 700
                 },
+                "rrule": {
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10"
+                },
                 "startTime": {
                     "type": "integer",
                     "example": 1717290000
                 },
+                "timeZone": {
+                    "type": "string",
+                    "example": "Europe/Moscow"
+                },
                 "title": {
                     "type": "string",
                     "example": "Team Meeting Updated"
@@ -502,6 +1720,59 @@ const docTemplate = `{
                     "example": "id1234"
                 }
             }
+        },
+        "internalhttp.UpdateEventResponse": {
+            "description": "Represents a successful event update response.",
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internalhttp.WorkingHoursRequest": {
+            "description": "Restricts meeting slots to a daily clock range; weekdays use ISO numbers, Monday is 1.",
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "string",
+                    "example": "18:00"
+                },
+                "start": {
+                    "type": "string",
+                    "example": "09:00"
+                },
+                "timeZone": {
+                    "type": "string",
+                    "example": "Europe/Moscow"
+                },
+                "weekdays": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3,
+                        4,
+                        5
+                    ]
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
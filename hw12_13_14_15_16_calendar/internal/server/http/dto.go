@@ -3,40 +3,54 @@ package internalhttp
 // CreateEventRequest represents the request to create an event.
 // @Description Represents the request to create an event.
 type CreateEventRequest struct {
-	UserID       string `json:"userId" example:"id1234"`
-	Title        string `json:"title" example:"Team Meeting"`
-	Description  string `json:"description" example:"Discuss project roadmap"`
-	StartTime    int64  `json:"startTime" example:"1717290000"`
-	EndTime      int64  `json:"endTime" example:"1717293600"`
-	NotifyBefore int64  `json:"notifyBefore" example:"600"`
+	UserID       string  `json:"userId" example:"id1234"`
+	Title        string  `json:"title" example:"Team Meeting"`
+	Description  string  `json:"description" example:"Discuss project roadmap"`
+	StartTime    int64   `json:"startTime" example:"1717290000"`
+	EndTime      int64   `json:"endTime" example:"1717293600"`
+	NotifyBefore int64   `json:"notifyBefore" example:"600"`
+	RRule        string  `json:"rrule,omitempty" example:"FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10"`
+	ExDates      []int64 `json:"exDates,omitempty" example:"1717894800"`
+	TimeZone     string  `json:"timeZone,omitempty" example:"Europe/Moscow"`
+	AllDay       bool    `json:"allDay,omitempty" example:"false"`
 }
 
 // UpdateEventRequest represents the request to update an existing event.
 // @Description Represents the request to update an existing event.
 type UpdateEventRequest struct {
-	ID           string `json:"id" example:"12345678-1234-1234-1234-12345678abcd"`
-	UserID       string `json:"userId" example:"id1234"`
-	Title        string `json:"title" example:"Team Meeting Updated"`
-	Description  string `json:"description" example:"Updated description"`
-	StartTime    int64  `json:"startTime" example:"1717290000"`
-	EndTime      int64  `json:"endTime" example:"1717293600"`
-	NotifyBefore int64  `json:"notifyBefore" example:"700"`
+	ID           string  `json:"id" example:"12345678-1234-1234-1234-12345678abcd"`
+	UserID       string  `json:"userId" example:"id1234"`
+	Title        string  `json:"title" example:"Team Meeting Updated"`
+	Description  string  `json:"description" example:"Updated description"`
+	StartTime    int64   `json:"startTime" example:"1717290000"`
+	EndTime      int64   `json:"endTime" example:"1717293600"`
+	NotifyBefore int64   `json:"notifyBefore" example:"700"`
+	RRule        string  `json:"rrule,omitempty" example:"FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10"`
+	ExDates      []int64 `json:"exDates,omitempty" example:"1717894800"`
+	TimeZone     string  `json:"timeZone,omitempty" example:"Europe/Moscow"`
+	AllDay       bool    `json:"allDay,omitempty" example:"false"`
 }
 
 // EventResponse represents an event returned by the API.
 // @Description Represents an event returned by the API.
 type EventResponse struct {
-	ID           string `json:"id"`
-	UserID       string `json:"userId"`
-	Title        string `json:"title"`
-	Description  string `json:"description"`
-	StartTime    int64  `json:"startTime"`
-	EndTime      int64  `json:"endTime"`
-	NotifyBefore int64  `json:"notifyBefore"`
+	ID           string  `json:"id"`
+	UserID       string  `json:"userId"`
+	Title        string  `json:"title"`
+	Description  string  `json:"description"`
+	StartTime    int64   `json:"startTime"`
+	EndTime      int64   `json:"endTime"`
+	NotifyBefore int64   `json:"notifyBefore"`
+	RRule        string  `json:"rrule,omitempty"`
+	ExDates      []int64 `json:"exDates,omitempty"`
+	TimeZone     string  `json:"timeZone,omitempty"`
+	AllDay       bool    `json:"allDay,omitempty"`
+	Version      int64   `json:"version"`
 }
 
 type ListEventsResponse struct {
-	Events []EventResponse `json:"events"`
+	Events        []EventResponse `json:"events"`
+	NextPageToken string          `json:"nextPageToken,omitempty"`
 }
 
 // CreateEventResponse represents a successful event creation response.
@@ -50,6 +64,104 @@ type CreateEventResponse struct {
 // UpdateEventResponse represents a successful event update response.
 // @Description Represents a successful event update response.
 type UpdateEventResponse struct {
-	Status string `json:"status"`
-	ID     string `json:"id"`
+	Status  string `json:"status"`
+	ID      string `json:"id"`
+	Version int64  `json:"version"`
+}
+
+// ImportEventResult represents the outcome of importing a single VEVENT.
+// @Description Represents the outcome of importing a single VEVENT.
+type ImportEventResult struct {
+	UID    string `json:"uid"`
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status" example:"created"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ImportEventsResponse represents the result of an iCalendar import.
+// @Description Represents the result of an iCalendar import.
+type ImportEventsResponse struct {
+	Created int                 `json:"created"`
+	Failed  int                 `json:"failed"`
+	Results []ImportEventResult `json:"results"`
+}
+
+// InviteAttendeeRequest represents the request to invite a user to an event.
+// @Description Represents the request to invite a user to an event.
+type InviteAttendeeRequest struct {
+	EventID string `json:"eventId" example:"12345678-1234-1234-1234-12345678abcd"`
+	UserID  string `json:"userId" example:"id5678"`
+}
+
+// RespondToInvitationRequest represents the RSVP of an invited user.
+// @Description Represents the RSVP of an invited user.
+type RespondToInvitationRequest struct {
+	EventID string `json:"eventId" example:"12345678-1234-1234-1234-12345678abcd"`
+	UserID  string `json:"userId" example:"id5678"`
+	Status  string `json:"status" example:"accepted" enums:"needs-action,accepted,declined,tentative"`
+}
+
+// AttendeeResponse represents an attendee of an event.
+// @Description Represents an attendee of an event.
+type AttendeeResponse struct {
+	UserID string `json:"userId" example:"id5678"`
+	Status string `json:"status" example:"needs-action"`
+}
+
+type ListAttendeesResponse struct {
+	Attendees []AttendeeResponse `json:"attendees"`
+}
+
+// NotificationStatusResponse represents a delivery status reported for a notification.
+// @Description Represents a delivery status reported for a notification.
+type NotificationStatusResponse struct {
+	Status    string `json:"status" example:"delivered"`
+	Timestamp int64  `json:"timestamp" example:"1717290001"`
+}
+
+// NotificationResponse represents a notification about one occurrence of an event.
+// @Description Represents a notification about one occurrence of an event with its delivery history.
+type NotificationResponse struct {
+	ID         string                       `json:"id" example:"12345678-1234-1234-1234-12345678abcd"`
+	Occurrence int64                        `json:"occurrence" example:"1717290000"`
+	NotifyAt   int64                        `json:"notifyAt" example:"1717289400"`
+	State      string                       `json:"state" example:"delivered" enums:"pending,published,delivered"`
+	Statuses   []NotificationStatusResponse `json:"statuses"`
+}
+
+type ListNotificationsResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+}
+
+// WorkingHoursRequest restricts meeting slots to a daily clock range.
+// @Description Restricts meeting slots to a daily clock range; weekdays use ISO numbers, Monday is 1.
+type WorkingHoursRequest struct {
+	Start    string `json:"start" example:"09:00"`
+	End      string `json:"end" example:"18:00"`
+	TimeZone string `json:"timeZone,omitempty" example:"Europe/Moscow"`
+	Weekdays []int  `json:"weekdays,omitempty" example:"1,2,3,4,5"`
+}
+
+// FreeBusyRequest represents a free/busy lookup for a group of users.
+// @Description Represents a free/busy lookup for a group of users.
+type FreeBusyRequest struct {
+	UserIDs      []string             `json:"userIds" example:"id1234,id5678"`
+	From         int64                `json:"from" example:"1717290000"`
+	To           int64                `json:"to" example:"1717894800"`
+	Duration     int64                `json:"duration" example:"3600"`
+	Step         int64                `json:"step,omitempty" example:"900"`
+	Limit        int                  `json:"limit,omitempty" example:"10"`
+	WorkingHours *WorkingHoursRequest `json:"workingHours,omitempty"`
+}
+
+type IntervalResponse struct {
+	Start int64 `json:"start" example:"1717290000"`
+	End   int64 `json:"end" example:"1717293600"`
+}
+
+// FreeBusyResponse holds busy intervals per user and common free slots, soonest first.
+// @Description Holds busy intervals per user and common free slots, soonest first.
+type FreeBusyResponse struct {
+	Busy  map[string][]IntervalResponse `json:"busy"`
+	Slots []IntervalResponse            `json:"slots"`
 }
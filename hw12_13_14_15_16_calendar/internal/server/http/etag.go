@@ -0,0 +1,50 @@
+package internalhttp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	errPreconditionRequired = fmt.Errorf("the If-Match header with the event ETag is required")
+	errInvalidIfMatch       = fmt.Errorf("the If-Match header must be a single strong ETag or *")
+)
+
+// ETag is the strong entity tag of an event version.
+func ETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// ifMatchVersion returns the event version required by the If-Match header;
+// "*" matches any version and yields zero.
+func ifMatchVersion(r *http.Request) (int64, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	switch {
+	case value == "":
+		return 0, errPreconditionRequired
+	case value == "*":
+		return 0, nil
+	}
+
+	unquoted, err := strconv.Unquote(value)
+	if err != nil {
+		return 0, errInvalidIfMatch
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, errInvalidIfMatch
+	}
+	return version, nil
+}
+
+// writePreconditionError reports a missing or malformed If-Match header.
+func writePreconditionError(w http.ResponseWriter, err error) {
+	code := http.StatusBadRequest
+	if errors.Is(err, errPreconditionRequired) {
+		code = http.StatusPreconditionRequired
+	}
+	http.Error(w, err.Error(), code)
+}
@@ -0,0 +1,49 @@
+package internalhttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// FreeBusy godoc
+// @Summary      Free/busy lookup and meeting slot finder
+// @Description  Return merged busy intervals of each user in the window and the slots of the given duration
+// @Description  in which all of them are free, soonest first, optionally within working hours
+// @Tags         events
+// @Accept       json
+// @Produce      json
+// @Param        query body FreeBusyRequest true "Users, window and meeting duration (seconds)"
+// @Success      200 {object} FreeBusyResponse
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /events/freebusy [post].
+func (h *CalendarHandlers) FreeBusy(w http.ResponseWriter, r *http.Request) {
+	var req FreeBusyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Invalid request body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	query, err := FromFreeBusyRequest(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.app.FreeBusy(r.Context(), query)
+	if err != nil {
+		h.logger.Errorf("Failed to look up free/busy: %v", err)
+		http.Error(w, fmt.Sprintf("Failed to look up free/busy: %v", err), errorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(ToFreeBusyResponse(result)); err != nil {
+		h.logger.Errorf("Failed to encode response: %v", err)
+	}
+}
@@ -1,13 +1,19 @@
 package internalhttp
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/auth"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/freebusy"
 	i "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/interface"
+	storagecommon "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/types"
 )
 
 type CalendarHandlers struct {
@@ -33,7 +39,11 @@ func (h *CalendarHandlers) helloHandler(w http.ResponseWriter, _ *http.Request)
 // @Param event body CreateEventRequest true "Event data"
 // @Success 201 {object} CreateEventResponse
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /event/create [post].
 func (h *CalendarHandlers) CreateEvent(w http.ResponseWriter, r *http.Request) {
 	var req CreateEventRequest
@@ -53,7 +63,7 @@ func (h *CalendarHandlers) CreateEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.StartTime >= req.EndTime {
+	if req.StartTime > req.EndTime || (req.StartTime == req.EndTime && !req.AllDay) {
 		http.Error(w, "Start time must be before end time", http.StatusBadRequest)
 		return
 	}
@@ -63,7 +73,7 @@ func (h *CalendarHandlers) CreateEvent(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id, err := h.app.CreateEvent(ctx, event)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to create event: %v", err), http.StatusInternalServerError)
+		http.Error(w, fmt.Sprintf("Failed to create event: %v", err), errorStatus(err, http.StatusInternalServerError))
 		return
 	}
 
@@ -86,11 +96,18 @@ func (h *CalendarHandlers) CreateEvent(w http.ResponseWriter, r *http.Request) {
 // @Tags         events
 // @Accept       json
 // @Produce      json
+// @Param        If-Match header string true "ETag of the event version being updated, or *"
 // @Param        event body UpdateEventRequest true "Updated event data"
-// @Success      200 {object} map[string]string
+// @Success      200 {object} UpdateEventResponse
+// @Header       200 {string} ETag "ETag of the new version"
 // @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
 // @Failure      404 {object} map[string]string
+// @Failure      412 {object} map[string]string
+// @Failure      428 {object} map[string]string
 // @Failure      500 {object} map[string]string
+// @Security     BearerAuth
 // @Router       /event/update [post].
 func (h *CalendarHandlers) UpdateEvent(w http.ResponseWriter, r *http.Request) {
 	var req UpdateEventRequest
@@ -105,18 +122,28 @@ func (h *CalendarHandlers) UpdateEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writePreconditionError(w, err)
+		return
+	}
+
 	event := FromUpdateEventRequest(req)
+	event.Version = version
 
 	ctx := r.Context()
-	if err := h.app.UpdateEvent(ctx, event); err != nil {
+	version, err = h.app.UpdateEvent(ctx, event)
+	if err != nil {
 		h.logger.Errorf("Failed to update event: %v", err)
-		http.Error(w, fmt.Sprintf("Failed to update event: %v", err), http.StatusInternalServerError)
+		http.Error(w, fmt.Sprintf("Failed to update event: %v", err), errorStatus(err, http.StatusInternalServerError))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", ETag(version))
 	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(map[string]string{"status": "updated", "id": event.ID}); err != nil {
+	response := UpdateEventResponse{Status: "updated", ID: event.ID, Version: version}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
 		h.logger.Errorf("Failed to encode response: %v", err)
 	}
 }
@@ -127,10 +154,16 @@ func (h *CalendarHandlers) UpdateEvent(w http.ResponseWriter, r *http.Request) {
 // @Tags         events
 // @Produce      json
 // @Param        id   query string true "Event ID"
+// @Param        If-Match header string true "ETag of the event version being deleted, or *"
 // @Success      200  {object} map[string]string
 // @Failure      400  {object} map[string]string
+// @Failure      401  {object} map[string]string
+// @Failure      403  {object} map[string]string
 // @Failure      404  {object} map[string]string
+// @Failure      412  {object} map[string]string
+// @Failure      428  {object} map[string]string
 // @Failure      500  {object} map[string]string
+// @Security     BearerAuth
 // @Router       /event/delete [delete].
 func (h *CalendarHandlers) DeleteEvent(w http.ResponseWriter, r *http.Request) {
 	id := r.URL.Query().Get("id")
@@ -139,10 +172,16 @@ func (h *CalendarHandlers) DeleteEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writePreconditionError(w, err)
+		return
+	}
+
 	ctx := r.Context()
-	if err := h.app.DeleteEvent(ctx, id); err != nil {
+	if err := h.app.DeleteEvent(ctx, id, version); err != nil {
 		h.logger.Errorf("Failed to delete event: %v", err)
-		http.Error(w, fmt.Sprintf("Failed to delete event: %v", err), http.StatusInternalServerError)
+		http.Error(w, fmt.Sprintf("Failed to delete event: %v", err), errorStatus(err, http.StatusInternalServerError))
 		return
 	}
 
@@ -163,8 +202,11 @@ func (h *CalendarHandlers) DeleteEvent(w http.ResponseWriter, r *http.Request) {
 // @Param        id   query string true "Event ID"
 // @Success      200  {object} EventResponse
 // @Failure      400  {object} map[string]string
+// @Failure      401  {object} map[string]string
+// @Failure      403  {object} map[string]string
 // @Failure      404  {object} map[string]string
 // @Failure      500  {object} map[string]string
+// @Security     BearerAuth
 // @Router       /event/get [get].
 func (h *CalendarHandlers) GetEventByID(w http.ResponseWriter, r *http.Request) {
 	id := r.URL.Query().Get("id")
@@ -177,13 +219,14 @@ func (h *CalendarHandlers) GetEventByID(w http.ResponseWriter, r *http.Request)
 	event, err := h.app.GetEventByID(ctx, id)
 	if err != nil {
 		h.logger.Errorf("Failed to get event by ID: %v", err)
-		http.Error(w, fmt.Sprintf("Event not found: %v", err), http.StatusNotFound)
+		http.Error(w, fmt.Sprintf("Event not found: %v", err), errorStatus(err, http.StatusNotFound))
 		return
 	}
 
 	resp := ToEventResponse(event)
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", ETag(event.Version))
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		h.logger.Errorf("Failed to encode response: %v", err)
@@ -192,23 +235,61 @@ func (h *CalendarHandlers) GetEventByID(w http.ResponseWriter, r *http.Request)
 
 // ListEvents godoc
 // @Summary      Get all events
-// @Description  Retrieve a list of all events
+// @Description  Retrieve a page of events ordered by start time, optionally filtered by title and time range
 // @Tags         events
 // @Produce      json
+// @Param        pageSize   query integer false "Maximum number of events in the page"
+// @Param        pageToken  query string  false "Token of the page returned by the previous call"
+// @Param        title      query string  false "Case-insensitive title substring"
+// @Param        from       query integer false "Window start (Unix timestamp)"
+// @Param        to         query integer false "Window end (Unix timestamp)"
 // @Success      200 {object} ListEventsResponse
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
 // @Failure      500 {object} map[string]string
+// @Security     BearerAuth
 // @Router       /events/list [get].
 func (h *CalendarHandlers) ListEvents(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	query := types.ListEventsQuery{
+		Title:     params.Get("title"),
+		PageToken: params.Get("pageToken"),
+	}
+
+	if raw := params.Get("pageSize"); raw != "" {
+		pageSize, err := strconv.Atoi(raw)
+		if err != nil || pageSize < 0 {
+			http.Error(w, "Invalid page size", http.StatusBadRequest)
+			return
+		}
+		query.PageSize = pageSize
+	}
+
+	var err error
+	if query.From, err = parseOptionalUnix(params.Get("from")); err != nil {
+		http.Error(w, "Invalid from timestamp", http.StatusBadRequest)
+		return
+	}
+	if query.To, err = parseOptionalUnix(params.Get("to")); err != nil {
+		http.Error(w, "Invalid to timestamp", http.StatusBadRequest)
+		return
+	}
+
 	ctx := r.Context()
-	events, err := h.app.ListEvents(ctx)
+	page, err := h.app.ListEvents(ctx, query)
+	if errors.Is(err, storagecommon.ErrInvalidPageToken) {
+		http.Error(w, "Invalid page token", http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		h.logger.Errorf("Failed to list events: %v", err)
-		http.Error(w, "Failed to fetch events", http.StatusInternalServerError)
+		http.Error(w, "Failed to fetch events", errorStatus(err, http.StatusInternalServerError))
 		return
 	}
 
-	response := ListEventsResponse{}
-	for _, e := range events {
+	response := ListEventsResponse{NextPageToken: page.NextPageToken}
+	for _, e := range page.Events {
 		response.Events = append(response.Events, ToEventResponse(e))
 	}
 
@@ -227,7 +308,10 @@ func (h *CalendarHandlers) ListEvents(w http.ResponseWriter, r *http.Request) {
 // @Param        userId   query string true "User ID"
 // @Success      200 {object} ListEventsResponse
 // @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
 // @Failure      500 {object} map[string]string
+// @Security     BearerAuth
 // @Router       /events/user [get].
 func (h *CalendarHandlers) ListEventsByUser(w http.ResponseWriter, r *http.Request) {
 	userID := r.URL.Query().Get("userId")
@@ -240,7 +324,7 @@ func (h *CalendarHandlers) ListEventsByUser(w http.ResponseWriter, r *http.Reque
 	events, err := h.app.ListEventsByUser(ctx, userID)
 	if err != nil {
 		h.logger.Errorf("Failed to list events for user: %v", err)
-		http.Error(w, "Failed to fetch events", http.StatusInternalServerError)
+		http.Error(w, "Failed to fetch events", errorStatus(err, http.StatusInternalServerError))
 		return
 	}
 
@@ -266,7 +350,10 @@ func (h *CalendarHandlers) ListEventsByUser(w http.ResponseWriter, r *http.Reque
 // @Param        to       query integer true "End time (Unix timestamp)"
 // @Success      200 {object} ListEventsResponse
 // @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
 // @Failure      500 {object} map[string]string
+// @Security     BearerAuth
 // @Router       /events/range [get].
 func (h *CalendarHandlers) ListEventsByUserInRange(w http.ResponseWriter, r *http.Request) {
 	userID := r.URL.Query().Get("userId")
@@ -296,7 +383,7 @@ func (h *CalendarHandlers) ListEventsByUserInRange(w http.ResponseWriter, r *htt
 	events, err := h.app.ListEventsByUserInRange(ctx, userID, from, to)
 	if err != nil {
 		h.logger.Errorf("Failed to list events in range: %v", err)
-		http.Error(w, "Failed to fetch events", http.StatusInternalServerError)
+		http.Error(w, "Failed to fetch events", errorStatus(err, http.StatusInternalServerError))
 		return
 	}
 
@@ -311,3 +398,38 @@ func (h *CalendarHandlers) ListEventsByUserInRange(w http.ResponseWriter, r *htt
 		h.logger.Errorf("Failed to encode response: %v", err)
 	}
 }
+
+// errorStatus maps application errors that are not specific to a handler to HTTP status codes.
+func errorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, auth.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, auth.ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, storagecommon.ErrEventNotFound), errors.Is(err, storagecommon.ErrAttendeeNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, storagecommon.ErrAttendeeExists), errors.Is(err, storagecommon.ErrConflictOverlap),
+		errors.Is(err, storagecommon.ErrAlreadyExists):
+		return http.StatusConflict
+	case errors.Is(err, storagecommon.ErrVersionConflict):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, storagecommon.ErrInvalidEvent):
+		return http.StatusBadRequest
+	case errors.Is(err, storagecommon.ErrInvalidAttendee), errors.Is(err, freebusy.ErrInvalidQuery):
+		return http.StatusBadRequest
+	}
+	return fallback
+}
+
+func parseOptionalUnix(raw string) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	sec, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(sec, 0), nil
+}
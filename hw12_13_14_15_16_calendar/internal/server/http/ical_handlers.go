@@ -0,0 +1,137 @@
+package internalhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/ical"
+)
+
+const maxImportSize = 10 << 20
+
+// ExportEvents godoc
+// @Summary      Export user events as iCalendar
+// @Description  Export user events as a VCALENDAR document, optionally only series occurring in a time range
+// @Tags         events
+// @Produce      text/calendar
+// @Param        userId   query string  true  "User ID"
+// @Param        from     query integer false "Start time (Unix timestamp)"
+// @Param        to       query integer false "End time (Unix timestamp)"
+// @Success      200 {string} string "VCALENDAR document"
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /events/export [get].
+func (h *CalendarHandlers) ExportEvents(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("userId")
+	fromStr := r.URL.Query().Get("from")
+	toStr := r.URL.Query().Get("to")
+
+	if userID == "" {
+		http.Error(w, "UserID is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	events, err := h.app.ListEventsByUser(ctx, userID)
+	if err != nil {
+		h.logger.Errorf("Failed to list events for export: %v", err)
+		http.Error(w, "Failed to fetch events", errorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	if fromStr != "" || toStr != "" {
+		fromUnix, err := strconv.ParseInt(fromStr, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid from timestamp", http.StatusBadRequest)
+			return
+		}
+		toUnix, err := strconv.ParseInt(toStr, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid to timestamp", http.StatusBadRequest)
+			return
+		}
+
+		occurrences, err := h.app.ListEventsByUserInRange(ctx, userID, time.Unix(fromUnix, 0), time.Unix(toUnix, 0))
+		if err != nil {
+			h.logger.Errorf("Failed to list events in range for export: %v", err)
+			http.Error(w, "Failed to fetch events", errorStatus(err, http.StatusInternalServerError))
+			return
+		}
+		events = ical.SelectSeries(events, occurrences)
+	}
+
+	w.Header().Set("Content-Type", ical.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", userID+".ics"))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(ical.Encode(events)); err != nil {
+		h.logger.Errorf("Failed to write calendar: %v", err)
+	}
+}
+
+// ImportEvents godoc
+// @Summary      Import events from iCalendar
+// @Description  Create events for a user from the VEVENTs of a VCALENDAR document and report the outcome per event
+// @Tags         events
+// @Accept       text/calendar
+// @Produce      json
+// @Param        userId   query string true "User ID"
+// @Param        calendar body  string true "VCALENDAR document"
+// @Success      200 {object} ImportEventsResponse
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /events/import [post].
+func (h *CalendarHandlers) ImportEvents(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("userId")
+	if userID == "" {
+		http.Error(w, "UserID is required", http.StatusBadRequest)
+		return
+	}
+
+	items, err := ical.Decode(http.MaxBytesReader(w, r.Body, maxImportSize))
+	if err != nil {
+		h.logger.Errorf("Invalid calendar: %v", err)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "Calendar is too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Invalid calendar: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	response := ImportEventsResponse{Results: make([]ImportEventResult, 0, len(items))}
+	for _, item := range items {
+		result := ImportEventResult{UID: item.UID}
+
+		err := item.Err
+		if err == nil {
+			item.Event.UserID = userID
+			result.ID, err = h.app.CreateEvent(ctx, item.Event)
+		}
+
+		result.Status = ical.ImportStatus(err)
+		if err != nil {
+			result.Error = err.Error()
+			response.Failed++
+		} else {
+			response.Created++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Errorf("Failed to encode response: %v", err)
+	}
+}
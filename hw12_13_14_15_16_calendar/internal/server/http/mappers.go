@@ -3,6 +3,8 @@ package internalhttp
 import (
 	"time"
 
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/freebusy"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/mappers"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/types"
 )
 
@@ -14,6 +16,10 @@ func FromCreateEventRequest(req CreateEventRequest) types.Event {
 		StartTime:    time.Unix(req.StartTime, 0),
 		EndTime:      time.Unix(req.EndTime, 0),
 		NotifyBefore: int(req.NotifyBefore),
+		RRule:        req.RRule,
+		ExDates:      mappers.UnixToTimes(req.ExDates),
+		TimeZone:     req.TimeZone,
+		AllDay:       req.AllDay,
 	}
 }
 
@@ -26,6 +32,10 @@ func FromUpdateEventRequest(req UpdateEventRequest) types.Event {
 		StartTime:    time.Unix(req.StartTime, 0),
 		EndTime:      time.Unix(req.EndTime, 0),
 		NotifyBefore: int(req.NotifyBefore),
+		RRule:        req.RRule,
+		ExDates:      mappers.UnixToTimes(req.ExDates),
+		TimeZone:     req.TimeZone,
+		AllDay:       req.AllDay,
 	}
 }
 
@@ -38,6 +48,11 @@ func ToEventResponse(event types.Event) EventResponse {
 		StartTime:    event.StartTime.Unix(),
 		EndTime:      event.EndTime.Unix(),
 		NotifyBefore: int64(event.NotifyBefore),
+		RRule:        event.RRule,
+		ExDates:      mappers.TimesToUnix(event.ExDates),
+		TimeZone:     event.TimeZone,
+		AllDay:       event.AllDay,
+		Version:      event.Version,
 	}
 }
 
@@ -49,5 +64,73 @@ func ToCreateEventRequest(event types.Event) CreateEventRequest {
 		StartTime:    event.StartTime.Unix(),
 		EndTime:      event.EndTime.Unix(),
 		NotifyBefore: int64(event.NotifyBefore),
+		RRule:        event.RRule,
+		ExDates:      mappers.TimesToUnix(event.ExDates),
+		TimeZone:     event.TimeZone,
+		AllDay:       event.AllDay,
 	}
 }
+
+func ToListAttendeesResponse(attendees []types.Attendee) ListAttendeesResponse {
+	resp := ListAttendeesResponse{Attendees: make([]AttendeeResponse, 0, len(attendees))}
+	for _, a := range attendees {
+		resp.Attendees = append(resp.Attendees, AttendeeResponse{UserID: a.UserID, Status: a.Status})
+	}
+	return resp
+}
+
+func ToListNotificationsResponse(notifications []types.Notification) ListNotificationsResponse {
+	resp := ListNotificationsResponse{Notifications: make([]NotificationResponse, 0, len(notifications))}
+	for _, n := range notifications {
+		statuses := make([]NotificationStatusResponse, 0, len(n.Statuses))
+		for _, s := range n.Statuses {
+			statuses = append(statuses, NotificationStatusResponse{Status: s.Status, Timestamp: s.ReportedAt.Unix()})
+		}
+		resp.Notifications = append(resp.Notifications, NotificationResponse{
+			ID:         n.ID,
+			Occurrence: n.Occurrence.Unix(),
+			NotifyAt:   n.NotifyAt.Unix(),
+			State:      n.State,
+			Statuses:   statuses,
+		})
+	}
+	return resp
+}
+
+func FromFreeBusyRequest(req FreeBusyRequest) (types.FreeBusyQuery, error) {
+	query := types.FreeBusyQuery{
+		UserIDs:  req.UserIDs,
+		From:     time.Unix(req.From, 0),
+		To:       time.Unix(req.To, 0),
+		Duration: time.Duration(req.Duration) * time.Second,
+		Step:     time.Duration(req.Step) * time.Second,
+		Limit:    req.Limit,
+	}
+	if wh := req.WorkingHours; wh != nil {
+		hours, err := freebusy.ParseWorkingHours(wh.Start, wh.End, wh.TimeZone, wh.Weekdays)
+		if err != nil {
+			return types.FreeBusyQuery{}, err
+		}
+		query.WorkingHours = hours
+	}
+	return query, nil
+}
+
+func ToFreeBusyResponse(fb types.FreeBusy) FreeBusyResponse {
+	resp := FreeBusyResponse{
+		Busy:  make(map[string][]IntervalResponse, len(fb.Busy)),
+		Slots: toIntervalResponses(fb.Slots),
+	}
+	for userID, intervals := range fb.Busy {
+		resp.Busy[userID] = toIntervalResponses(intervals)
+	}
+	return resp
+}
+
+func toIntervalResponses(intervals []types.Interval) []IntervalResponse {
+	result := make([]IntervalResponse, 0, len(intervals))
+	for _, in := range intervals {
+		result = append(result, IntervalResponse{Start: in.Start.Unix(), End: in.End.Unix()})
+	}
+	return result
+}
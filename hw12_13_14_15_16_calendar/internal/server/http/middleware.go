@@ -1,13 +1,23 @@
 package internalhttp
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/auth"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/health"
 	i "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/interface"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/tracing"
+	"go.opentelemetry.io/otel/codes"                   //nolint:depguard
+	"go.opentelemetry.io/otel/propagation"             //nolint:depguard
+	semconv "go.opentelemetry.io/otel/semconv/v1.30.0" //nolint:depguard
+	"go.opentelemetry.io/otel/trace"                   //nolint:depguard
 )
 
 type responseWriter struct {
@@ -20,7 +30,9 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
-func loggingMiddleware(logger i.Logger) func(next http.Handler) http.Handler {
+// loggingMiddleware logs every request and counts it in the metrics under the pattern of the route
+// that serves it, so that paths with IDs do not multiply the series.
+func loggingMiddleware(logger i.Logger, routes *http.ServeMux) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
@@ -31,6 +43,10 @@ func loggingMiddleware(logger i.Logger) func(next http.Handler) http.Handler {
 			clientIP := getClientIP(r)
 			latency := time.Since(start)
 
+			_, route := routes.Handler(r)
+			metrics.HTTPRequests.WithLabelValues(route, r.Method, strconv.Itoa(rw.statusCode)).Inc()
+			metrics.HTTPDuration.WithLabelValues(route, r.Method).Observe(latency.Seconds())
+
 			logEntry := fmt.Sprintf(
 				"%s [%s] %s %s %s %d %d \"%s\"",
 				clientIP,
@@ -48,6 +64,92 @@ func loggingMiddleware(logger i.Logger) func(next http.Handler) http.Handler {
 	}
 }
 
+// tracingMiddleware serves every request in a span that continues the trace of the caller. The span is
+// named after the route, like the metrics; scrapes of the metrics and the probes are not traced.
+func tracingMiddleware(routes *http.ServeMux) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/metrics" || health.IsProbe(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			_, route := routes.Handler(r)
+			ctx := tracing.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			ctx, span := tracing.Start(ctx, r.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(r.Method),
+					semconv.HTTPRoute(route),
+					semconv.URLPath(r.URL.Path),
+				),
+			)
+			defer span.End()
+
+			rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(rw, r.WithContext(ctx))
+
+			span.SetAttributes(semconv.HTTPResponseStatusCode(rw.statusCode))
+			if rw.statusCode >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(rw.statusCode))
+			}
+		})
+	}
+}
+
+// timeoutMiddleware puts a deadline into the request context, so that slow storage calls are
+// canceled instead of outliving the connection's write deadline.
+func timeoutMiddleware(timeout time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if timeout <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// authMiddleware authenticates requests by their bearer token and puts the caller identity
+// into the request context. Public paths are served without authentication.
+func authMiddleware(authenticator i.Authenticator, logger i.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isPublicPath(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token, err := auth.BearerToken(r.Header.Get("Authorization"))
+			var identity auth.Identity
+			if err == nil {
+				identity, err = authenticator.Authenticate(token)
+			}
+			if err != nil {
+				logger.Warnf("Authentication failed for %s: %v", r.URL.Path, err)
+				w.Header().Set("WWW-Authenticate", `Bearer realm="calendar"`)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), identity)))
+		})
+	}
+}
+
+// trustedMiddleware serves the requests when authentication is disabled, with every caller trusted.
+func trustedMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(auth.WithSystem(r.Context())))
+	})
+}
+
+func isPublicPath(path string) bool {
+	return path == "/" || path == "/metrics" || health.IsProbe(path) || strings.HasPrefix(path, "/swagger/")
+}
+
 func getClientIP(r *http.Request) string {
 	ip := r.Header.Get("X-Forwarded-For")
 	if ip != "" {
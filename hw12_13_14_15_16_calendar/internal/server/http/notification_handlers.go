@@ -0,0 +1,42 @@
+package internalhttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ListNotifications godoc
+// @Summary      List notifications of an event
+// @Description  Retrieve the notifications scheduled for an event with the delivery statuses reported for them
+// @Tags         notifications
+// @Produce      json
+// @Param        eventId query string true "Event ID"
+// @Success      200 {object} ListNotificationsResponse
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /event/notifications [get].
+func (h *CalendarHandlers) ListNotifications(w http.ResponseWriter, r *http.Request) {
+	eventID := r.URL.Query().Get("eventId")
+	if eventID == "" {
+		http.Error(w, "EventID is required", http.StatusBadRequest)
+		return
+	}
+
+	notifications, err := h.app.ListNotifications(r.Context(), eventID)
+	if err != nil {
+		h.logger.Errorf("Failed to list notifications: %v", err)
+		http.Error(w, fmt.Sprintf("Failed to list notifications: %v", err), errorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(ToListNotificationsResponse(notifications)); err != nil {
+		h.logger.Errorf("Failed to encode response: %v", err)
+	}
+}
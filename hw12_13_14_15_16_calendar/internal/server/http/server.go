@@ -8,7 +8,9 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/health"
 	i "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/interface"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/metrics"
 	// Импортируем сгенерированный пакет docs для регистрации Swagger.
 	_ "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/server/http/docs"
 	httpSwagger "github.com/swaggo/http-swagger" //nolint: depguard
@@ -28,9 +30,19 @@ type ServerConfig struct {
 	WriteTimeout      time.Duration
 	IdleTimeout       time.Duration
 	ReadHeaderTimeout time.Duration
+	HandlerTimeout    time.Duration
 }
 
-func NewServer(app i.Application, logger i.Logger, cfg ServerConfig, handlers *CalendarHandlers) *Server {
+// NewServer creates the HTTP server; a nil authenticator disables authentication, and every caller is
+// trusted then. The probes report the checks of the checker; without one the server is always ready.
+func NewServer(
+	app i.Application,
+	logger i.Logger,
+	cfg ServerConfig,
+	handlers *CalendarHandlers,
+	authenticator i.Authenticator,
+	checker *health.Checker,
+) *Server {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("/event/create", handlers.CreateEvent)
@@ -40,18 +52,41 @@ func NewServer(app i.Application, logger i.Logger, cfg ServerConfig, handlers *C
 	mux.HandleFunc("/events/list", handlers.ListEvents)
 	mux.HandleFunc("/events/user", handlers.ListEventsByUser)
 	mux.HandleFunc("/events/range", handlers.ListEventsByUserInRange)
+	mux.HandleFunc("/events/export", handlers.ExportEvents)
+	mux.HandleFunc("/events/import", handlers.ImportEvents)
+	mux.HandleFunc("/events/freebusy", handlers.FreeBusy)
+	mux.HandleFunc("/event/attendees", handlers.ListAttendees)
+	mux.HandleFunc("/event/attendees/invite", handlers.InviteAttendee)
+	mux.HandleFunc("/event/attendees/respond", handlers.RespondToInvitation)
+	mux.HandleFunc("/event/attendees/remove", handlers.RemoveAttendee)
+	mux.HandleFunc("/event/notifications", handlers.ListNotifications)
 
 	mux.HandleFunc("/", handlers.helloHandler)
+	mux.Handle("/metrics", metrics.Handler())
+	checker.Register(mux)
 
 	mux.HandleFunc("/swagger/", func(w http.ResponseWriter, r *http.Request) {
 		httpSwagger.Handler()(w, r)
 	})
 
+	var handler http.Handler = mux
+	if authenticator != nil {
+		handler = authMiddleware(authenticator, handlers.logger)(handler)
+	} else {
+		handler = trustedMiddleware(handler)
+	}
+	handlerTimeout := cfg.HandlerTimeout
+	if handlerTimeout == 0 {
+		handlerTimeout = cfg.WriteTimeout
+	}
+	handler = timeoutMiddleware(handlerTimeout)(handler)
+	handler = tracingMiddleware(mux)(handler)
+
 	return &Server{
 		logger: logger,
 		app:    app,
 		server: &http.Server{
-			Handler:           loggingMiddleware(handlers.logger)(mux),
+			Handler:           loggingMiddleware(handlers.logger, mux)(handler),
 			ReadTimeout:       cfg.ReadTimeout,
 			WriteTimeout:      cfg.WriteTimeout,
 			IdleTimeout:       cfg.IdleTimeout,
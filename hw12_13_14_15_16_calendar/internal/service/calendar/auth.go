@@ -0,0 +1,29 @@
+package calendar
+
+import (
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/auth"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/config"
+	i "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/interface"
+)
+
+// newAuthenticator returns nil when authentication is disabled.
+func newAuthenticator(cfg config.Auth) (i.Authenticator, error) {
+	if !cfg.Enable {
+		return nil, nil
+	}
+
+	keys := make([]auth.Key, 0, len(cfg.Keys)+1)
+	if cfg.Secret != "" {
+		keys = append(keys, auth.Key{Secret: cfg.Secret})
+	}
+	for _, key := range cfg.Keys {
+		keys = append(keys, auth.Key{ID: key.ID, Secret: key.Secret})
+	}
+
+	return auth.NewJWTAuthenticator(auth.JWTConfig{
+		Keys:     keys,
+		Issuer:   cfg.Issuer,
+		Audience: cfg.Audience,
+		Leeway:   cfg.Leeway,
+	})
+}
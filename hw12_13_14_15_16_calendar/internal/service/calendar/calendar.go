@@ -5,26 +5,60 @@ import (
 	"fmt"
 
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/config"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/health"
 	i "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/interface"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/server/grpc"
 	internalhttp "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/server/http"
 )
 
 type Calendar struct {
-	app  i.Application
-	logg i.Logger
-	cfg  *config.CalendarConfig
+	app     i.Application
+	logg    i.Logger
+	cfg     *config.CalendarConfig
+	checker *health.Checker
 }
 
-func NewCalendar(app i.Application, logger i.Logger, cfg *config.CalendarConfig) *Calendar {
+// NewCalendar creates the service; its readiness is the one of the checks of the checker and of the gRPC
+// server when it is enabled.
+func NewCalendar(
+	app i.Application,
+	logger i.Logger,
+	cfg *config.CalendarConfig,
+	checker *health.Checker,
+) *Calendar {
+	if checker == nil {
+		checker = health.NewChecker(health.DefaultTimeout)
+	}
 	return &Calendar{
-		app:  app,
-		logg: logger,
-		cfg:  cfg,
+		app:     app,
+		logg:    logger,
+		cfg:     cfg,
+		checker: checker,
 	}
 }
 
 func (s *Calendar) Run(ctx context.Context) error {
+	authenticator, err := newAuthenticator(s.cfg.Auth)
+	if err != nil {
+		return fmt.Errorf("failed to configure authentication: %w", err)
+	}
+	if authenticator == nil {
+		s.logg.Warnf("Authentication is disabled")
+	}
+
+	var grpcServer *grpc.Server
+	if s.cfg.GRPC.Enable {
+		grpcServer = grpc.NewServer(
+			s.app,
+			grpc.ServerConfig{
+				Port: s.cfg.GRPC.Port,
+			},
+			s.logg,
+			authenticator,
+		)
+		s.checker.Add("grpc", grpcServer.Ready)
+	}
+
 	handlers := internalhttp.NewCalendarHandlers(s.app, s.logg)
 
 	server := internalhttp.NewServer(s.app, s.logg, internalhttp.ServerConfig{
@@ -34,18 +68,12 @@ func (s *Calendar) Run(ctx context.Context) error {
 		WriteTimeout:      s.cfg.HTTP.WriteTimeout,
 		IdleTimeout:       s.cfg.HTTP.IdleTimeout,
 		ReadHeaderTimeout: s.cfg.HTTP.ReadHeaderTimeout,
-	}, handlers)
+		HandlerTimeout:    s.cfg.HTTP.HandlerTimeout,
+	}, handlers, authenticator, s.checker)
 
-	if s.cfg.GRPC.Enable {
+	if grpcServer != nil {
 		go func() {
 			s.logg.Debugf("gRPC server starting..")
-			grpcServer := grpc.NewServer(
-				s.app,
-				grpc.ServerConfig{
-					Port: s.cfg.GRPC.Port,
-				},
-				s.logg,
-			)
 			if err := grpcServer.Run(); err != nil {
 				s.logg.Fatalf("Failed to start gRPC server: %s", err.Error())
 			}
@@ -55,6 +83,10 @@ func (s *Calendar) Run(ctx context.Context) error {
 
 	go func() {
 		<-ctx.Done()
+		if grpcServer != nil {
+			s.logg.Infof("Stopping gRPC server...")
+			grpcServer.Stop()
+		}
 		s.logg.Infof("Stopping HTTP server...")
 		if err := server.Stop(context.Background()); err != nil {
 			s.logg.Errorf("Failed to stop http server: %s", err.Error())
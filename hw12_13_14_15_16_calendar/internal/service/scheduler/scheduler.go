@@ -3,32 +3,60 @@ package scheduler
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/auth"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/config"
 	i "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/interface"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/metrics"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/rmq"
+	storagecommon "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/tracing"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/types"
 )
 
+// publishBatchSize limits the number of notifications published per tick.
+const publishBatchSize = 100
+
 type Scheduler struct {
 	app    i.Application
 	rmq    i.RmqClient
 	logger i.Logger
 	cfg    *config.SchedulerConfig
+	leader i.Leader
 }
 
-func NewScheduler(app i.Application, rmq i.RmqClient, logger i.Logger, cfg *config.SchedulerConfig) *Scheduler {
+func NewScheduler(
+	app i.Application,
+	rmq i.RmqClient,
+	logger i.Logger,
+	cfg *config.SchedulerConfig,
+	leader i.Leader,
+) *Scheduler {
 	return &Scheduler{
 		app:    app,
 		rmq:    rmq,
 		logger: logger,
 		cfg:    cfg,
+		leader: leader,
 	}
 }
 
+// Run adds due notifications to the outbox and publishes the pending ones on every tick, and records
+// the delivery statuses the sender reports. Ticks are skipped unless the replica is the leader, while
+// every replica records statuses.
 func (s *Scheduler) Run(ctx context.Context) error {
+	ctx = auth.WithSystem(ctx)
 	s.logger.Infof("Scheduler started with interval: %v", s.cfg.Interval)
 
+	statuses, err := s.rmq.Consume(rmq.StatusQueue)
+	if err != nil {
+		s.logger.Errorf("Failed to consume from status queue: %v", err)
+		return err
+	}
+
 	ticker := time.NewTicker(s.cfg.Interval)
 	defer ticker.Stop()
 
@@ -36,38 +64,163 @@ func (s *Scheduler) Run(ctx context.Context) error {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
+		case delivery, ok := <-statuses:
+			if !ok {
+				return fmt.Errorf("status queue %s closed", rmq.StatusQueue)
+			}
+			s.handleStatus(ctx, delivery)
 		case <-ticker.C:
-			now := time.Now()
-			events, err := s.app.ListEventsDueBefore(ctx, now.Add(s.cfg.Interval))
-			if err != nil {
-				s.logger.Errorf("Error fetching events: %v", err)
-				continue
+			if s.leader.IsLeader() {
+				s.tick(ctx)
 			}
+		}
+	}
+}
 
-			for _, event := range events {
-				dto := rmq.Notification{
-					ID:          event.ID,
-					Title:       event.Title,
-					Description: event.Description,
-					UserID:      event.UserID,
-					Time:        event.StartTime.Format(time.RFC3339),
-					NotifyAt:    now.Format(time.RFC3339),
-				}
-
-				body, err := json.Marshal(dto)
-				if err != nil {
-					s.logger.Errorf("Error marshalling notification: %v", err)
-				}
-				if err := s.rmq.Publish(event.UserID, body); err != nil {
-					s.logger.Errorf("Failed to publish notification for event %s: %v", event.ID, err)
-					continue
-				}
-				s.logger.Infof("Published notification for event %s", event.ID)
-			}
+// tick adds the due notifications to the outbox, publishes the pending ones and deletes old events.
+func (s *Scheduler) tick(ctx context.Context) {
+	now := time.Now()
+	ctx, span := tracing.Start(ctx, "Scheduler.tick")
+	defer func() {
+		span.End()
+		metrics.SchedulerTickDuration.Observe(time.Since(now).Seconds())
+	}()
 
-			if err := s.app.DeleteOlderThan(ctx, time.Now().Add(-s.cfg.Scheduler.RetentionPeriod)); err != nil {
-				s.logger.Warnf("Failed to delete old events: %v", err)
-			}
+	added, err := s.app.ScheduleNotifications(ctx, now.Add(-s.catchUp()), now.Add(s.cfg.Interval))
+	if err != nil {
+		s.logger.Errorf("Error scheduling notifications: %v", err)
+	} else if added > 0 {
+		s.logger.Infof("Scheduled %d notifications", added)
+	}
+
+	if err := s.publishPending(ctx); err != nil {
+		s.logger.Warnf("Publishing paused until the next tick: %v", err)
+	}
+
+	if err := s.app.DeleteOlderThan(ctx, time.Now().Add(-s.cfg.Scheduler.RetentionPeriod)); err != nil {
+		s.logger.Warnf("Failed to delete old events: %v", err)
+	}
+}
+
+// publishPending publishes the pending notifications. A notification stays pending until the broker confirms
+// it and it is marked published, so one that was unroutable, rejected or not confirmed in time is retried on
+// the next tick. Published notifications whose delivery is not confirmed within ResendAfter are published
+// again. Either way a notification may reach the queue twice, see rmq.Notification. While the broker is
+// unreachable the rest of the batch is left for the next tick and the error is returned.
+func (s *Scheduler) publishPending(ctx context.Context) error {
+	var resendBefore time.Time
+	if s.cfg.ResendAfter > 0 {
+		resendBefore = time.Now().Add(-s.cfg.ResendAfter)
+	}
+
+	notifications, err := s.app.PendingNotifications(ctx, resendBefore, publishBatchSize)
+	if err != nil {
+		s.logger.Errorf("Error fetching pending notifications: %v", err)
+		return nil
+	}
+
+	for _, notification := range notifications {
+		body, err := json.Marshal(toMessage(notification))
+		if err != nil {
+			s.logger.Errorf("Error marshalling notification %s: %v", notification.ID, err)
+			continue
 		}
+		err = s.rmq.Publish(ctx, rmq.NotificationRoutingKey(notification.UserID), body)
+		if err != nil {
+			metrics.NotificationPublishFailures.WithLabelValues(publishFailure(err)).Inc()
+		}
+		switch {
+		case errors.Is(err, rmq.ErrNotConnected), errors.Is(err, rmq.ErrClosed):
+			return fmt.Errorf("failed to publish notification %s: %w", notification.ID, err)
+		case errors.Is(err, rmq.ErrUnroutable):
+			s.logger.Warnf("No queue accepts notifications of user %s, notification %s kept pending: %v",
+				notification.UserID, notification.ID, err)
+			continue
+		case err != nil:
+			s.logger.Errorf("Failed to publish notification %s: %v", notification.ID, err)
+			continue
+		}
+		metrics.NotificationsPublished.Inc()
+		if err := s.app.MarkNotificationPublished(ctx, notification.ID); err != nil {
+			s.logger.Errorf("Failed to mark notification %s published: %v", notification.ID, err)
+			continue
+		}
+		s.logger.Infof("Published notification %s for event %s", notification.ID, notification.EventID)
+	}
+	return nil
+}
+
+// handleStatus records a status and settles its delivery: statuses that failed to be recorded are
+// retried, malformed ones are dead-lettered and the ones for unknown notifications are dropped.
+func (s *Scheduler) handleStatus(ctx context.Context, delivery rmq.Delivery) {
+	ctx = delivery.WithTrace(ctx)
+	var status rmq.NotificationStatus
+	if err := json.Unmarshal(delivery.Body, &status); err != nil {
+		s.logger.Errorf("Failed to unmarshal notification status: %v", err)
+		s.settle(delivery.DeadLetter(err.Error()))
+		return
+	}
+
+	err := s.app.RecordNotificationStatus(ctx, types.NotificationStatus{
+		NotificationID: status.NotificationID,
+		Status:         status.Status,
+		ReportedAt:     status.Timestamp,
+	})
+	switch {
+	case errors.Is(err, storagecommon.ErrNotificationNotFound):
+		s.logger.Warnf("Received status for unknown notification %s", status.NotificationID)
+		s.settle(delivery.Ack())
+	case errors.Is(err, storagecommon.ErrInvalidNotification):
+		s.logger.Warnf("Received invalid status for notification %s", status.NotificationID)
+		s.settle(delivery.DeadLetter(err.Error()))
+	case err != nil:
+		s.logger.Errorf("Failed to record status of notification %s: %v", status.NotificationID, err)
+		s.settle(delivery.Retry(err.Error()))
+	default:
+		s.logger.Infof("Notification %s %s", status.NotificationID, status.Status)
+		s.settle(delivery.Ack())
+	}
+}
+
+// publishFailure names the reason of a failed publish for the metrics.
+func publishFailure(err error) string {
+	switch {
+	case errors.Is(err, rmq.ErrUnroutable):
+		return "unroutable"
+	case errors.Is(err, rmq.ErrNacked):
+		return "rejected"
+	case errors.Is(err, rmq.ErrConfirmTimeout):
+		return "unconfirmed"
+	case errors.Is(err, rmq.ErrNotConnected), errors.Is(err, rmq.ErrClosed):
+		return "disconnected"
+	default:
+		return "error"
+	}
+}
+
+func (s *Scheduler) catchUp() time.Duration {
+	if s.cfg.CatchUp > 0 {
+		return s.cfg.CatchUp
+	}
+	return s.cfg.Interval
+}
+
+func (s *Scheduler) settle(err error) {
+	if err != nil {
+		s.logger.Errorf("Failed to settle status message: %v", err)
+	}
+}
+
+func toMessage(n types.Notification) rmq.Notification {
+	return rmq.Notification{
+		ID:          n.ID,
+		EventID:     n.EventID,
+		Title:       n.Title,
+		Description: n.Description,
+		UserID:      n.UserID,
+		Time:        n.Occurrence.Format(time.RFC3339),
+		NotifyAt:    n.NotifyAt.Format(time.RFC3339),
+		TimeZone:    n.TimeZone,
+		AllDay:      n.AllDay,
 	}
 }
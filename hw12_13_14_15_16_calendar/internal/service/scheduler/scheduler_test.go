@@ -2,72 +2,355 @@ package scheduler_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/config"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/rmq"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/service/scheduler"
+	storagecommon "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/types"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/mocks"
 	"github.com/golang/mock/gomock" //nolint:depguard
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 )
 
-func TestScheduler_Run(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+func newScheduler(
+	t *testing.T,
+	resendAfter time.Duration,
+) (*scheduler.Scheduler, *mocks.MockApplication, *mocks.MockRmqClient, chan rmq.Delivery) {
+	t.Helper()
 
+	ctrl := gomock.NewController(t)
 	mockApp := mocks.NewMockApplication(ctrl)
 	mockRmq := mocks.NewMockRmqClient(ctrl)
 	mockLog := mocks.NewMockLogger(ctrl)
 
-	now := time.Now()
-	event := types.Event{
-		ID:           "event_id",
-		Title:        "Team Meeting",
-		Description:  "Discuss roadmap",
-		UserID:       "user1",
-		StartTime:    now.Add(10 * time.Second),
-		EndTime:      now.Add(1 * time.Hour),
-		NotifyBefore: 600,
-	}
-
 	cfg := &config.SchedulerConfig{
 		Scheduler: config.Scheduler{
 			Interval:        10 * time.Millisecond,
 			RetentionPeriod: 8760 * time.Hour, // 1 год
+			ResendAfter:     resendAfter,
 		},
 	}
 
-	mockApp.EXPECT().
-		ListEventsDueBefore(gomock.Any(), gomock.Any()).
-		Return([]types.Event{event}, nil).
-		AnyTimes()
+	statuses := make(chan rmq.Delivery)
+	mockRmq.EXPECT().Consume(rmq.StatusQueue).Return((<-chan rmq.Delivery)(statuses), nil)
+	mockApp.EXPECT().ScheduleNotifications(gomock.Any(), gomock.Any(), gomock.Any()).Return(0, nil).AnyTimes()
+	mockApp.EXPECT().DeleteOlderThan(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockLog.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+	mockLog.EXPECT().Warnf(gomock.Any(), gomock.Any()).AnyTimes()
+	mockLog.EXPECT().Errorf(gomock.Any(), gomock.Any()).AnyTimes()
 
-	mockRmq.EXPECT().
-		Publish(event.UserID, gomock.Any()).
-		Return(nil).
-		AnyTimes()
+	mockLeader := mocks.NewMockLeader(ctrl)
+	mockLeader.EXPECT().IsLeader().Return(true).AnyTimes()
 
-	mockApp.EXPECT().
-		DeleteOlderThan(gomock.Any(), gomock.Any()).
-		Return(nil).
-		AnyTimes()
-
-	mockLog.EXPECT().
-		Infof(gomock.Any(), gomock.Any()).
-		AnyTimes()
+	return scheduler.NewScheduler(mockApp, mockRmq, mockLog, cfg, mockLeader), mockApp, mockRmq, statuses
+}
 
-	sched := scheduler.NewScheduler(mockApp, mockRmq, mockLog, cfg)
+func run(t *testing.T, sched *scheduler.Scheduler) {
+	t.Helper()
 
 	ctx, cancel := context.WithCancel(context.Background())
-	t.Cleanup(cancel)
-
+	done := make(chan error)
 	go func() {
-		err := sched.Run(ctx)
-		require.Error(t, err)
+		done <- sched.Run(ctx)
 	}()
+	t.Cleanup(func() {
+		cancel()
+		require.ErrorIs(t, <-done, context.Canceled)
+	})
+}
+
+func TestScheduler_SchedulesWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		catchUp time.Duration
+		want    time.Duration
+	}{
+		{name: "default catch-up", want: 10 * time.Millisecond},
+		{name: "configured catch-up", catchUp: time.Hour, want: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockApp := mocks.NewMockApplication(ctrl)
+			mockRmq := mocks.NewMockRmqClient(ctrl)
+			mockLog := mocks.NewMockLogger(ctrl)
+			mockLeader := mocks.NewMockLeader(ctrl)
+			mockLeader.EXPECT().IsLeader().Return(true).AnyTimes()
+			cfg := &config.SchedulerConfig{
+				Scheduler: config.Scheduler{Interval: 10 * time.Millisecond, CatchUp: tt.catchUp},
+			}
+
+			windows := make(chan [2]time.Time, 1)
+			mockRmq.EXPECT().Consume(rmq.StatusQueue).Return(make(<-chan rmq.Delivery), nil)
+			mockApp.EXPECT().ScheduleNotifications(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, from, to time.Time) (int, error) {
+					select {
+					case windows <- [2]time.Time{from, to}:
+					default:
+					}
+					return 0, nil
+				}).AnyTimes()
+			mockApp.EXPECT().PendingNotifications(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			mockApp.EXPECT().DeleteOlderThan(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			mockLog.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				_ = scheduler.NewScheduler(mockApp, mockRmq, mockLog, cfg, mockLeader).Run(ctx)
+			}()
+
+			var window [2]time.Time
+			select {
+			case window = <-windows:
+			case <-time.After(5 * time.Second):
+				t.Fatal("no notifications scheduled")
+			}
+			require.Equal(t, tt.want+10*time.Millisecond, window[1].Sub(window[0]))
+			require.WithinDuration(t, time.Now().Add(-tt.want), window[0], 5*time.Second)
+		})
+	}
+}
+
+func TestScheduler_PublishesFromOutbox(t *testing.T) {
+	sched, mockApp, mockRmq, _ := newScheduler(t, 0)
+
+	occurrence := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	notification := types.Notification{
+		ID:          "notification_id",
+		EventID:     "event_id",
+		UserID:      "user1",
+		Title:       "Team Meeting",
+		Description: "Discuss roadmap",
+		Occurrence:  occurrence,
+		NotifyAt:    occurrence.Add(-10 * time.Minute),
+	}
+
+	published := make(chan rmq.Notification, 1)
+	gomock.InOrder(
+		mockApp.EXPECT().PendingNotifications(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]types.Notification{notification}, nil),
+		mockRmq.EXPECT().Publish(gomock.Any(), "notifications.user1", gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ string, body []byte) error {
+				var message rmq.Notification
+				require.NoError(t, json.Unmarshal(body, &message))
+				published <- message
+				return nil
+			}),
+		mockApp.EXPECT().MarkNotificationPublished(gomock.Any(), "notification_id").Return(nil),
+		mockApp.EXPECT().PendingNotifications(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes(),
+	)
+
+	run(t, sched)
+
+	message := <-published
+	require.Equal(t, "notification_id", message.ID)
+	require.Equal(t, "event_id", message.EventID)
+	require.Equal(t, "2025-06-02T09:00:00Z", message.Time)
+	require.Equal(t, "2025-06-02T08:50:00Z", message.NotifyAt)
+}
+
+func TestScheduler_KeepsFailedNotificationPending(t *testing.T) {
+	sched, mockApp, mockRmq, _ := newScheduler(t, 0)
+
+	notification := types.Notification{ID: "notification_id", UserID: "user1"}
+	retried := make(chan struct{})
+	gomock.InOrder(
+		mockApp.EXPECT().PendingNotifications(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]types.Notification{notification}, nil),
+		mockRmq.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("connection closed")),
+		mockApp.EXPECT().PendingNotifications(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]types.Notification{notification}, nil),
+		mockRmq.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		mockApp.EXPECT().MarkNotificationPublished(gomock.Any(), "notification_id").DoAndReturn(
+			func(context.Context, string) error {
+				close(retried)
+				return nil
+			}),
+		mockApp.EXPECT().PendingNotifications(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes(),
+	)
+
+	run(t, sched)
+	<-retried
+}
+
+func TestScheduler_PublishOutcomes(t *testing.T) {
+	first := types.Notification{ID: "first", UserID: "user1"}
+	second := types.Notification{ID: "second", UserID: "user2"}
+
+	tests := []struct {
+		name       string
+		publishErr error
+		reason     string
+		attempted  []string
+	}{
+		{name: "unroutable", publishErr: rmq.ErrUnroutable, reason: "unroutable", attempted: []string{"first", "second"}},
+		{name: "rejected", publishErr: rmq.ErrNacked, reason: "rejected", attempted: []string{"first", "second"}},
+		{
+			name:       "not confirmed",
+			publishErr: rmq.ErrConfirmTimeout,
+			reason:     "unconfirmed",
+			attempted:  []string{"first", "second"},
+		},
+		{name: "disconnected", publishErr: rmq.ErrNotConnected, reason: "disconnected", attempted: []string{"first"}},
+	}
 
-	time.Sleep(20 * time.Millisecond)
-	cancel()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sched, mockApp, mockRmq, _ := newScheduler(t, 0)
+			failures := metrics.NotificationPublishFailures.WithLabelValues(tt.reason)
+			failed := testutil.ToFloat64(failures)
+
+			ticked := make(chan struct{})
+			attempted := make(chan string, 2)
+			gomock.InOrder(
+				mockApp.EXPECT().PendingNotifications(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return([]types.Notification{first, second}, nil),
+				mockApp.EXPECT().PendingNotifications(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(context.Context, time.Time, int) ([]types.Notification, error) {
+						close(ticked)
+						return nil, nil
+					}),
+				mockApp.EXPECT().PendingNotifications(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes(),
+			)
+			mockRmq.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ string, body []byte) error {
+					var message rmq.Notification
+					require.NoError(t, json.Unmarshal(body, &message))
+					attempted <- message.ID
+					return tt.publishErr
+				}).Times(len(tt.attempted))
+
+			run(t, sched)
+			<-ticked
+
+			close(attempted)
+			var ids []string
+			for id := range attempted {
+				ids = append(ids, id)
+			}
+			require.Equal(t, tt.attempted, ids)
+			require.Equal(t, float64(len(tt.attempted)), testutil.ToFloat64(failures)-failed)
+		})
+	}
+}
+
+func TestScheduler_ResendsUnconfirmed(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		resendAfter time.Duration
+	}{
+		{name: "disabled"},
+		{name: "enabled", resendAfter: 5 * time.Minute},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			sched, mockApp, _, _ := newScheduler(t, tt.resendAfter)
+
+			requested := make(chan time.Time, 1)
+			mockApp.EXPECT().PendingNotifications(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, resendBefore time.Time, _ int) ([]types.Notification, error) {
+					select {
+					case requested <- resendBefore:
+					default:
+					}
+					return nil, nil
+				}).AnyTimes()
+
+			run(t, sched)
+
+			resendBefore := <-requested
+			if tt.resendAfter == 0 {
+				require.True(t, resendBefore.IsZero())
+				return
+			}
+			require.WithinDuration(t, time.Now().Add(-tt.resendAfter), resendBefore, time.Second)
+		})
+	}
+}
+
+func TestScheduler_FollowerDoesNotTick(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockApplication(ctrl)
+	mockRmq := mocks.NewMockRmqClient(ctrl)
+	mockLog := mocks.NewMockLogger(ctrl)
+	mockLeader := mocks.NewMockLeader(ctrl)
+	cfg := &config.SchedulerConfig{Scheduler: config.Scheduler{Interval: time.Millisecond}}
+
+	checked := make(chan struct{}, 1)
+	statuses := make(chan rmq.Delivery)
+	mockRmq.EXPECT().Consume(rmq.StatusQueue).Return((<-chan rmq.Delivery)(statuses), nil)
+	mockLog.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+	mockLeader.EXPECT().IsLeader().DoAndReturn(func() bool {
+		select {
+		case checked <- struct{}{}:
+		default:
+		}
+		return false
+	}).AnyTimes()
+
+	run(t, scheduler.NewScheduler(mockApp, mockRmq, mockLog, cfg, mockLeader))
+	for range 3 {
+		<-checked
+	}
+
+	body, err := json.Marshal(rmq.NotificationStatus{NotificationID: "notification_id", Status: rmq.StatusDelivered})
+	require.NoError(t, err)
+	mockApp.EXPECT().RecordNotificationStatus(gomock.Any(), gomock.Any()).Return(nil)
+	ack := mocks.NewMockAcknowledger(ctrl)
+	acked := make(chan struct{})
+	ack.EXPECT().Ack().DoAndReturn(func() error { close(acked); return nil })
+
+	statuses <- rmq.Delivery{Body: body, Attempt: 1, Acknowledger: ack}
+	<-acked
+}
+
+func TestScheduler_RecordsStatuses(t *testing.T) {
+	reportedAt := time.Date(2025, 6, 2, 8, 50, 1, 0, time.UTC)
+	status := rmq.NotificationStatus{NotificationID: "notification_id", Status: rmq.StatusDelivered, Timestamp: reportedAt}
+	body, err := json.Marshal(status)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		body      []byte
+		recordErr error
+		settle    string
+	}{
+		{name: "recorded", body: body, settle: "ack"},
+		{name: "unknown notification", body: body, recordErr: storagecommon.ErrNotificationNotFound, settle: "ack"},
+		{name: "invalid status", body: body, recordErr: storagecommon.ErrInvalidNotification, settle: "dead"},
+		{name: "storage failure", body: body, recordErr: errors.New("connection reset"), settle: "retry"},
+		{name: "malformed", body: []byte("{"), settle: "dead"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sched, mockApp, _, statuses := newScheduler(t, 0)
+			mockApp.EXPECT().PendingNotifications(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			if json.Valid(tt.body) {
+				mockApp.EXPECT().RecordNotificationStatus(gomock.Any(), types.NotificationStatus{
+					NotificationID: "notification_id", Status: rmq.StatusDelivered, ReportedAt: reportedAt,
+				}).Return(tt.recordErr)
+			}
+
+			settled := make(chan string, 1)
+			ack := mocks.NewMockAcknowledger(gomock.NewController(t))
+			ack.EXPECT().Ack().DoAndReturn(func() error { settled <- "ack"; return nil }).AnyTimes()
+			ack.EXPECT().Retry(gomock.Any()).DoAndReturn(func(string) error { settled <- "retry"; return nil }).AnyTimes()
+			ack.EXPECT().DeadLetter(gomock.Any()).DoAndReturn(func(string) error { settled <- "dead"; return nil }).AnyTimes()
+
+			run(t, sched)
+
+			statuses <- rmq.Delivery{Body: tt.body, Attempt: 1, Acknowledger: ack}
+			require.Equal(t, tt.settle, <-settled)
+		})
+	}
 }
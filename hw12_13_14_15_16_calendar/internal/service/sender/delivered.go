@@ -0,0 +1,36 @@
+package sender
+
+import "time"
+
+// deliveredTTL is how long a delivered notification is remembered. It outlasts the resend delay of the
+// scheduler, so the copy published again because the delivered status was lost is still recognized.
+const deliveredTTL = 24 * time.Hour
+
+// delivered remembers the notifications this sender delivered and when, oldest first, so that a copy
+// of one is not delivered to the user again.
+type delivered struct {
+	at    map[string]time.Time
+	order []string
+}
+
+func newDelivered() *delivered {
+	return &delivered{at: make(map[string]time.Time)}
+}
+
+// get returns when the notification was delivered.
+func (d *delivered) get(id string) (time.Time, bool) {
+	at, ok := d.at[id]
+	return at, ok
+}
+
+// add records the delivery and forgets the deliveries older than deliveredTTL.
+func (d *delivered) add(id string, at time.Time) {
+	for len(d.order) > 0 && at.Sub(d.at[d.order[0]]) > deliveredTTL {
+		delete(d.at, d.order[0])
+		d.order = d.order[1:]
+	}
+	if _, ok := d.at[id]; !ok {
+		d.order = append(d.order, id)
+	}
+	d.at[id] = at
+}
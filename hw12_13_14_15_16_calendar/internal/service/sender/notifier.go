@@ -0,0 +1,70 @@
+package sender
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/config"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/notifier"
+)
+
+// NewNotifier sets up the channels the configuration refers to; the file channel is the default.
+func NewNotifier(cfg config.Notifier) (*notifier.Router, error) {
+	fallback := cfg.Channel
+	if fallback == "" {
+		fallback = notifier.ChannelFile
+	}
+
+	used := map[string]bool{fallback: true}
+	routes := make(map[string]notifier.Route, len(cfg.Users))
+	for userID, user := range cfg.Users {
+		if user.Channel == "" {
+			user.Channel = fallback
+		}
+		used[user.Channel] = true
+		routes[userID] = notifier.Route{Channel: user.Channel, Address: user.Address}
+	}
+
+	channels := make(map[string]notifier.Channel, len(used))
+	for name := range used {
+		channel, err := newChannel(name, cfg)
+		if err != nil {
+			for _, opened := range channels {
+				closeChannel(opened)
+			}
+			return nil, err
+		}
+		channels[name] = channel
+	}
+	return notifier.NewRouter(channels, fallback, routes)
+}
+
+func newChannel(name string, cfg config.Notifier) (notifier.Channel, error) {
+	switch name {
+	case notifier.ChannelFile:
+		return notifier.NewFile(cfg.File.Path)
+	case notifier.ChannelSMTP:
+		return notifier.NewSMTP(notifier.SMTPConfig{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+			Timeout:  cfg.SMTP.Timeout,
+		})
+	case notifier.ChannelWebhook:
+		return notifier.NewWebhook(notifier.WebhookConfig{
+			URL:     cfg.Webhook.URL,
+			Secret:  cfg.Webhook.Secret,
+			Timeout: cfg.Webhook.Timeout,
+		})
+	default:
+		return nil, fmt.Errorf("%w: %q", notifier.ErrUnknownChannel, name)
+	}
+}
+
+func closeChannel(channel notifier.Channel) {
+	if closer, ok := channel.(io.Closer); ok {
+		_ = closer.Close()
+	}
+}
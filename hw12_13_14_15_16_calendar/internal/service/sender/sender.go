@@ -3,27 +3,40 @@ package sender
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/config"
 	i "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/interface"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/metrics"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/rmq"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/tracing"
+	"go.opentelemetry.io/otel/attribute" //nolint:depguard
+	"go.opentelemetry.io/otel/trace"     //nolint:depguard
 )
 
 type Sender struct {
-	rmq    i.RmqClient
-	logger i.Logger
-	cfg    *config.SenderConfig
+	rmq      i.RmqClient
+	notifier i.Notifier
+	logger   i.Logger
+	cfg      *config.SenderConfig
+
+	delivered *delivered
 }
 
-func NewSender(rmq i.RmqClient, logger i.Logger, cfg *config.SenderConfig) *Sender {
+func NewSender(rmq i.RmqClient, notifier i.Notifier, logger i.Logger, cfg *config.SenderConfig) *Sender {
 	return &Sender{
-		rmq:    rmq,
-		logger: logger,
-		cfg:    cfg,
+		rmq:       rmq,
+		notifier:  notifier,
+		logger:    logger,
+		cfg:       cfg,
+		delivered: newDelivered(),
 	}
 }
 
+// Run delivers the notifications from the queue and reports the outcome of every attempt. Failed
+// deliveries are retried with backoff until the retries are exhausted and the message is dead-lettered;
+// a notification interrupted by shutdown stays unacknowledged and is delivered again after restart.
 func (s *Sender) Run(ctx context.Context) error {
 	s.logger.Infof("Sender started")
 
@@ -38,34 +51,101 @@ func (s *Sender) Run(ctx context.Context) error {
 		case <-ctx.Done():
 			s.logger.Infof("Stopping sender...")
 			return ctx.Err()
-		case body := <-msgChan:
-			var notif rmq.Notification
-			if err := json.Unmarshal(body, &notif); err != nil {
-				s.logger.Errorf("Failed to unmarshal notification: %v", err)
-				continue
+		case delivery, ok := <-msgChan:
+			if !ok {
+				return fmt.Errorf("queue %s closed", s.cfg.QueueName)
 			}
-
-			if notif.UserID == "" || notif.ID == "" {
-				s.logger.Warnf("Received invalid notification: %+v", notif)
-				continue
+			if err := s.handle(ctx, delivery); err != nil {
+				return err
 			}
+		}
+	}
+}
+
+// handle returns an error only when the context is done. A copy of a notification this sender already
+// delivered, see rmq.Notification, is not delivered again; its delivered status is reported again with
+// the original time instead, which the storage records once.
+func (s *Sender) handle(ctx context.Context, delivery rmq.Delivery) error {
+	ctx = delivery.WithTrace(ctx)
+	var notif rmq.Notification
+	if err := json.Unmarshal(delivery.Body, &notif); err != nil {
+		s.logger.Errorf("Failed to unmarshal notification: %v", err)
+		metrics.NotificationsSent.WithLabelValues("malformed").Inc()
+		s.settle(delivery.DeadLetter(err.Error()))
+		return nil
+	}
 
-			s.logger.Infof("Received notification: %+v", notif)
+	if notif.UserID == "" || notif.ID == "" {
+		s.logger.Warnf("Received invalid notification: %+v", notif)
+		metrics.NotificationsSent.WithLabelValues("malformed").Inc()
+		s.settle(delivery.DeadLetter("notification without id or user"))
+		return nil
+	}
 
-			if err := s.sendStatus(notif, "delivered"); err != nil {
-				s.logger.Errorf("Error sending delivered status: %v", err)
-			}
+	s.logger.Infof("Received notification: %+v (attempt %d)", notif, delivery.Attempt)
+
+	if at, ok := s.delivered.get(notif.ID); ok {
+		s.logger.Infof("Notification %s already delivered at %s, skipped", notif.ID, at)
+		metrics.NotificationsSent.WithLabelValues("duplicate").Inc()
+		if err := s.sendStatus(ctx, notif, rmq.StatusDelivered, at); err != nil {
+			s.logger.Errorf("Error sending %s status: %v", rmq.StatusDelivered, err)
+		}
+		s.settle(delivery.Ack())
+		return nil
+	}
+
+	if err := s.notify(ctx, notif); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		s.logger.Errorf("Failed to deliver notification %s: %v", notif.ID, err)
+		metrics.NotificationsSent.WithLabelValues(rmq.StatusFailed).Inc()
+		if err := s.sendStatus(ctx, notif, rmq.StatusFailed, time.Now()); err != nil {
+			s.logger.Errorf("Error sending %s status: %v", rmq.StatusFailed, err)
 		}
+		s.settle(delivery.Retry(err.Error()))
+		return nil
+	}
+
+	metrics.NotificationsSent.WithLabelValues(rmq.StatusDelivered).Inc()
+	deliveredAt := time.Now()
+	s.delivered.add(notif.ID, deliveredAt)
+	if err := s.sendStatus(ctx, notif, rmq.StatusDelivered, deliveredAt); err != nil {
+		s.logger.Errorf("Error sending %s status: %v", rmq.StatusDelivered, err)
+	}
+	s.settle(delivery.Ack())
+	return nil
+}
+
+func (s *Sender) settle(err error) {
+	if err != nil {
+		s.logger.Errorf("Failed to settle notification message: %v", err)
 	}
 }
 
-func (s *Sender) sendStatus(notification rmq.Notification, status string) error {
+// notify delivers the notification through the notifier in a span of its own, to tell the time spent
+// in the channel from the time spent in the broker.
+func (s *Sender) notify(ctx context.Context, notification rmq.Notification) (err error) {
+	ctx, span := tracing.Start(ctx, "Sender.notify", trace.WithAttributes(
+		attribute.String("notification.id", notification.ID),
+		attribute.String("user.id", notification.UserID),
+	))
+	defer func() { tracing.End(span, err) }()
+	return s.notifier.Notify(ctx, notification)
+}
+
+func (s *Sender) sendStatus(
+	ctx context.Context,
+	notification rmq.Notification,
+	status string,
+	reportedAt time.Time,
+) error {
 	statusMsg := rmq.NotificationStatus{
 		NotificationID: notification.ID,
-		EventID:        notification.ID, // или передавать отдельно
+		EventID:        notification.EventID,
 		UserID:         notification.UserID,
 		Status:         status,
-		Timestamp:      time.Now(),
+		Timestamp:      reportedAt,
 	}
 
 	body, err := json.Marshal(statusMsg)
@@ -73,7 +153,7 @@ func (s *Sender) sendStatus(notification rmq.Notification, status string) error
 		return err
 	}
 
-	err = s.rmq.Publish("status.notification", body)
+	err = s.rmq.Publish(ctx, rmq.StatusRoutingKey, body)
 	if err != nil {
 		s.logger.Errorf("Failed to publish status for notification %s: %v", notification.ID, err)
 		return err
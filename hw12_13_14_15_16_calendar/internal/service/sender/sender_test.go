@@ -0,0 +1,174 @@
+package sender_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/config"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/rmq"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/service/sender"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/mocks"
+	"github.com/golang/mock/gomock" //nolint:depguard
+	"github.com/stretchr/testify/require"
+)
+
+func TestSender_ReportsChannelOutcome(t *testing.T) {
+	notification := rmq.Notification{ID: "notification_id", EventID: "event_id", UserID: "user1"}
+	body, err := json.Marshal(notification)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		body      []byte
+		notifyErr error
+		status    string
+		settle    string
+	}{
+		{name: "delivered", body: body, status: rmq.StatusDelivered, settle: "ack"},
+		{name: "failed", body: body, notifyErr: errors.New("connection refused"), status: rmq.StatusFailed, settle: "retry"},
+		{name: "malformed", body: []byte(`{"id":`), settle: "dead"},
+		{name: "without user", body: []byte(`{"id":"notification_id"}`), settle: "dead"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRmq := mocks.NewMockRmqClient(ctrl)
+			mockNotifier := mocks.NewMockNotifier(ctrl)
+			mockLog := mocks.NewMockLogger(ctrl)
+			mockLog.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLog.EXPECT().Warnf(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLog.EXPECT().Errorf(gomock.Any(), gomock.Any()).AnyTimes()
+
+			queue := make(chan rmq.Delivery, 1)
+			mockRmq.EXPECT().Consume("notifications").Return((<-chan rmq.Delivery)(queue), nil)
+
+			reported := make(chan rmq.NotificationStatus, 1)
+			if tt.status != "" {
+				mockNotifier.EXPECT().Notify(gomock.Any(), notification).Return(tt.notifyErr)
+				mockRmq.EXPECT().Publish(gomock.Any(), rmq.StatusRoutingKey, gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ string, body []byte) error {
+						var status rmq.NotificationStatus
+						require.NoError(t, json.Unmarshal(body, &status))
+						reported <- status
+						return nil
+					})
+			}
+
+			settled := make(chan string, 1)
+			ack := mocks.NewMockAcknowledger(ctrl)
+			ack.EXPECT().Ack().DoAndReturn(func() error { settled <- "ack"; return nil }).AnyTimes()
+			ack.EXPECT().Retry(gomock.Any()).DoAndReturn(func(string) error { settled <- "retry"; return nil }).AnyTimes()
+			ack.EXPECT().DeadLetter(gomock.Any()).DoAndReturn(func(string) error { settled <- "dead"; return nil }).AnyTimes()
+
+			cfg := &config.SenderConfig{QueueName: "notifications"}
+			s := sender.NewSender(mockRmq, mockNotifier, mockLog, cfg)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error)
+			go func() { done <- s.Run(ctx) }()
+
+			queue <- rmq.Delivery{Body: tt.body, Attempt: 1, Acknowledger: ack}
+			require.Equal(t, tt.settle, <-settled)
+			cancel()
+			require.ErrorIs(t, <-done, context.Canceled)
+
+			if tt.status == "" {
+				return
+			}
+			status := <-reported
+			require.Equal(t, tt.status, status.Status)
+			require.Equal(t, "notification_id", status.NotificationID)
+			require.Equal(t, "event_id", status.EventID)
+			require.Equal(t, "user1", status.UserID)
+		})
+	}
+}
+
+func TestSender_SkipsDeliveredCopy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRmq := mocks.NewMockRmqClient(ctrl)
+	mockNotifier := mocks.NewMockNotifier(ctrl)
+	mockLog := mocks.NewMockLogger(ctrl)
+	mockLog.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+
+	queue := make(chan rmq.Delivery, 2)
+	mockRmq.EXPECT().Consume("notifications").Return((<-chan rmq.Delivery)(queue), nil)
+
+	notification := rmq.Notification{ID: "notification_id", EventID: "event_id", UserID: "user1"}
+	mockNotifier.EXPECT().Notify(gomock.Any(), notification).Return(nil).Times(1)
+
+	reported := make(chan rmq.NotificationStatus, 2)
+	mockRmq.EXPECT().Publish(gomock.Any(), rmq.StatusRoutingKey, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, body []byte) error {
+			var status rmq.NotificationStatus
+			require.NoError(t, json.Unmarshal(body, &status))
+			reported <- status
+			return nil
+		}).Times(2)
+
+	settled := make(chan struct{}, 2)
+	ack := mocks.NewMockAcknowledger(ctrl)
+	ack.EXPECT().Ack().DoAndReturn(func() error { settled <- struct{}{}; return nil }).Times(2)
+
+	body, err := json.Marshal(notification)
+	require.NoError(t, err)
+	queue <- rmq.Delivery{Body: body, Attempt: 1, Acknowledger: ack}
+	queue <- rmq.Delivery{Body: body, Attempt: 1, Acknowledger: ack}
+
+	s := sender.NewSender(mockRmq, mockNotifier, mockLog, &config.SenderConfig{QueueName: "notifications"})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Run(ctx) }()
+
+	<-settled
+	<-settled
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	first, second := <-reported, <-reported
+	require.Equal(t, rmq.StatusDelivered, first.Status)
+	require.Equal(t, first, second)
+}
+
+func TestSender_LeavesInterruptedUnsettled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRmq := mocks.NewMockRmqClient(ctrl)
+	mockNotifier := mocks.NewMockNotifier(ctrl)
+	mockLog := mocks.NewMockLogger(ctrl)
+	mockLog.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+
+	queue := make(chan rmq.Delivery, 1)
+	mockRmq.EXPECT().Consume("notifications").Return((<-chan rmq.Delivery)(queue), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockNotifier.EXPECT().Notify(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ rmq.Notification) error {
+			cancel()
+			return ctx.Err()
+		})
+
+	s := sender.NewSender(mockRmq, mockNotifier, mockLog, &config.SenderConfig{QueueName: "notifications"})
+	queue <- rmq.Delivery{
+		Body:         []byte(`{"id":"notification_id","userId":"user1"}`),
+		Attempt:      1,
+		Acknowledger: mocks.NewMockAcknowledger(ctrl),
+	}
+	require.ErrorIs(t, s.Run(ctx), context.Canceled)
+}
+
+func TestNewNotifier(t *testing.T) {
+	router, err := sender.NewNotifier(config.Notifier{})
+	require.NoError(t, err)
+	require.NoError(t, router.Close())
+
+	_, err = sender.NewNotifier(config.Notifier{Channel: "sms"})
+	require.Error(t, err)
+
+	_, err = sender.NewNotifier(config.Notifier{
+		Users: map[string]config.NotifierUser{"alice": {Channel: "smtp", Address: "alice@example.com"}},
+	})
+	require.Error(t, err, "smtp is used but not configured")
+}
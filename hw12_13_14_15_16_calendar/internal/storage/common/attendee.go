@@ -0,0 +1,24 @@
+package storagecommon
+
+const (
+	AttendeeNeedsAction = "needs-action"
+	AttendeeAccepted    = "accepted"
+	AttendeeDeclined    = "declined"
+	AttendeeTentative   = "tentative"
+)
+
+// Attendee is a user invited to someone else's event and their response.
+type Attendee struct {
+	EventID string `db:"event_id"`
+	UserID  string `db:"user_id"`
+	Status  string `db:"status"`
+}
+
+func ValidAttendeeStatus(status string) bool {
+	switch status {
+	case AttendeeNeedsAction, AttendeeAccepted, AttendeeDeclined, AttendeeTentative:
+		return true
+	default:
+		return false
+	}
+}
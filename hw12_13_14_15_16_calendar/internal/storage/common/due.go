@@ -0,0 +1,76 @@
+package storagecommon
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DueOccurrences returns the occurrences whose reminder is due in the half-open window [from, to).
+func (e Event) DueOccurrences(from, to time.Time) []Event {
+	notifyBefore := time.Duration(e.NotifyBefore) * time.Second
+	occurrences := e.Occurrences(from.Add(notifyBefore), to.Add(notifyBefore))
+
+	due := occurrences[:0]
+	for _, occurrence := range occurrences {
+		if notifyAt := occurrence.NotifyTime(); !notifyAt.Before(from) && notifyAt.Before(to) {
+			due = append(due, occurrence)
+		}
+	}
+	return due
+}
+
+// LastNotifyTime returns the time of the reminder of the last occurrence; ok is false for open-ended
+// series, whose reminders never stop. Storages keep it to skip the series that can no longer be due.
+func (e Event) LastNotifyTime() (notifyAt time.Time, ok bool) {
+	rule, err := e.rule()
+	if err != nil {
+		return e.NotifyTime(), true
+	}
+
+	last, ok := rule.Last(e.StartTime.In(e.Location()))
+	if !ok {
+		return time.Time{}, false
+	}
+	return last.Add(-time.Duration(e.NotifyBefore) * time.Second), true
+}
+
+// FillLastNotifyAt records the time of the last reminder of the bounded series a storage wrote before
+// it kept the time; until then they are loaded on every tick like open-ended ones. load selects the series
+// missing the time, store saves it for one of them.
+func FillLastNotifyAt(
+	ctx context.Context,
+	load func(ctx context.Context) ([]Event, error),
+	store func(ctx context.Context, id string, notifyAt time.Time) error,
+) error {
+	series, err := load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to select series: %w", err)
+	}
+
+	for _, event := range series {
+		notifyAt, ok := event.LastNotifyTime()
+		if !ok {
+			continue
+		}
+		if err := store(ctx, event.ID, notifyAt); err != nil {
+			return fmt.Errorf("failed to fill last_notify_at of event %s: %w", event.ID, err)
+		}
+	}
+	return nil
+}
+
+// SortDue orders occurrences by the time of their reminder, then by start and event ID.
+func SortDue(occurrences []Event) {
+	sort.Slice(occurrences, func(i, j int) bool {
+		a, b := occurrences[i], occurrences[j]
+		if !a.NotifyTime().Equal(b.NotifyTime()) {
+			return a.NotifyTime().Before(b.NotifyTime())
+		}
+		if !a.StartTime.Equal(b.StartTime) {
+			return a.StartTime.Before(b.StartTime)
+		}
+		return a.ID < b.ID
+	})
+}
@@ -3,9 +3,17 @@ package storagecommon
 import "fmt"
 
 var (
-	ErrEventNotFound   = fmt.Errorf("event not found")
-	ErrDateBusy        = fmt.Errorf("the selected time is already busy")
-	ErrInvalidEvent    = fmt.Errorf("invalid event data")
-	ErrAlreadyExists   = fmt.Errorf("event already exists")
-	ErrConflictOverlap = fmt.Errorf("event overlaps with another event")
+	ErrEventNotFound    = fmt.Errorf("event not found")
+	ErrDateBusy         = fmt.Errorf("the selected time is already busy")
+	ErrInvalidEvent     = fmt.Errorf("invalid event data")
+	ErrAlreadyExists    = fmt.Errorf("event already exists")
+	ErrConflictOverlap  = fmt.Errorf("event overlaps with another event")
+	ErrInvalidPageToken = fmt.Errorf("invalid page token")
+	ErrAttendeeNotFound = fmt.Errorf("attendee not found")
+	ErrAttendeeExists   = fmt.Errorf("user is already invited")
+	ErrInvalidAttendee  = fmt.Errorf("invalid attendee")
+	ErrVersionConflict  = fmt.Errorf("event version does not match")
+
+	ErrNotificationNotFound = fmt.Errorf("notification not found")
+	ErrInvalidNotification  = fmt.Errorf("invalid notification state")
 )
@@ -10,6 +10,32 @@ type Event struct {
 	Description  string    `db:"description"`
 	UserID       string    `db:"user_id"`
 	NotifyBefore int       `db:"notify_before"`
+	RRule        string    `db:"rrule"`
+	ExDates      TimeList  `db:"exdates"`
+	TimeZone     string    `db:"time_zone"`
+	AllDay       bool      `db:"all_day"`
+	Version      int64     `db:"version"`
+}
+
+// InitialVersion is the version of a newly created event; every update increments it.
+const InitialVersion = 1
+
+// MatchesVersion reports whether a write expecting the given version may proceed;
+// zero skips the check.
+func (e Event) MatchesVersion(version int64) bool {
+	return version == 0 || e.Version == version
+}
+
+// IsDuplicateOf reports whether the events describe the same meeting; storages reject such a Create
+// with ErrAlreadyExists.
+func (e Event) IsDuplicateOf(o Event) bool {
+	return e.UserID == o.UserID &&
+		e.Title == o.Title &&
+		e.StartTime.Equal(o.StartTime) &&
+		e.EndTime.Equal(o.EndTime) &&
+		e.Description == o.Description &&
+		e.NotifyBefore == o.NotifyBefore &&
+		e.RRule == o.RRule
 }
 
 func (e Event) With(fn func(Event) Event) Event {
@@ -0,0 +1,66 @@
+package storagecommon
+
+import "time"
+
+const (
+	NotificationPending   = "pending"
+	NotificationPublished = "published"
+	NotificationDelivered = "delivered"
+)
+
+// Notification is the outbox entry for the reminder about one occurrence of an event. An occurrence
+// has at most one entry, so scheduling it again has no effect.
+type Notification struct {
+	ID          string    `db:"id"`
+	EventID     string    `db:"event_id"`
+	UserID      string    `db:"user_id"`
+	Title       string    `db:"title"`
+	Description string    `db:"description"`
+	Occurrence  time.Time `db:"occurrence"`
+	NotifyAt    time.Time `db:"notify_at"`
+	TimeZone    string    `db:"time_zone"`
+	AllDay      bool      `db:"all_day"`
+	State       string    `db:"state"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// NotificationStatus is a status reported by the sender for a notification. A status reported again
+// with the same time is recorded once.
+type NotificationStatus struct {
+	NotificationID string    `db:"notification_id"`
+	EventID        string    `db:"event_id"`
+	UserID         string    `db:"user_id"`
+	Status         string    `db:"status"`
+	ReportedAt     time.Time `db:"reported_at"`
+}
+
+// NewNotification returns a pending notification about an occurrence returned by Event.Occurrences.
+func NewNotification(occurrence Event, now time.Time) Notification {
+	return Notification{
+		EventID:     occurrence.ID,
+		UserID:      occurrence.UserID,
+		Title:       occurrence.Title,
+		Description: occurrence.Description,
+		Occurrence:  occurrence.StartTime,
+		NotifyAt:    occurrence.NotifyTime(),
+		TimeZone:    occurrence.TimeZone,
+		AllDay:      occurrence.AllDay,
+		State:       NotificationPending,
+		UpdatedAt:   now,
+	}
+}
+
+// NotificationStateRank orders the states a notification goes through; it is zero for an unknown state.
+// Storages never move a notification to a state of a lower rank.
+func NotificationStateRank(state string) int {
+	switch state {
+	case NotificationPending:
+		return 1
+	case NotificationPublished:
+		return 2
+	case NotificationDelivered:
+		return 3
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,124 @@
+package storagecommon
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPageSize = 100
+	MaxPageSize     = 1000
+)
+
+// ListQuery selects a page of events ordered by start time and ID.
+// Zero From/To leave the corresponding side of the time window open.
+type ListQuery struct {
+	UserID    string
+	Title     string
+	From      time.Time
+	To        time.Time
+	PageSize  int
+	PageToken string
+}
+
+type EventPage struct {
+	Events        []Event
+	NextPageToken string
+}
+
+// Cursor is the position of the last event of a page.
+type Cursor struct {
+	StartTime time.Time
+	ID        string
+}
+
+func (q ListQuery) Limit() int {
+	switch {
+	case q.PageSize <= 0:
+		return DefaultPageSize
+	case q.PageSize > MaxPageSize:
+		return MaxPageSize
+	default:
+		return q.PageSize
+	}
+}
+
+// Cursor decodes the page token; ok is false for the first page.
+func (q ListQuery) Cursor() (cursor Cursor, ok bool, err error) {
+	if q.PageToken == "" {
+		return Cursor{}, false, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(q.PageToken)
+	if err != nil {
+		return Cursor{}, false, ErrInvalidPageToken
+	}
+	nanos, id, found := strings.Cut(string(raw), ":")
+	if !found || id == "" {
+		return Cursor{}, false, ErrInvalidPageToken
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return Cursor{}, false, ErrInvalidPageToken
+	}
+	return Cursor{StartTime: time.Unix(0, n).UTC(), ID: id}, true, nil
+}
+
+// Matches reports whether the event passes the user, title and time window filters.
+func (q ListQuery) Matches(e Event) bool {
+	if q.UserID != "" && e.UserID != q.UserID {
+		return false
+	}
+	if q.Title != "" && !strings.Contains(strings.ToLower(e.Title), strings.ToLower(q.Title)) {
+		return false
+	}
+	return q.matchesWindow(e)
+}
+
+func (q ListQuery) matchesWindow(e Event) bool {
+	if !q.To.IsZero() && !e.StartTime.Before(q.To) {
+		return false
+	}
+	if q.From.IsZero() {
+		return true
+	}
+	if !e.IsRecurring() {
+		return e.EndTime.After(q.From)
+	}
+	if q.To.IsZero() {
+		end, ok := e.SeriesEnd()
+		return !ok || end.After(q.From)
+	}
+
+	for _, o := range e.Occurrences(q.From, q.To) {
+		if o.EndTime.After(q.From) && o.StartTime.Before(q.To) {
+			return true
+		}
+	}
+	return false
+}
+
+// After reports whether the event sorts after the cursor.
+func (c Cursor) After(e Event) bool {
+	if !e.StartTime.Equal(c.StartTime) {
+		return e.StartTime.After(c.StartTime)
+	}
+	return e.ID > c.ID
+}
+
+// PageToken returns the token of the page that follows the event.
+func PageToken(e Event) string {
+	raw := fmt.Sprintf("%d:%s", e.StartTime.UnixNano(), e.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// Less orders events by start time and then by ID.
+func Less(a, b Event) bool {
+	if !a.StartTime.Equal(b.StartTime) {
+		return a.StartTime.Before(b.StartTime)
+	}
+	return a.ID < b.ID
+}
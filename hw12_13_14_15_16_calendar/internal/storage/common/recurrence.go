@@ -0,0 +1,122 @@
+package storagecommon
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/recurrence"
+)
+
+// OverlapHorizon limits how far ahead two open-ended series are compared for overlaps.
+const OverlapHorizon = 2 * 365 * 24 * time.Hour
+
+func (e Event) IsRecurring() bool {
+	return e.RRule != ""
+}
+
+func (e Event) ValidateRecurrence() error {
+	if !e.IsRecurring() {
+		return nil
+	}
+	if _, err := recurrence.Parse(e.RRule); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidEvent, err)
+	}
+	return nil
+}
+
+// Occurrences expands the event into the occurrences that intersect [from, to].
+// A non-recurring event is returned as is when it intersects the window. Series are
+// expanded in the event's time zone, so occurrences keep their local wall clock time.
+func (e Event) Occurrences(from, to time.Time) []Event {
+	rule, err := e.rule()
+	if err != nil {
+		if !e.EndTime.Before(from) && !e.StartTime.After(to) {
+			return []Event{e}
+		}
+		return nil
+	}
+
+	duration := e.EndTime.Sub(e.StartTime)
+	starts := rule.Between(e.StartTime.In(e.Location()), e.ExDates, from.Add(-duration), to)
+
+	occurrences := make([]Event, 0, len(starts))
+	for _, start := range starts {
+		occurrence := e
+		occurrence.StartTime = start
+		occurrence.EndTime = e.occurrenceEnd(start)
+		occurrences = append(occurrences, occurrence)
+	}
+	return occurrences
+}
+
+// OccurrencesWithin returns the occurrences that intersect the half-open window [from, to),
+// i.e. start before to and end after from.
+func (e Event) OccurrencesWithin(from, to time.Time) []Event {
+	occurrences := e.Occurrences(from, to)
+	within := occurrences[:0]
+	for _, occurrence := range occurrences {
+		if occurrence.EndTime.After(from) && occurrence.StartTime.Before(to) {
+			within = append(within, occurrence)
+		}
+	}
+	return within
+}
+
+// SeriesEnd returns the end of the last occurrence; ok is false for open-ended series.
+func (e Event) SeriesEnd() (end time.Time, ok bool) {
+	rule, err := e.rule()
+	if err != nil {
+		return e.EndTime, true
+	}
+
+	last, ok := rule.Last(e.StartTime.In(e.Location()))
+	if !ok {
+		return time.Time{}, false
+	}
+	return e.occurrenceEnd(last), true
+}
+
+// Overlaps reports whether any occurrence of a intersects any occurrence of b.
+func Overlaps(a, b Event) bool {
+	if !a.IsRecurring() && !b.IsRecurring() {
+		return isOverlapping(a, b)
+	}
+
+	from := a.StartTime
+	if b.StartTime.After(from) {
+		from = b.StartTime
+	}
+	to := from.Add(OverlapHorizon)
+	for _, e := range []Event{a, b} {
+		if end, ok := e.SeriesEnd(); ok && end.Before(to) {
+			to = end
+		}
+	}
+	if !to.After(from) {
+		return false
+	}
+
+	occA, occB := a.Occurrences(from, to), b.Occurrences(from, to)
+	for i, j := 0, 0; i < len(occA) && j < len(occB); {
+		switch {
+		case !occA[i].EndTime.After(occB[j].StartTime):
+			i++
+		case !occB[j].EndTime.After(occA[i].StartTime):
+			j++
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+func (e Event) rule() (recurrence.Rule, error) {
+	if !e.IsRecurring() {
+		return recurrence.Rule{}, fmt.Errorf("event is not recurring")
+	}
+	return recurrence.Parse(e.RRule)
+}
+
+func isOverlapping(a, b Event) bool {
+	return a.StartTime.Before(b.EndTime) && b.StartTime.Before(a.EndTime)
+}
@@ -0,0 +1,51 @@
+package storagecommon
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeList is stored as a comma-separated list of RFC 3339 timestamps.
+type TimeList []time.Time
+
+func (l TimeList) Value() (driver.Value, error) {
+	parts := make([]string, 0, len(l))
+	for _, t := range l {
+		parts = append(parts, t.UTC().Format(time.RFC3339Nano))
+	}
+	return strings.Join(parts, ","), nil
+}
+
+func (l *TimeList) Scan(src any) error {
+	var raw string
+	switch v := src.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("unsupported TimeList source type %T", src)
+	}
+
+	if raw == "" {
+		*l = nil
+		return nil
+	}
+
+	parts := strings.Split(raw, ",")
+	list := make(TimeList, 0, len(parts))
+	for _, part := range parts {
+		t, err := time.Parse(time.RFC3339Nano, part)
+		if err != nil {
+			return fmt.Errorf("failed to parse time list: %w", err)
+		}
+		list = append(list, t)
+	}
+	*l = list
+	return nil
+}
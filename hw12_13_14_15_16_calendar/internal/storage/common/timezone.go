@@ -0,0 +1,82 @@
+package storagecommon
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+var locations sync.Map
+
+// LoadLocation is time.LoadLocation with a cache; an empty name is UTC.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locations.Store(name, loc)
+	return loc, nil
+}
+
+// Location returns the time zone of the event, UTC when it is unset or unknown.
+func (e Event) Location() *time.Location {
+	loc, err := LoadLocation(e.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Normalize validates the time zone and snaps an all-day event to the local
+// midnights of its first day and of the day after its last one.
+func (e Event) Normalize() (Event, error) {
+	loc, err := LoadLocation(e.TimeZone)
+	if err != nil {
+		return e, fmt.Errorf("%w: unknown time zone %q", ErrInvalidEvent, e.TimeZone)
+	}
+	if !e.AllDay {
+		if !e.EndTime.After(e.StartTime) {
+			return e, fmt.Errorf("%w: event must end after it starts", ErrInvalidEvent)
+		}
+		return e, nil
+	}
+
+	start := midnight(e.StartTime.In(loc))
+	end := midnight(e.EndTime.In(loc))
+	if end.Before(e.EndTime) {
+		end = end.AddDate(0, 0, 1)
+	}
+	if !end.After(start) {
+		end = start.AddDate(0, 0, 1)
+	}
+	e.StartTime, e.EndTime = start, end
+	return e, nil
+}
+
+// NotifyTime is when the reminder of the occurrence is due. The start of an all-day
+// event is local midnight in its time zone, so reminders follow that zone too.
+func (e Event) NotifyTime() time.Time {
+	return e.StartTime.Add(-time.Duration(e.NotifyBefore) * time.Second)
+}
+
+// occurrenceEnd keeps the length of an all-day event in days, which differs
+// from a fixed duration when a daylight saving shift falls inside it.
+func (e Event) occurrenceEnd(start time.Time) time.Time {
+	if e.AllDay {
+		days := int(math.Round(e.EndTime.Sub(e.StartTime).Hours() / 24))
+		return start.AddDate(0, 0, max(days, 1))
+	}
+	return start.Add(e.EndTime.Sub(e.StartTime))
+}
+
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
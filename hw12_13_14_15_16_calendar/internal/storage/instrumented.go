@@ -0,0 +1,218 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	i "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/interface"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.30.0" //nolint:depguard
+	"go.opentelemetry.io/otel/trace"                   //nolint:depguard
+)
+
+// instrumented measures the latency of every operation of the storage it wraps and traces it.
+type instrumented struct {
+	next   i.Storage
+	system string
+}
+
+// Instrument wraps the storage so that its operations are measured in the metrics and traced as spans
+// that carry the name of the database system.
+func Instrument(storage i.Storage, system string) i.Storage {
+	return instrumented{next: storage, system: system}
+}
+
+// Unwrap returns the wrapped storage.
+func (s instrumented) Unwrap() i.Storage {
+	return s.next
+}
+
+// Close closes the wrapped storage if it holds resources.
+func (s instrumented) Close(ctx context.Context) error {
+	if closer, ok := s.next.(interface{ Close(context.Context) error }); ok {
+		return closer.Close(ctx)
+	}
+	return nil
+}
+
+// Ping checks the wrapped storage.
+func (s instrumented) Ping(ctx context.Context) error {
+	return Ping(ctx, s.next)
+}
+
+// operation is a storage call being measured.
+type operation struct {
+	name  string
+	start time.Time
+	span  trace.Span
+}
+
+func (s instrumented) begin(ctx context.Context, name string) (context.Context, operation) {
+	ctx, span := tracing.Start(ctx, "storage."+name, trace.WithAttributes(
+		semconv.DBSystemNameKey.String(s.system),
+		semconv.DBOperationName(name),
+	))
+	return ctx, operation{name: name, start: time.Now(), span: span}
+}
+
+func (op operation) end(err *error) {
+	metrics.StorageDuration.WithLabelValues(op.name, metrics.Result(*err)).Observe(time.Since(op.start).Seconds())
+	tracing.End(op.span, *err)
+}
+
+func (s instrumented) Create(ctx context.Context, event storagecommon.Event) (id string, err error) {
+	ctx, op := s.begin(ctx, "create")
+	defer op.end(&err)
+	return s.next.Create(ctx, event)
+}
+
+func (s instrumented) Update(ctx context.Context, event storagecommon.Event) (version int64, err error) {
+	ctx, op := s.begin(ctx, "update")
+	defer op.end(&err)
+	return s.next.Update(ctx, event)
+}
+
+func (s instrumented) Delete(ctx context.Context, id string, version int64) (err error) {
+	ctx, op := s.begin(ctx, "delete")
+	defer op.end(&err)
+	return s.next.Delete(ctx, id, version)
+}
+
+func (s instrumented) DeleteOlder(ctx context.Context, t time.Time) (err error) {
+	ctx, op := s.begin(ctx, "delete_older")
+	defer op.end(&err)
+	return s.next.DeleteOlder(ctx, t)
+}
+
+func (s instrumented) GetByID(ctx context.Context, id string) (event storagecommon.Event, err error) {
+	ctx, op := s.begin(ctx, "get_by_id")
+	defer op.end(&err)
+	return s.next.GetByID(ctx, id)
+}
+
+func (s instrumented) List(ctx context.Context) (events []storagecommon.Event, err error) {
+	ctx, op := s.begin(ctx, "list")
+	defer op.end(&err)
+	return s.next.List(ctx)
+}
+
+func (s instrumented) ListPage(
+	ctx context.Context,
+	query storagecommon.ListQuery,
+) (page storagecommon.EventPage, err error) {
+	ctx, op := s.begin(ctx, "list_page")
+	defer op.end(&err)
+	return s.next.ListPage(ctx, query)
+}
+
+func (s instrumented) ListByUser(ctx context.Context, userID string) (events []storagecommon.Event, err error) {
+	ctx, op := s.begin(ctx, "list_by_user")
+	defer op.end(&err)
+	return s.next.ListByUser(ctx, userID)
+}
+
+func (s instrumented) ListByUserInRange(
+	ctx context.Context,
+	userID string,
+	from, to time.Time,
+) (events []storagecommon.Event, err error) {
+	ctx, op := s.begin(ctx, "list_by_user_in_range")
+	defer op.end(&err)
+	return s.next.ListByUserInRange(ctx, userID, from, to)
+}
+
+func (s instrumented) ListDueOccurrences(
+	ctx context.Context,
+	from, to time.Time,
+) (events []storagecommon.Event, err error) {
+	ctx, op := s.begin(ctx, "list_due_occurrences")
+	defer op.end(&err)
+	return s.next.ListDueOccurrences(ctx, from, to)
+}
+
+func (s instrumented) AddAttendee(ctx context.Context, attendee storagecommon.Attendee) (err error) {
+	ctx, op := s.begin(ctx, "add_attendee")
+	defer op.end(&err)
+	return s.next.AddAttendee(ctx, attendee)
+}
+
+func (s instrumented) UpdateAttendee(ctx context.Context, attendee storagecommon.Attendee) (err error) {
+	ctx, op := s.begin(ctx, "update_attendee")
+	defer op.end(&err)
+	return s.next.UpdateAttendee(ctx, attendee)
+}
+
+func (s instrumented) RemoveAttendee(ctx context.Context, eventID, userID string) (err error) {
+	ctx, op := s.begin(ctx, "remove_attendee")
+	defer op.end(&err)
+	return s.next.RemoveAttendee(ctx, eventID, userID)
+}
+
+func (s instrumented) ListAttendees(
+	ctx context.Context,
+	eventID string,
+) (attendees []storagecommon.Attendee, err error) {
+	ctx, op := s.begin(ctx, "list_attendees")
+	defer op.end(&err)
+	return s.next.ListAttendees(ctx, eventID)
+}
+
+func (s instrumented) AddNotifications(
+	ctx context.Context,
+	notifications []storagecommon.Notification,
+) (added int, err error) {
+	ctx, op := s.begin(ctx, "add_notifications")
+	defer op.end(&err)
+	return s.next.AddNotifications(ctx, notifications)
+}
+
+func (s instrumented) GetNotification(
+	ctx context.Context,
+	id string,
+) (notification storagecommon.Notification, err error) {
+	ctx, op := s.begin(ctx, "get_notification")
+	defer op.end(&err)
+	return s.next.GetNotification(ctx, id)
+}
+
+func (s instrumented) ListNotifications(
+	ctx context.Context,
+	eventID string,
+) (notifications []storagecommon.Notification, err error) {
+	ctx, op := s.begin(ctx, "list_notifications")
+	defer op.end(&err)
+	return s.next.ListNotifications(ctx, eventID)
+}
+
+func (s instrumented) ListPendingNotifications(
+	ctx context.Context,
+	resendBefore time.Time,
+	limit int,
+) (notifications []storagecommon.Notification, err error) {
+	ctx, op := s.begin(ctx, "list_pending_notifications")
+	defer op.end(&err)
+	return s.next.ListPendingNotifications(ctx, resendBefore, limit)
+}
+
+func (s instrumented) SetNotificationState(ctx context.Context, id, state string, at time.Time) (err error) {
+	ctx, op := s.begin(ctx, "set_notification_state")
+	defer op.end(&err)
+	return s.next.SetNotificationState(ctx, id, state, at)
+}
+
+func (s instrumented) AddNotificationStatus(ctx context.Context, status storagecommon.NotificationStatus) (err error) {
+	ctx, op := s.begin(ctx, "add_notification_status")
+	defer op.end(&err)
+	return s.next.AddNotificationStatus(ctx, status)
+}
+
+func (s instrumented) ListNotificationStatuses(
+	ctx context.Context,
+	eventID string,
+) (statuses []storagecommon.NotificationStatus, err error) {
+	ctx, op := s.begin(ctx, "list_notification_statuses")
+	defer op.end(&err)
+	return s.next.ListNotificationStatuses(ctx, eventID)
+}
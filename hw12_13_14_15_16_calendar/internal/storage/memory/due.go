@@ -0,0 +1,81 @@
+package memorystorage
+
+import (
+	"sort"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
+)
+
+// dueIndex orders single events by the time of their reminder. Series are kept aside with the reminder of
+// their last occurrence, since the reminder of their first occurrence says nothing about the later ones.
+type dueIndex struct {
+	entries []dueEntry
+	series  map[string]seriesEntry
+}
+
+// seriesEntry bounds the reminders of a series; ends is false for open-ended series.
+type seriesEntry struct {
+	lastNotifyAt time.Time
+	ends         bool
+}
+
+type dueEntry struct {
+	notifyAt time.Time
+	eventID  string
+}
+
+func newDueIndex() dueIndex {
+	return dueIndex{series: make(map[string]seriesEntry)}
+}
+
+func (d *dueIndex) add(event storagecommon.Event) {
+	if event.IsRecurring() {
+		lastNotifyAt, ends := event.LastNotifyTime()
+		d.series[event.ID] = seriesEntry{lastNotifyAt: lastNotifyAt, ends: ends}
+		return
+	}
+	entry := dueEntry{notifyAt: event.NotifyTime(), eventID: event.ID}
+	k := d.search(entry)
+	d.entries = append(d.entries, dueEntry{})
+	copy(d.entries[k+1:], d.entries[k:])
+	d.entries[k] = entry
+}
+
+func (d *dueIndex) remove(event storagecommon.Event) {
+	if event.IsRecurring() {
+		delete(d.series, event.ID)
+		return
+	}
+	entry := dueEntry{notifyAt: event.NotifyTime(), eventID: event.ID}
+	k := d.search(entry)
+	if k < len(d.entries) && d.entries[k].eventID == event.ID && d.entries[k].notifyAt.Equal(entry.notifyAt) {
+		d.entries = append(d.entries[:k], d.entries[k+1:]...)
+	}
+}
+
+// search returns the position of the entry, or where it belongs.
+func (d *dueIndex) search(entry dueEntry) int {
+	return sort.Search(len(d.entries), func(k int) bool {
+		e := d.entries[k]
+		if !e.notifyAt.Equal(entry.notifyAt) {
+			return e.notifyAt.After(entry.notifyAt)
+		}
+		return e.eventID >= entry.eventID
+	})
+}
+
+// candidates returns the IDs of the single events reminded about in [from, to) and of the series that
+// have not ended before from.
+func (d *dueIndex) candidates(from, to time.Time) []string {
+	ids := make([]string, 0, len(d.series))
+	for k := d.search(dueEntry{notifyAt: from}); k < len(d.entries) && d.entries[k].notifyAt.Before(to); k++ {
+		ids = append(ids, d.entries[k].eventID)
+	}
+	for id, series := range d.series {
+		if !series.ends || !series.lastNotifyAt.Before(from) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
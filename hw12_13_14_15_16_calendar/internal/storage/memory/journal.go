@@ -0,0 +1,255 @@
+package memorystorage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
+)
+
+const (
+	walFile      = "wal.log"
+	snapshotFile = "snapshot.json"
+
+	defaultSnapshotEvery = 1000
+)
+
+const (
+	opPutEvent       = "put_event"
+	opDeleteEvent    = "delete_event"
+	opPutAttendee    = "put_attendee"
+	opDeleteAttendee = "delete_attendee"
+
+	opPutNotification = "put_notification"
+	opAddStatus       = "add_status"
+)
+
+// record is a single mutation in the write-ahead log. Records carry the resulting state rather than
+// the request, and a status report that is already recorded is skipped, so replaying one that is
+// already reflected in the snapshot is harmless.
+type record struct {
+	Op           string                            `json:"op"`
+	Event        *storagecommon.Event              `json:"event,omitempty"`
+	EventID      string                            `json:"eventId,omitempty"`
+	UserID       string                            `json:"userId,omitempty"`
+	Status       string                            `json:"status,omitempty"`
+	Notification *storagecommon.Notification       `json:"notification,omitempty"`
+	Report       *storagecommon.NotificationStatus `json:"report,omitempty"`
+}
+
+func putEvent(event storagecommon.Event) record {
+	return record{Op: opPutEvent, Event: &event}
+}
+
+func deleteEvent(id string) record {
+	return record{Op: opDeleteEvent, EventID: id}
+}
+
+func putAttendee(eventID, userID, status string) record {
+	return record{Op: opPutAttendee, EventID: eventID, UserID: userID, Status: status}
+}
+
+func deleteAttendee(eventID, userID string) record {
+	return record{Op: opDeleteAttendee, EventID: eventID, UserID: userID}
+}
+
+func putNotification(notification storagecommon.Notification) record {
+	return record{Op: opPutNotification, Notification: &notification}
+}
+
+func addStatus(status storagecommon.NotificationStatus) record {
+	return record{Op: opAddStatus, Report: &status}
+}
+
+func (r record) valid() bool {
+	switch r.Op {
+	case opPutEvent:
+		return r.Event != nil
+	case opDeleteEvent:
+		return r.EventID != ""
+	case opPutAttendee, opDeleteAttendee:
+		return r.EventID != "" && r.UserID != ""
+	case opPutNotification:
+		return r.Notification != nil
+	case opAddStatus:
+		return r.Report != nil
+	default:
+		return false
+	}
+}
+
+type snapshot struct {
+	Events        []storagecommon.Event              `json:"events"`
+	Attendees     map[string]map[string]string       `json:"attendees"`
+	Notifications []storagecommon.Notification       `json:"notifications,omitempty"`
+	Statuses      []storagecommon.NotificationStatus `json:"statuses,omitempty"`
+}
+
+// journal is the on-disk state of a durable storage: a snapshot and the log of mutations made after it.
+type journal struct {
+	dir           string
+	file          *os.File
+	size          int64
+	records       int
+	snapshotEvery int
+}
+
+// append writes the records to the log and waits until they reach the disk.
+func (j *journal) append(records []record) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, rec := range records {
+		if err := enc.Encode(rec); err != nil {
+			return fmt.Errorf("failed to encode log record: %w", err)
+		}
+	}
+
+	if _, err := j.file.Write(buf.Bytes()); err != nil {
+		_ = j.file.Truncate(j.size)
+		return fmt.Errorf("failed to write log: %w", err)
+	}
+	if err := j.file.Sync(); err != nil {
+		_ = j.file.Truncate(j.size)
+		return fmt.Errorf("failed to sync log: %w", err)
+	}
+
+	j.size += int64(buf.Len())
+	j.records += len(records)
+	return nil
+}
+
+func (j *journal) needsSnapshot() bool {
+	return j.records >= j.snapshotEvery
+}
+
+// writeSnapshot atomically replaces the snapshot and empties the log. A crash between the two steps
+// leaves records that are already in the snapshot, which replay applies again without effect.
+func (j *journal) writeSnapshot(state snapshot) error {
+	tmp := filepath.Join(j.dir, snapshotFile+".tmp")
+	if err := writeFileSync(tmp, state); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(j.dir, snapshotFile)); err != nil {
+		return fmt.Errorf("failed to replace snapshot: %w", err)
+	}
+	if err := syncDir(j.dir); err != nil {
+		return err
+	}
+
+	if err := j.file.Truncate(0); err != nil {
+		return fmt.Errorf("failed to truncate log: %w", err)
+	}
+	if err := j.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync log: %w", err)
+	}
+	j.size = 0
+	j.records = 0
+	return nil
+}
+
+func (j *journal) close() error {
+	return j.file.Close()
+}
+
+// openJournal loads the snapshot from dir and returns it together with the records logged after it.
+// An incomplete last record, left by a crash in the middle of a write, is dropped.
+func openJournal(dir string, snapshotEvery int) (*journal, snapshot, []record, error) {
+	if snapshotEvery <= 0 {
+		snapshotEvery = defaultSnapshotEvery
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, snapshot{}, nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	var state snapshot
+	raw, err := os.ReadFile(filepath.Join(dir, snapshotFile))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, snapshot{}, nil, fmt.Errorf("failed to read snapshot: %w", err)
+	default:
+		if err := json.Unmarshal(raw, &state); err != nil {
+			return nil, snapshot{}, nil, fmt.Errorf("failed to decode snapshot: %w", err)
+		}
+	}
+
+	file, err := os.OpenFile(filepath.Join(dir, walFile), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, snapshot{}, nil, fmt.Errorf("failed to open log: %w", err)
+	}
+
+	records, size, err := readLog(file)
+	if err == nil {
+		err = file.Truncate(size)
+	}
+	if err != nil {
+		_ = file.Close()
+		return nil, snapshot{}, nil, err
+	}
+
+	j := &journal{dir: dir, file: file, size: size, records: len(records), snapshotEvery: snapshotEvery}
+	return j, state, records, nil
+}
+
+// readLog decodes the log and returns the size of its complete part.
+func readLog(r io.Reader) ([]record, int64, error) {
+	var (
+		records []record
+		size    int64
+	)
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return records, size, nil
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read log: %w", err)
+		}
+
+		var rec record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return nil, 0, fmt.Errorf("corrupt log record at offset %d: %w", size, err)
+		}
+		if !rec.valid() {
+			return nil, 0, fmt.Errorf("corrupt log record at offset %d: invalid %q record", size, rec.Op)
+		}
+		records = append(records, rec)
+		size += int64(len(line))
+	}
+}
+
+func writeFileSync(name string, v any) error {
+	file, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	if err := json.NewEncoder(file).Encode(v); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to sync snapshot: %w", err)
+	}
+	return file.Close()
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("failed to open data directory: %w", err)
+	}
+	defer d.Close()
+
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("failed to sync data directory: %w", err)
+	}
+	return nil
+}
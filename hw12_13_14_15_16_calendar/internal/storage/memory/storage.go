@@ -1,45 +1,141 @@
 package memorystorage
 
 import (
+	"context"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
+	"github.com/google/uuid" //nolint:depguard
 )
 
+// Config enables persistence: mutations are appended to a write-ahead log in DataDir and compacted
+// into a snapshot every SnapshotEvery records (1000 by default).
+type Config struct {
+	DataDir       string
+	SnapshotEvery int
+}
+
 type Storage struct {
-	events map[string]storagecommon.Event
-	mu     sync.RWMutex
+	events        map[string]storagecommon.Event
+	attendees     map[string]map[string]string
+	notifications map[string]storagecommon.Notification
+	scheduled     map[occurrenceKey]string
+	statuses      map[string][]storagecommon.NotificationStatus
+	due           dueIndex
+	journal       *journal
+	mu            sync.RWMutex
+}
+
+// occurrenceKey identifies the notification about an occurrence of an event.
+type occurrenceKey struct {
+	eventID    string
+	occurrence int64
 }
 
+func keyOf(n storagecommon.Notification) occurrenceKey {
+	return occurrenceKey{eventID: n.EventID, occurrence: n.Occurrence.UnixNano()}
+}
+
+// New returns a storage that keeps events only in memory.
 func New() *Storage {
 	return &Storage{
-		events: make(map[string]storagecommon.Event),
+		events:        make(map[string]storagecommon.Event),
+		attendees:     make(map[string]map[string]string),
+		notifications: make(map[string]storagecommon.Notification),
+		scheduled:     make(map[occurrenceKey]string),
+		statuses:      make(map[string][]storagecommon.NotificationStatus),
+		due:           newDueIndex(),
+	}
+}
+
+// Open returns a durable storage restored from the snapshot and log in cfg.DataDir.
+// The directory must not be shared with another storage.
+func Open(cfg Config) (*Storage, error) {
+	j, state, records, err := openJournal(cfg.DataDir, cfg.SnapshotEvery)
+	if err != nil {
+		return nil, err
+	}
+
+	s := New()
+	for _, event := range state.Events {
+		s.apply(putEvent(event))
+	}
+	for eventID, attendees := range state.Attendees {
+		s.attendees[eventID] = attendees
 	}
+	for _, notification := range state.Notifications {
+		s.apply(putNotification(notification))
+	}
+	for _, status := range state.Statuses {
+		s.apply(addStatus(status))
+	}
+	for _, rec := range records {
+		s.apply(rec)
+	}
+	s.journal = j
+	return s, nil
 }
 
-func (s *Storage) Create(event storagecommon.Event) (string, error) {
+// Close writes a snapshot and releases the log; it is a no-op for a storage without persistence.
+func (s *Storage) Close(_ context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, exists := s.events[event.ID]; exists {
-		return "", storagecommon.ErrAlreadyExists
+	if s.journal == nil {
+		return nil
+	}
+	err := s.journal.writeSnapshot(s.snapshot())
+	if closeErr := s.journal.close(); err == nil {
+		err = closeErr
+	}
+	s.journal = nil
+	return err
+}
+
+func (s *Storage) Create(ctx context.Context, event storagecommon.Event) (string, error) {
+	if err := event.ValidateRecurrence(); err != nil {
+		return "", err
+	}
+	event, err := event.Normalize()
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return "", err
 	}
 
 	for _, e := range s.events {
-		if e.UserID == event.UserID && isOverlapping(e, event) {
-			return "", storagecommon.ErrConflictOverlap
+		if e.IsDuplicateOf(event) {
+			return "", storagecommon.ErrAlreadyExists
 		}
 	}
 
-	s.events[event.ID] = event
+	event.ID = uuid.NewString()
+	if s.overlapsCalendar(event, event.UserID) {
+		return "", storagecommon.ErrConflictOverlap
+	}
+
+	event.Version = storagecommon.InitialVersion
+	if err := s.commit(putEvent(event)); err != nil {
+		return "", err
+	}
 	return event.ID, nil
 }
 
-func (s *Storage) GetByID(id string) (storagecommon.Event, error) {
+func (s *Storage) GetByID(ctx context.Context, id string) (storagecommon.Event, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	if err := ctx.Err(); err != nil {
+		return storagecommon.Event{}, err
+	}
+
 	event, ok := s.events[id]
 	if !ok {
 		return storagecommon.Event{}, storagecommon.ErrEventNotFound
@@ -47,87 +143,561 @@ func (s *Storage) GetByID(id string) (storagecommon.Event, error) {
 	return event, nil
 }
 
-func (s *Storage) Update(event storagecommon.Event) error {
+func (s *Storage) Update(ctx context.Context, event storagecommon.Event) (int64, error) {
+	if err := event.ValidateRecurrence(); err != nil {
+		return 0, err
+	}
+	event, err := event.Normalize()
+	if err != nil {
+		return 0, err
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	_, exist := s.events[event.ID]
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	existing, exist := s.events[event.ID]
 	if !exist {
-		return storagecommon.ErrEventNotFound
+		return 0, storagecommon.ErrEventNotFound
+	}
+	if !existing.MatchesVersion(event.Version) {
+		return 0, storagecommon.ErrVersionConflict
 	}
 
-	for id, e := range s.events {
-		if id != event.ID && e.UserID == event.UserID && isOverlapping(e, event) {
-			return storagecommon.ErrConflictOverlap
-		}
+	if s.overlapsCalendar(event, event.UserID) {
+		return 0, storagecommon.ErrConflictOverlap
 	}
 
-	s.events[event.ID] = event
-	return nil
+	event.Version = existing.Version + 1
+	if err := s.commit(putEvent(event)); err != nil {
+		return 0, err
+	}
+	return event.Version, nil
 }
 
-func (s *Storage) Delete(id string) error {
+func (s *Storage) Delete(ctx context.Context, id string, version int64) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, exists := s.events[id]; !exists {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	existing, exists := s.events[id]
+	if !exists {
 		return storagecommon.ErrEventNotFound
 	}
+	if !existing.MatchesVersion(version) {
+		return storagecommon.ErrVersionConflict
+	}
 
-	delete(s.events, id)
-	return nil
+	return s.commit(deleteEvent(id))
 }
 
-func (s *Storage) DeleteOlder(t time.Time) error {
+func (s *Storage) DeleteOlder(ctx context.Context, t time.Time) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var records []record
 	for id, event := range s.events {
-		if event.EndTime.Before(t) {
-			delete(s.events, id)
+		if end, ok := event.SeriesEnd(); ok && end.Before(t) {
+			records = append(records, deleteEvent(id))
 		}
 	}
 
-	return nil
+	return s.commit(records...)
 }
 
-func (s *Storage) List() ([]storagecommon.Event, error) {
+func (s *Storage) List(ctx context.Context) ([]storagecommon.Event, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	result := make([]storagecommon.Event, 0, len(s.events))
 	for _, v := range s.events {
 		result = append(result, v)
 	}
+	sort.Slice(result, func(i, j int) bool { return storagecommon.Less(result[i], result[j]) })
 	return result, nil
 }
 
-func (s *Storage) ListByUser(userID string) ([]storagecommon.Event, error) {
+func (s *Storage) ListPage(ctx context.Context, query storagecommon.ListQuery) (storagecommon.EventPage, error) {
+	cursor, hasCursor, err := query.Cursor()
+	if err != nil {
+		return storagecommon.EventPage{}, err
+	}
+
+	s.mu.RLock()
+	if err := ctx.Err(); err != nil {
+		s.mu.RUnlock()
+		return storagecommon.EventPage{}, err
+	}
+	matched := make([]storagecommon.Event, 0)
+	for _, event := range s.events {
+		if (!hasCursor || cursor.After(event)) && query.Matches(event) {
+			matched = append(matched, event)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool { return storagecommon.Less(matched[i], matched[j]) })
+
+	page := storagecommon.EventPage{Events: matched}
+	if limit := query.Limit(); len(matched) > limit {
+		page.Events = matched[:limit]
+		page.NextPageToken = storagecommon.PageToken(page.Events[limit-1])
+	}
+	return page, nil
+}
+
+func (s *Storage) ListByUser(ctx context.Context, userID string) ([]storagecommon.Event, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	result := make([]storagecommon.Event, 0)
 	for _, event := range s.events {
-		if event.UserID == userID {
+		if s.isInvolved(event, userID) {
 			result = append(result, event)
 		}
 	}
 	return result, nil
 }
 
-func (s *Storage) ListByUserInRange(userID string, from, to time.Time) ([]storagecommon.Event, error) {
+func (s *Storage) ListByUserInRange(
+	ctx context.Context,
+	userID string,
+	from, to time.Time,
+) ([]storagecommon.Event, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	result := make([]storagecommon.Event, 0)
 	for _, event := range s.events {
-		if event.UserID == userID && !event.EndTime.Before(from) && !event.StartTime.After(to) {
-			result = append(result, event)
+		if s.isInvolved(event, userID) {
+			result = append(result, event.OccurrencesWithin(from, to)...)
+		}
+	}
+	return result, nil
+}
+
+func (s *Storage) ListDueOccurrences(ctx context.Context, from, to time.Time) ([]storagecommon.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]storagecommon.Event, 0)
+	for _, id := range s.due.candidates(from, to) {
+		result = append(result, s.events[id].DueOccurrences(from, to)...)
+	}
+	storagecommon.SortDue(result)
+	return result, nil
+}
+
+func (s *Storage) AddAttendee(ctx context.Context, attendee storagecommon.Attendee) error {
+	if attendee.Status == "" {
+		attendee.Status = storagecommon.AttendeeNeedsAction
+	}
+	if attendee.UserID == "" || !storagecommon.ValidAttendeeStatus(attendee.Status) {
+		return storagecommon.ErrInvalidAttendee
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	event, ok := s.events[attendee.EventID]
+	if !ok {
+		return storagecommon.ErrEventNotFound
+	}
+	if event.UserID == attendee.UserID {
+		return storagecommon.ErrInvalidAttendee
+	}
+	if _, exists := s.attendees[event.ID][attendee.UserID]; exists {
+		return storagecommon.ErrAttendeeExists
+	}
+	if attendee.Status == storagecommon.AttendeeAccepted && s.overlapsCalendar(event, attendee.UserID) {
+		return storagecommon.ErrConflictOverlap
+	}
+
+	return s.commit(putAttendee(event.ID, attendee.UserID, attendee.Status))
+}
+
+func (s *Storage) UpdateAttendee(ctx context.Context, attendee storagecommon.Attendee) error {
+	if !storagecommon.ValidAttendeeStatus(attendee.Status) {
+		return storagecommon.ErrInvalidAttendee
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	event, ok := s.events[attendee.EventID]
+	if !ok {
+		return storagecommon.ErrEventNotFound
+	}
+	status, exists := s.attendees[event.ID][attendee.UserID]
+	if !exists {
+		return storagecommon.ErrAttendeeNotFound
+	}
+	if attendee.Status == storagecommon.AttendeeAccepted && status != storagecommon.AttendeeAccepted &&
+		s.overlapsCalendar(event, attendee.UserID) {
+		return storagecommon.ErrConflictOverlap
+	}
+
+	return s.commit(putAttendee(event.ID, attendee.UserID, attendee.Status))
+}
+
+func (s *Storage) RemoveAttendee(ctx context.Context, eventID, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if _, exists := s.attendees[eventID][userID]; !exists {
+		return storagecommon.ErrAttendeeNotFound
+	}
+	return s.commit(deleteAttendee(eventID, userID))
+}
+
+func (s *Storage) ListAttendees(ctx context.Context, eventID string) ([]storagecommon.Attendee, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, ok := s.events[eventID]; !ok {
+		return nil, storagecommon.ErrEventNotFound
+	}
+
+	result := make([]storagecommon.Attendee, 0, len(s.attendees[eventID]))
+	for userID, status := range s.attendees[eventID] {
+		result = append(result, storagecommon.Attendee{EventID: eventID, UserID: userID, Status: status})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].UserID < result[j].UserID })
+	return result, nil
+}
+
+func (s *Storage) AddNotifications(ctx context.Context, notifications []storagecommon.Notification) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	records := make([]record, 0, len(notifications))
+	added := make(map[occurrenceKey]bool, len(notifications))
+	for _, notification := range notifications {
+		key := keyOf(notification)
+		if _, ok := s.events[notification.EventID]; !ok {
+			continue
+		}
+		if _, exists := s.scheduled[key]; exists || added[key] {
+			continue
+		}
+		added[key] = true
+
+		notification.ID = uuid.NewString()
+		notification.State = storagecommon.NotificationPending
+		records = append(records, putNotification(notification))
+	}
+
+	if err := s.commit(records...); err != nil {
+		return 0, err
+	}
+	return len(records), nil
+}
+
+func (s *Storage) GetNotification(ctx context.Context, id string) (storagecommon.Notification, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return storagecommon.Notification{}, err
+	}
+
+	notification, ok := s.notifications[id]
+	if !ok {
+		return storagecommon.Notification{}, storagecommon.ErrNotificationNotFound
+	}
+	return notification, nil
+}
+
+func (s *Storage) ListNotifications(ctx context.Context, eventID string) ([]storagecommon.Notification, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, ok := s.events[eventID]; !ok {
+		return nil, storagecommon.ErrEventNotFound
+	}
+
+	result := make([]storagecommon.Notification, 0)
+	for _, notification := range s.notifications {
+		if notification.EventID == eventID {
+			result = append(result, notification)
 		}
 	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Occurrence.Before(result[j].Occurrence) })
+	return result, nil
+}
+
+func (s *Storage) ListPendingNotifications(
+	ctx context.Context,
+	resendBefore time.Time,
+	limit int,
+) ([]storagecommon.Notification, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]storagecommon.Notification, 0)
+	for _, notification := range s.notifications {
+		unconfirmed := notification.State == storagecommon.NotificationPublished &&
+			notification.UpdatedAt.Before(resendBefore)
+		if notification.State == storagecommon.NotificationPending || unconfirmed {
+			result = append(result, notification)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].NotifyAt.Equal(result[j].NotifyAt) {
+			return result[i].NotifyAt.Before(result[j].NotifyAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (s *Storage) SetNotificationState(ctx context.Context, id, state string, at time.Time) error {
+	rank := storagecommon.NotificationStateRank(state)
+	if rank == 0 {
+		return storagecommon.ErrInvalidNotification
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	notification, ok := s.notifications[id]
+	if !ok {
+		return storagecommon.ErrNotificationNotFound
+	}
+	if rank < storagecommon.NotificationStateRank(notification.State) {
+		return nil
+	}
+
+	notification.State = state
+	notification.UpdatedAt = at
+	return s.commit(putNotification(notification))
+}
+
+func (s *Storage) AddNotificationStatus(ctx context.Context, status storagecommon.NotificationStatus) error {
+	if status.Status == "" {
+		return storagecommon.ErrInvalidNotification
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	notification, ok := s.notifications[status.NotificationID]
+	if !ok {
+		return storagecommon.ErrNotificationNotFound
+	}
+	status.EventID = notification.EventID
+	status.UserID = notification.UserID
+	if s.hasStatus(status) {
+		return nil
+	}
+
+	records := []record{addStatus(status)}
+	if status.Status == storagecommon.NotificationDelivered {
+		notification.State = storagecommon.NotificationDelivered
+		notification.UpdatedAt = status.ReportedAt
+		records = append(records, putNotification(notification))
+	}
+	return s.commit(records...)
+}
+
+func (s *Storage) ListNotificationStatuses(
+	ctx context.Context,
+	eventID string,
+) ([]storagecommon.NotificationStatus, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, ok := s.events[eventID]; !ok {
+		return nil, storagecommon.ErrEventNotFound
+	}
+
+	result := append([]storagecommon.NotificationStatus{}, s.statuses[eventID]...)
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].ReportedAt.Equal(result[j].ReportedAt) {
+			return result[i].ReportedAt.Before(result[j].ReportedAt)
+		}
+		return result[i].NotificationID < result[j].NotificationID
+	})
 	return result, nil
 }
 
-func isOverlapping(a, b storagecommon.Event) bool {
-	return a.StartTime.Before(b.EndTime) && b.StartTime.Before(a.EndTime)
+// commit logs the records when the storage is durable and applies them. The caller holds the write lock.
+// A failed compaction is not reported: the log still holds the records and it is retried on the next commit.
+func (s *Storage) commit(records ...record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	if s.journal != nil {
+		if err := s.journal.append(records); err != nil {
+			return err
+		}
+	}
+
+	for _, rec := range records {
+		s.apply(rec)
+	}
+
+	if s.journal != nil && s.journal.needsSnapshot() {
+		_ = s.journal.writeSnapshot(s.snapshot())
+	}
+	return nil
+}
+
+func (s *Storage) apply(rec record) {
+	switch rec.Op {
+	case opPutEvent:
+		if old, ok := s.events[rec.Event.ID]; ok {
+			s.due.remove(old)
+		}
+		s.events[rec.Event.ID] = *rec.Event
+		s.due.add(*rec.Event)
+	case opDeleteEvent:
+		if old, ok := s.events[rec.EventID]; ok {
+			s.due.remove(old)
+		}
+		delete(s.events, rec.EventID)
+		delete(s.attendees, rec.EventID)
+		for id, notification := range s.notifications {
+			if notification.EventID == rec.EventID {
+				delete(s.notifications, id)
+				delete(s.scheduled, keyOf(notification))
+			}
+		}
+		delete(s.statuses, rec.EventID)
+	case opPutAttendee:
+		if s.attendees[rec.EventID] == nil {
+			s.attendees[rec.EventID] = make(map[string]string)
+		}
+		s.attendees[rec.EventID][rec.UserID] = rec.Status
+	case opDeleteAttendee:
+		delete(s.attendees[rec.EventID], rec.UserID)
+	case opPutNotification:
+		s.notifications[rec.Notification.ID] = *rec.Notification
+		s.scheduled[keyOf(*rec.Notification)] = rec.Notification.ID
+	case opAddStatus:
+		if !s.hasStatus(*rec.Report) {
+			s.statuses[rec.Report.EventID] = append(s.statuses[rec.Report.EventID], *rec.Report)
+		}
+	}
+}
+
+// hasStatus reports whether the same report is already recorded; a status has no identity of its own,
+// so the notification, the status and the time it was reported at stand for one.
+func (s *Storage) hasStatus(status storagecommon.NotificationStatus) bool {
+	for _, recorded := range s.statuses[status.EventID] {
+		if recorded.NotificationID == status.NotificationID && recorded.Status == status.Status &&
+			recorded.ReportedAt.Equal(status.ReportedAt) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Storage) snapshot() snapshot {
+	state := snapshot{
+		Events:    make([]storagecommon.Event, 0, len(s.events)),
+		Attendees: s.attendees,
+	}
+	for _, event := range s.events {
+		state.Events = append(state.Events, event)
+	}
+	for _, notification := range s.notifications {
+		state.Notifications = append(state.Notifications, notification)
+	}
+	for _, statuses := range s.statuses {
+		state.Statuses = append(state.Statuses, statuses...)
+	}
+	return state
+}
+
+// overlapsCalendar reports whether the event overlaps another event the user owns or has accepted.
+func (s *Storage) overlapsCalendar(event storagecommon.Event, userID string) bool {
+	for id, e := range s.events {
+		if id == event.ID {
+			continue
+		}
+		busy := e.UserID == userID || s.attendees[id][userID] == storagecommon.AttendeeAccepted
+		if busy && storagecommon.Overlaps(e, event) {
+			return true
+		}
+	}
+	return false
+}
+
+// isInvolved reports whether the event belongs to the user or the user is invited and has not declined.
+func (s *Storage) isInvolved(event storagecommon.Event, userID string) bool {
+	if event.UserID == userID {
+		return true
+	}
+	status, invited := s.attendees[event.ID][userID]
+	return invited && status != storagecommon.AttendeeDeclined
 }
@@ -1,601 +1,236 @@
 package memorystorage
 
 import (
-	"sort"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	i "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/interface"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
-	"github.com/stretchr/testify/assert"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/storagetest"
 	"github.com/stretchr/testify/require"
 )
 
-func TestStorage_Create(t *testing.T) {
-	now := time.Now()
-
-	event := storagecommon.Event{
-		ID:          "1",
-		Title:       "Meeting",
-		StartTime:   now,
-		EndTime:     now.Add(time.Hour),
-		Description: "Discuss project",
-		UserID:      "user1",
-	}
-
-	tests := []struct {
-		name    string
-		input   storagecommon.Event
-		setup   func() i.Storage
-		wantErr error
-	}{
-		{
-			name:  "success create new event",
-			input: event,
-			setup: func() i.Storage {
-				return New()
-			},
-			wantErr: nil,
-		},
-		{
-			name:  "fail event already exists",
-			input: event,
-			setup: func() i.Storage {
-				s := New()
-				_, _ = s.Create(event)
-				return s
-			},
-			wantErr: storagecommon.ErrAlreadyExists,
-		},
-		{
-			name: "fail time overlap",
-			input: storagecommon.Event{
-				ID:        "2",
-				Title:     "Another Meeting",
-				StartTime: now.Add(30 * time.Minute),
-				EndTime:   now.Add(time.Hour + 30*time.Minute),
-				UserID:    "user1",
-			},
-			setup: func() i.Storage {
-				s := New()
-				_, _ = s.Create(event)
-				return s
-			},
-			wantErr: storagecommon.ErrConflictOverlap,
-		},
-		{
-			name: "success different user same time",
-			input: storagecommon.Event{
-				ID:        "2",
-				Title:     "Another Meeting",
-				StartTime: now.Add(30 * time.Minute),
-				EndTime:   now.Add(time.Hour + 30*time.Minute),
-				UserID:    "user2",
-			},
-			setup: func() i.Storage {
-				s := New()
-				_, _ = s.Create(event)
-				return s
-			},
-			wantErr: nil,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			s := tt.setup()
-			_, err := s.Create(tt.input)
+func TestStorage_Conformance(t *testing.T) {
+	storagetest.Run(t, func(*testing.T) i.Storage {
+		return New()
+	})
+}
 
-			if tt.wantErr != nil {
-				require.Error(t, err)
-				require.ErrorIs(t, err, tt.wantErr)
-			} else {
-				require.NoError(t, err)
-				got, err := s.GetByID(tt.input.ID)
-				require.NoError(t, err)
-				require.Equal(t, tt.input, got)
-			}
+func TestStorage_DurableConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) i.Storage {
+		storage, err := Open(Config{DataDir: t.TempDir(), SnapshotEvery: 3})
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, storage.Close(context.Background()))
 		})
-	}
+		return storage
+	})
 }
 
-func TestStorage_Update(t *testing.T) {
-	now := time.Now()
+func TestStorage_Persistence(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
 
-	baseEvent := storagecommon.Event{
-		ID:          "1",
-		Title:       "Meeting",
-		StartTime:   now,
-		EndTime:     now.Add(time.Hour),
-		Description: "Discuss project",
-		UserID:      "user1",
-	}
+	populate := func(t *testing.T, storage *Storage) string {
+		t.Helper()
 
-	tests := []struct {
-		name    string
-		input   storagecommon.Event
-		setup   func() i.Storage
-		wantErr error
-	}{
-		{
-			name:  "success update event",
-			input: baseEvent,
-			setup: func() i.Storage {
-				s := New()
-				_, _ = s.Create(baseEvent)
-				updated := baseEvent
-				updated.Title = "Updated Meeting"
-				updated.StartTime = now.Add(2 * time.Hour)
-				updated.EndTime = now.Add(3 * time.Hour)
-				s.events[baseEvent.ID] = updated
-				return s
-			},
-			wantErr: nil,
-		},
-		{
-			name: "fail event not found",
-			input: storagecommon.Event{
-				ID:        "2",
-				Title:     "Non-existent",
-				StartTime: now,
-				EndTime:   now.Add(time.Hour),
+		var ids []string
+		for n := 0; n < 5; n++ {
+			id, err := storage.Create(ctx, storagecommon.Event{
 				UserID:    "user1",
-			},
-			setup: func() i.Storage {
-				s := New()
-				_, _ = s.Create(baseEvent)
-				return s
-			},
-			wantErr: storagecommon.ErrEventNotFound,
-		},
-		{
-			name: "fail time overlap",
-			input: storagecommon.Event{
-				ID:        "1",
 				Title:     "Meeting",
-				StartTime: now.Add(30 * time.Minute),
-				EndTime:   now.Add(time.Hour + 30*time.Minute),
-				UserID:    "user1",
-			},
-			setup: func() i.Storage {
-				s := New()
-				_, _ = s.Create(baseEvent)
-				_, _ = s.Create(storagecommon.Event{
-					ID:        "2",
-					Title:     "Another",
-					StartTime: now.Add(time.Hour + 29*time.Minute),
-					EndTime:   now.Add(2 * time.Hour),
-					UserID:    "user1",
-				})
-				return s
-			},
-			wantErr: storagecommon.ErrConflictOverlap,
-		},
-		{
-			name: "success update with no overlap",
-			input: storagecommon.Event{
-				ID:        "1",
-				Title:     "Meeting",
-				StartTime: now.Add(30 * time.Minute),
-				EndTime:   now.Add(time.Hour + 30*time.Minute),
-				UserID:    "user1",
-			},
-			setup: func() i.Storage {
-				s := New()
-				_, _ = s.Create(baseEvent)
-				_, _ = s.Create(storagecommon.Event{
-					ID:        "2",
-					Title:     "Another",
-					StartTime: now.Add(90 * time.Minute),
-					EndTime:   now.Add(2 * time.Hour),
-					UserID:    "user1",
-				})
-				return s
-			},
-			wantErr: nil,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			s := tt.setup()
-			err := s.Update(tt.input)
+				StartTime: start.Add(time.Duration(n) * time.Hour),
+				EndTime:   start.Add(time.Duration(n)*time.Hour + 30*time.Minute),
+				TimeZone:  "Europe/Moscow",
+			})
+			require.NoError(t, err)
+			ids = append(ids, id)
+		}
 
-			if tt.wantErr != nil {
-				require.Error(t, err)
-				require.ErrorIs(t, err, tt.wantErr)
-			} else {
-				require.NoError(t, err)
-				got, err := s.GetByID(tt.input.ID)
-				require.NoError(t, err)
-				require.Equal(t, tt.input, got)
-			}
+		event, err := storage.GetByID(ctx, ids[0])
+		require.NoError(t, err)
+		event.Title = "Renamed"
+		_, err = storage.Update(ctx, event)
+		require.NoError(t, err)
+
+		require.NoError(t, storage.Delete(ctx, ids[1], 0))
+		require.NoError(t, storage.AddAttendee(ctx, storagecommon.Attendee{EventID: ids[2], UserID: "guest"}))
+		require.NoError(t, storage.AddAttendee(ctx, storagecommon.Attendee{EventID: ids[3], UserID: "guest"}))
+		require.NoError(t, storage.UpdateAttendee(ctx, storagecommon.Attendee{
+			EventID: ids[2], UserID: "guest", Status: storagecommon.AttendeeAccepted,
+		}))
+		require.NoError(t, storage.RemoveAttendee(ctx, ids[3], "guest"))
+		require.NoError(t, storage.DeleteOlder(ctx, start.Add(4*time.Hour)))
+
+		event, err = storage.GetByID(ctx, ids[4])
+		require.NoError(t, err)
+		added, err := storage.AddNotifications(ctx, []storagecommon.Notification{
+			storagecommon.NewNotification(event, start),
 		})
-	}
-}
-
-func TestStorage_Delete(t *testing.T) {
-	now := time.Now()
-
-	event := storagecommon.Event{
-		ID:          "1",
-		Title:       "Meeting",
-		StartTime:   now,
-		EndTime:     now.Add(time.Hour),
-		Description: "Discuss project",
-		UserID:      "user1",
+		require.NoError(t, err)
+		require.Equal(t, 1, added)
+		pending, err := storage.ListPendingNotifications(ctx, time.Time{}, 0)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		require.NoError(t, storage.SetNotificationState(ctx, pending[0].ID, storagecommon.NotificationPublished, start))
+		require.NoError(t, storage.AddNotificationStatus(ctx, storagecommon.NotificationStatus{
+			NotificationID: pending[0].ID, Status: "failed", ReportedAt: start,
+		}))
+		return pending[0].ID
 	}
 
 	tests := []struct {
-		name    string
-		inputID string
-		setup   func() i.Storage
-		wantErr error
+		name          string
+		snapshotEvery int
+		closeBefore   bool
+		keepLog       bool
 	}{
-		{
-			name:    "success delete existing event",
-			inputID: "1",
-			setup: func() i.Storage {
-				s := New()
-				_, _ = s.Create(event)
-				return s
-			},
-			wantErr: nil,
-		},
-		{
-			name:    "fail delete nonexistent event",
-			inputID: "2",
-			setup: func() i.Storage {
-				s := New()
-				_, _ = s.Create(event)
-				return s
-			},
-			wantErr: storagecommon.ErrEventNotFound,
-		},
+		{name: "replay log after crash", snapshotEvery: 100},
+		{name: "snapshot and log after crash", snapshotEvery: 4},
+		{name: "snapshot after close", snapshotEvery: 100, closeBefore: true},
+		{name: "crash before log truncate", snapshotEvery: 100, closeBefore: true, keepLog: true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := tt.setup()
-			err := s.Delete(tt.inputID)
+			dir := t.TempDir()
+			storage, err := Open(Config{DataDir: dir, SnapshotEvery: tt.snapshotEvery})
+			require.NoError(t, err)
+			notificationID := populate(t, storage)
+			want := dump(t, storage)
 
-			if tt.wantErr != nil {
-				require.Error(t, err)
-				require.ErrorIs(t, err, tt.wantErr)
-			} else {
+			if tt.closeBefore {
+				log, err := os.ReadFile(filepath.Join(dir, walFile))
 				require.NoError(t, err)
-				_, err := s.GetByID(tt.inputID)
-				require.ErrorIs(t, err, storagecommon.ErrEventNotFound)
-			}
-		})
-	}
-}
-
-func TestStorage_DeleteOlder(t *testing.T) {
-	now := time.Now()
-
-	tests := []struct {
-		name        string
-		cutoffTime  time.Time
-		setupEvents []storagecommon.Event
-		expectedIDs []string
-	}{
-		{
-			name:       "delete past events",
-			cutoffTime: now,
-			setupEvents: []storagecommon.Event{
-				{
-					ID:          "1",
-					Title:       "Past Event",
-					StartTime:   now.Add(-2 * time.Hour),
-					EndTime:     now.Add(-1 * time.Hour),
-					Description: "Should be deleted",
-					UserID:      "user1",
-				},
-				{
-					ID:          "2",
-					Title:       "Future Event",
-					StartTime:   now.Add(1 * time.Hour),
-					EndTime:     now.Add(2 * time.Hour),
-					Description: "Should stay",
-					UserID:      "user1",
-				},
-			},
-			expectedIDs: []string{"2"},
-		},
-		{
-			name:       "no deletion if all events are newer",
-			cutoffTime: now.Add(-1 * time.Hour), // на час раньше
-			setupEvents: []storagecommon.Event{
-				{
-					ID:          "3",
-					Title:       "Event A",
-					StartTime:   now,
-					EndTime:     now.Add(1 * time.Hour),
-					Description: "Should stay",
-					UserID:      "user1",
-				},
-				{
-					ID:          "4",
-					Title:       "Event B",
-					StartTime:   now.Add(2 * time.Hour),
-					EndTime:     now.Add(3 * time.Hour),
-					Description: "Should stay",
-					UserID:      "user1",
-				},
-			},
-			expectedIDs: []string{"3", "4"},
-		},
-		{
-			name:       "all events should be deleted",
-			cutoffTime: now.Add(1 * time.Hour),
-			setupEvents: []storagecommon.Event{
-				{
-					ID:          "5",
-					Title:       "Event A",
-					StartTime:   now.Add(-3 * time.Hour),
-					EndTime:     now.Add(-2 * time.Hour),
-					Description: "Deleted",
-					UserID:      "user1",
-				},
-				{
-					ID:          "6",
-					Title:       "Event B",
-					StartTime:   now.Add(-1 * time.Hour),
-					EndTime:     now.Add(-30 * time.Minute),
-					Description: "Deleted",
-					UserID:      "user1",
-				},
-			},
-			expectedIDs: []string{},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			s := New()
+				require.NoError(t, storage.Close(ctx))
+				info, err := os.Stat(filepath.Join(dir, walFile))
+				require.NoError(t, err)
+				require.Zero(t, info.Size())
 
-			for _, event := range tt.setupEvents {
-				_, _ = s.Create(event)
+				if tt.keepLog {
+					// The snapshot already holds every record of the log, as after a crash between the two.
+					require.NoError(t, os.WriteFile(filepath.Join(dir, walFile), log, 0o600))
+				}
 			}
 
-			err := s.DeleteOlder(tt.cutoffTime)
+			restored, err := Open(Config{DataDir: dir, SnapshotEvery: tt.snapshotEvery})
 			require.NoError(t, err)
+			defer restored.Close(ctx)
+			require.Equal(t, want, dump(t, restored))
 
-			actualIDs := make([]string, 0)
-			for id := range s.events {
-				actualIDs = append(actualIDs, id)
-			}
-
-			sort.Strings(actualIDs)
-			sort.Strings(tt.expectedIDs)
+			notification, err := restored.GetNotification(ctx, notificationID)
+			require.NoError(t, err)
+			require.Equal(t, storagecommon.NotificationPublished, notification.State)
+			added, err := restored.AddNotifications(ctx, []storagecommon.Notification{notification})
+			require.NoError(t, err)
+			require.Zero(t, added)
+			statuses, err := restored.ListNotificationStatuses(ctx, notification.EventID)
+			require.NoError(t, err)
+			require.Len(t, statuses, 1)
+			require.Equal(t, "failed", statuses[0].Status)
 
-			assert.Equal(t, tt.expectedIDs, actualIDs)
+			_, err = restored.Create(ctx, storagecommon.Event{
+				UserID: "user1", Title: "Meeting", StartTime: start.Add(4 * time.Hour), EndTime: start.Add(5 * time.Hour),
+			})
+			require.ErrorIs(t, err, storagecommon.ErrConflictOverlap)
 		})
 	}
 }
 
-func TestStorage_List(t *testing.T) {
-	now := time.Now()
-
-	events := []storagecommon.Event{
-		{
-			ID:        "1",
-			Title:     "Meeting 1",
-			StartTime: now,
-			EndTime:   now.Add(time.Hour),
-			UserID:    "user1",
-		},
-		{
-			ID:        "2",
-			Title:     "Meeting 2",
-			StartTime: now.Add(2 * time.Hour),
-			EndTime:   now.Add(3 * time.Hour),
-			UserID:    "user2",
-		},
-	}
-
-	tests := []struct {
-		name    string
-		setup   func() i.Storage
-		wantLen int
-	}{
-		{
-			name: "list with events",
-			setup: func() i.Storage {
-				s := New()
-				for _, e := range events {
-					_, _ = s.Create(e)
-				}
-				return s
-			},
-			wantLen: len(events),
-		},
-		{
-			name: "empty list",
-			setup: func() i.Storage {
-				return New()
-			},
-			wantLen: 0,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			s := tt.setup()
-			list, err := s.List()
-			require.NoError(t, err)
-			require.Len(t, list, tt.wantLen)
-		})
-	}
+func TestStorage_TornLog(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	storage, err := Open(Config{DataDir: dir})
+	require.NoError(t, err)
+	id, err := storage.Create(ctx, storagecommon.Event{
+		UserID: "user1", Title: "Kept", StartTime: now, EndTime: now.Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	log, err := os.OpenFile(filepath.Join(dir, walFile), os.O_WRONLY|os.O_APPEND, 0o600)
+	require.NoError(t, err)
+	_, err = log.WriteString(`{"op":"put_event","event":{"ID":"lost"`)
+	require.NoError(t, err)
+	require.NoError(t, log.Close())
+
+	restored, err := Open(Config{DataDir: dir})
+	require.NoError(t, err)
+	events, err := restored.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, id, events[0].ID)
+
+	_, err = restored.Create(ctx, storagecommon.Event{
+		UserID: "user1", Title: "Next", StartTime: now.Add(time.Hour), EndTime: now.Add(2 * time.Hour),
+	})
+	require.NoError(t, err)
+	require.NoError(t, restored.Close(ctx))
+
+	restored, err = Open(Config{DataDir: dir})
+	require.NoError(t, err)
+	events, err = restored.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, walFile), []byte("{\"op\":\"drop_table\"}\n"), 0o600))
+	_, err = Open(Config{DataDir: dir})
+	require.Error(t, err)
 }
 
-func TestStorage_ListByUser(t *testing.T) {
-	now := time.Now()
+// dump returns the events and attendees of the storage in a form that compares equal after a restart.
+func dump(t *testing.T, storage *Storage) string {
+	t.Helper()
 
-	events := []storagecommon.Event{
-		{
-			ID:        "1",
-			Title:     "User1 Event",
-			StartTime: now,
-			EndTime:   now.Add(time.Hour),
-			UserID:    "user1",
-		},
-		{
-			ID:        "2",
-			Title:     "User1 Another",
-			StartTime: now.Add(2 * time.Hour),
-			EndTime:   now.Add(3 * time.Hour),
-			UserID:    "user1",
-		},
-		{
-			ID:        "3",
-			Title:     "User2 Event",
-			StartTime: now,
-			EndTime:   now.Add(time.Hour),
-			UserID:    "user2",
-		},
-	}
+	events, err := storage.List(context.Background())
+	require.NoError(t, err)
 
-	tests := []struct {
-		name      string
-		userID    string
-		setup     func() i.Storage
-		wantCount int
-	}{
-		{
-			name:   "list user1 events",
-			userID: "user1",
-			setup: func() i.Storage {
-				s := New()
-				for _, e := range events {
-					_, _ = s.Create(e)
-				}
-				return s
-			},
-			wantCount: 2,
-		},
-		{
-			name:   "list user2 events",
-			userID: "user2",
-			setup: func() i.Storage {
-				s := New()
-				for _, e := range events {
-					_, _ = s.Create(e)
-				}
-				return s
-			},
-			wantCount: 1,
-		},
-		{
-			name:   "list empty",
-			userID: "user3",
-			setup: func() i.Storage {
-				s := New()
-				for _, e := range events {
-					_, _ = s.Create(e)
-				}
-				return s
-			},
-			wantCount: 0,
-		},
-	}
+	state := snapshot{Events: events, Attendees: make(map[string]map[string]string)}
+	for k, event := range events {
+		state.Events[k].StartTime = event.StartTime.UTC()
+		state.Events[k].EndTime = event.EndTime.UTC()
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			s := tt.setup()
-			list, err := s.ListByUser(tt.userID)
-			require.NoError(t, err)
-			require.Len(t, list, tt.wantCount)
-		})
+		attendees, err := storage.ListAttendees(context.Background(), event.ID)
+		require.NoError(t, err)
+		for _, attendee := range attendees {
+			if state.Attendees[event.ID] == nil {
+				state.Attendees[event.ID] = make(map[string]string)
+			}
+			state.Attendees[event.ID][attendee.UserID] = attendee.Status
+		}
 	}
+
+	raw, err := json.Marshal(state)
+	require.NoError(t, err)
+	return string(raw)
 }
 
-func TestStorage_ListByUserInRange(t *testing.T) {
+func TestStorage_ContextDone(t *testing.T) {
+	storage := New()
 	now := time.Now()
-
-	events := []storagecommon.Event{
-		{
-			ID:        "1",
-			Title:     "Morning",
-			StartTime: now,
-			EndTime:   now.Add(time.Hour),
-			UserID:    "user1",
-		},
-		{
-			ID:        "2",
-			Title:     "Noon",
-			StartTime: now.Add(2 * time.Hour),
-			EndTime:   now.Add(3 * time.Hour),
-			UserID:    "user1",
-		},
-		{
-			ID:        "3",
-			Title:     "Evening",
-			StartTime: now.Add(6 * time.Hour),
-			EndTime:   now.Add(7 * time.Hour),
-			UserID:    "user1",
-		},
-		{
-			ID:        "4",
-			Title:     "Other User",
-			StartTime: now,
-			EndTime:   now.Add(time.Hour),
-			UserID:    "user2",
-		},
-	}
-
-	tests := []struct {
-		name      string
-		userID    string
-		from      time.Time
-		to        time.Time
-		wantCount int
-	}{
-		{
-			name:      "day range with 2 events",
-			userID:    "user1",
-			from:      now,
-			to:        now.Add(5 * time.Hour),
-			wantCount: 2,
-		},
-		{
-			name:      "day range with 1 event",
-			userID:    "user1",
-			from:      now.Add(5 * time.Hour),
-			to:        now.Add(8 * time.Hour),
-			wantCount: 1,
-		},
-		{
-			name:      "no events in range",
-			userID:    "user1",
-			from:      now.Add(10 * time.Hour),
-			to:        now.Add(12 * time.Hour),
-			wantCount: 0,
-		},
-		{
-			name:      "other user event not included",
-			userID:    "user2",
-			from:      now,
-			to:        now.Add(5 * time.Hour),
-			wantCount: 1,
-		},
-	}
-
-	setup := func() i.Storage {
-		s := New()
-		for _, e := range events {
-			_, _ = s.Create(e)
-		}
-		return s
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			s := setup()
-			list, err := s.ListByUserInRange(tt.userID, tt.from, tt.to)
-			require.NoError(t, err)
-			require.Len(t, list, tt.wantCount)
-		})
-	}
+	event := storagecommon.Event{UserID: "user1", Title: "Meeting", StartTime: now, EndTime: now.Add(time.Hour)}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := storage.Create(canceled, event)
+	require.ErrorIs(t, err, context.Canceled)
+
+	id, err := storage.Create(context.Background(), event)
+	require.NoError(t, err)
+
+	expired, cancel := context.WithDeadline(context.Background(), now.Add(-time.Second))
+	defer cancel()
+	_, err = storage.GetByID(expired, id)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	_, err = storage.ListPage(expired, storagecommon.ListQuery{})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorIs(t, storage.Delete(expired, id, 0), context.DeadlineExceeded)
+
+	_, err = storage.GetByID(context.Background(), id)
+	require.NoError(t, err)
 }
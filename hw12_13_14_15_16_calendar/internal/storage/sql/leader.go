@@ -0,0 +1,89 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var errLockNotHeld = errors.New("advisory lock is not held")
+
+// AdvisoryLock is a session-level Postgres advisory lock. It keeps the connection that took the lock
+// out of the pool, so the lock is released when the session ends, however the process exits.
+type AdvisoryLock struct {
+	db  *sql.DB
+	key int64
+
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+// LeaderLock returns an advisory lock on the key; the replicas of a service must use the same key.
+func (s *Storage) LeaderLock(key int64) *AdvisoryLock {
+	return &AdvisoryLock{db: s.db.DB, key: key}
+}
+
+func (l *AdvisoryLock) TryLock(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		return true, nil
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, contextError(ctx, err)
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return false, fmt.Errorf("failed to take advisory lock: %w", contextError(ctx, err))
+	}
+	if !acquired {
+		return false, conn.Close()
+	}
+	l.conn = conn
+	return true, nil
+}
+
+// Check fails when the session that holds the lock is gone.
+func (l *AdvisoryLock) Check(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return errLockNotHeld
+	}
+	if err := l.conn.PingContext(ctx); err != nil {
+		return fmt.Errorf("advisory lock session is lost: %w", err)
+	}
+	return nil
+}
+
+// Unlock releases the lock and returns the connection to the pool; when the lock cannot be released,
+// the connection is discarded and the server releases the lock with the session.
+func (l *AdvisoryLock) Unlock(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return errLockNotHeld
+	}
+	conn := l.conn
+	l.conn = nil
+
+	_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key)
+	if err != nil {
+		// A connection that still holds the lock must not go back to the pool.
+		_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+	}
+	if closeErr := conn.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
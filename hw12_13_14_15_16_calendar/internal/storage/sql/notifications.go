@@ -0,0 +1,204 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
+	"github.com/google/uuid"  //nolint:depguard
+	"github.com/jmoiron/sqlx" //nolint:depguard
+)
+
+// stateRank mirrors storagecommon.NotificationStateRank.
+const stateRank = `CASE state WHEN 'pending' THEN 1 WHEN 'published' THEN 2 WHEN 'delivered' THEN 3 ELSE 0 END`
+
+func (s *Storage) AddNotifications(ctx context.Context, notifications []storagecommon.Notification) (int, error) {
+	var added int
+	err := s.inTx(ctx, func(tx *sqlx.Tx) error {
+		added = 0
+		for _, notification := range notifications {
+			if !isUUID(notification.EventID) {
+				continue
+			}
+
+			var exists bool
+			err := tx.GetContext(ctx, &exists, "SELECT EXISTS (SELECT 1 FROM events WHERE id = $1)", notification.EventID)
+			if err != nil {
+				return fmt.Errorf("failed to check event: %w", err)
+			}
+			if !exists {
+				continue
+			}
+
+			notification.ID = uuid.NewString()
+			notification.State = storagecommon.NotificationPending
+			res, err := tx.NamedExecContext(ctx, `
+                INSERT INTO notifications (id, event_id, user_id, title, description, occurrence, notify_at,
+                                           time_zone, all_day, state, updated_at)
+                VALUES (:id, :event_id, :user_id, :title, :description, :occurrence, :notify_at,
+                        :time_zone, :all_day, :state, :updated_at)
+                ON CONFLICT (event_id, occurrence) DO NOTHING
+            `, notification)
+			if err != nil {
+				return fmt.Errorf("failed to add notification: %w", err)
+			}
+			rowsAffected, err := res.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to get rows affected: %w", err)
+			}
+			added += int(rowsAffected)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return added, nil
+}
+
+func (s *Storage) GetNotification(ctx context.Context, id string) (storagecommon.Notification, error) {
+	if !isUUID(id) {
+		return storagecommon.Notification{}, storagecommon.ErrNotificationNotFound
+	}
+
+	var notification storagecommon.Notification
+	err := s.db.GetContext(ctx, &notification, "SELECT * FROM notifications WHERE id = $1", id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return storagecommon.Notification{}, storagecommon.ErrNotificationNotFound
+	}
+	if err != nil {
+		return storagecommon.Notification{}, fmt.Errorf("failed to get notification: %w", contextError(ctx, err))
+	}
+	return notification, nil
+}
+
+func (s *Storage) ListNotifications(ctx context.Context, eventID string) ([]storagecommon.Notification, error) {
+	if _, err := s.GetByID(ctx, eventID); err != nil {
+		return nil, err
+	}
+
+	notifications := make([]storagecommon.Notification, 0)
+	err := s.db.SelectContext(ctx, &notifications,
+		"SELECT * FROM notifications WHERE event_id = $1 ORDER BY occurrence", eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", contextError(ctx, err))
+	}
+	return notifications, nil
+}
+
+func (s *Storage) ListPendingNotifications(
+	ctx context.Context,
+	resendBefore time.Time,
+	limit int,
+) ([]storagecommon.Notification, error) {
+	query := "SELECT * FROM notifications WHERE state = $1"
+	args := []any{storagecommon.NotificationPending}
+	if !resendBefore.IsZero() {
+		query += " OR (state = $2 AND updated_at < $3)"
+		args = append(args, storagecommon.NotificationPublished, resendBefore)
+	}
+	query += " ORDER BY notify_at, id"
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", len(args)+1)
+		args = append(args, limit)
+	}
+
+	notifications := make([]storagecommon.Notification, 0)
+	if err := s.db.SelectContext(ctx, &notifications, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", contextError(ctx, err))
+	}
+	return notifications, nil
+}
+
+func (s *Storage) SetNotificationState(ctx context.Context, id, state string, at time.Time) error {
+	rank := storagecommon.NotificationStateRank(state)
+	if rank == 0 {
+		return storagecommon.ErrInvalidNotification
+	}
+	if !isUUID(id) {
+		return storagecommon.ErrNotificationNotFound
+	}
+
+	res, err := s.db.ExecContext(ctx,
+		"UPDATE notifications SET state = $2, updated_at = $3 WHERE id = $1 AND "+stateRank+" <= $4",
+		id, state, at, rank)
+	if err != nil {
+		return fmt.Errorf("failed to update notification: %w", contextError(ctx, err))
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	_, err = s.GetNotification(ctx, id)
+	return err
+}
+
+func (s *Storage) AddNotificationStatus(ctx context.Context, status storagecommon.NotificationStatus) error {
+	if status.Status == "" {
+		return storagecommon.ErrInvalidNotification
+	}
+	if !isUUID(status.NotificationID) {
+		return storagecommon.ErrNotificationNotFound
+	}
+
+	return s.inTx(ctx, func(tx *sqlx.Tx) error {
+		var notification storagecommon.Notification
+		err := tx.GetContext(ctx, &notification,
+			"SELECT * FROM notifications WHERE id = $1 FOR UPDATE", status.NotificationID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return storagecommon.ErrNotificationNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get notification: %w", err)
+		}
+
+		status.EventID = notification.EventID
+		status.UserID = notification.UserID
+		res, err := tx.NamedExecContext(ctx, `
+            INSERT INTO notification_statuses (notification_id, event_id, user_id, status, reported_at)
+            VALUES (:notification_id, :event_id, :user_id, :status, :reported_at)
+            ON CONFLICT DO NOTHING
+        `, status)
+		if err != nil {
+			return fmt.Errorf("failed to add notification status: %w", err)
+		}
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 || status.Status != storagecommon.NotificationDelivered {
+			return nil
+		}
+
+		_, err = tx.ExecContext(ctx, "UPDATE notifications SET state = $2, updated_at = $3 WHERE id = $1",
+			status.NotificationID, storagecommon.NotificationDelivered, status.ReportedAt)
+		if err != nil {
+			return fmt.Errorf("failed to update notification: %w", err)
+		}
+		return nil
+	})
+}
+
+func (s *Storage) ListNotificationStatuses(
+	ctx context.Context,
+	eventID string,
+) ([]storagecommon.NotificationStatus, error) {
+	if _, err := s.GetByID(ctx, eventID); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]storagecommon.NotificationStatus, 0)
+	err := s.db.SelectContext(ctx, &statuses,
+		"SELECT * FROM notification_statuses WHERE event_id = $1 ORDER BY reported_at, notification_id", eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification statuses: %w", contextError(ctx, err))
+	}
+	return statuses, nil
+}
@@ -5,14 +5,42 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
+	"github.com/google/uuid"      //nolint:depguard
 	"github.com/jmoiron/sqlx"     //nolint:depguard
 	_ "github.com/lib/pq"         //nolint:depguard
 	"github.com/pressly/goose/v3" //nolint:depguard
 )
 
+// Subqueries selecting the events a user ($1) takes part in as an attendee.
+const (
+	acceptedByUser = `SELECT event_id FROM attendees WHERE user_id = $1 AND status = 'accepted'`
+	invitedUser    = `SELECT event_id FROM attendees WHERE user_id = $1 AND status <> 'declined'`
+)
+
+// eventColumns lists the columns of storagecommon.Event; the generated notify_at column and last_notify_at
+// are only queried.
+const eventColumns = `id, title, start_time, end_time, description, user_id, notify_before, rrule, exdates,
+	time_zone, all_day, version`
+
+// eventRow is the event as it is written, with the reminder time of its last occurrence; that is NULL
+// for open-ended series.
+type eventRow struct {
+	storagecommon.Event
+	LastNotifyAt *time.Time `db:"last_notify_at"`
+}
+
+func rowOf(event storagecommon.Event) eventRow {
+	row := eventRow{Event: event}
+	if notifyAt, ok := event.LastNotifyTime(); ok {
+		row.LastNotifyAt = &notifyAt
+	}
+	return row
+}
+
 type Config struct {
 	StorageType    string
 	DSN            string
@@ -49,6 +77,17 @@ func (s *Storage) Connect(ctx context.Context) error {
 	return nil
 }
 
+// Ping checks that the database answers.
+func (s *Storage) Ping(ctx context.Context) error {
+	if s.db == nil {
+		return fmt.Errorf("%s is not connected", s.storageType)
+	}
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping %s: %w", s.storageType, err)
+	}
+	return nil
+}
+
 func (s *Storage) Close(_ context.Context) error {
 	if s.db != nil {
 		if err := s.db.Close(); err != nil {
@@ -72,172 +111,481 @@ func (s *Storage) Migrate() error {
 		return fmt.Errorf("failed to apply migrations: %w", err)
 	}
 
-	return nil
+	return s.fillLastNotifyAt(context.Background())
 }
 
-func (s *Storage) Create(event storagecommon.Event) (string, error) {
-	duplicate, err := s.isDuplicate(event)
-	if err != nil {
-		return "", err
+// fillLastNotifyAt fills the column for the series written before it existed.
+func (s *Storage) fillLastNotifyAt(ctx context.Context) error {
+	load := func(ctx context.Context) ([]storagecommon.Event, error) {
+		var series []storagecommon.Event
+		query := "SELECT " + eventColumns + ` FROM events
+            WHERE rrule <> '' AND last_notify_at IS NULL
+            AND (upper(rrule) LIKE '%COUNT=%' OR upper(rrule) LIKE '%UNTIL=%')`
+		err := s.db.SelectContext(ctx, &series, query)
+		return series, err
 	}
-	if duplicate {
-		return "", storagecommon.ErrAlreadyExists
+	store := func(ctx context.Context, id string, notifyAt time.Time) error {
+		_, err := s.db.ExecContext(ctx, "UPDATE events SET last_notify_at = $1 WHERE id = $2", notifyAt, id)
+		return err
 	}
+	return storagecommon.FillLastNotifyAt(ctx, load, store)
+}
 
-	overlap, err := s.isOverlapping(event)
-	if err != nil {
-		return "", fmt.Errorf("checking overlapping events: %w", err)
+func (s *Storage) Create(ctx context.Context, event storagecommon.Event) (string, error) {
+	if err := event.ValidateRecurrence(); err != nil {
+		return "", err
 	}
-	if overlap {
-		return "", storagecommon.ErrConflictOverlap
+	event, err := event.Normalize()
+	if err != nil {
+		return "", err
 	}
 
 	const query = `
 	   INSERT INTO events (
-	       user_id, title, start_time, end_time, description, notify_before
+	       user_id, title, start_time, end_time, description, notify_before, rrule, exdates,
+	       time_zone, all_day, last_notify_at
 	   ) VALUES (
-	       :user_id, :title, :start_time, :end_time, :description, :notify_before
+	       :user_id, :title, :start_time, :end_time, :description, :notify_before, :rrule, :exdates,
+	       :time_zone, :all_day, :last_notify_at
 	   )
 	   RETURNING id`
 
 	var newID string
-	namedQuery, err := s.db.PrepareNamed(query)
-	if err != nil {
-		return "", fmt.Errorf("failed to prepare named query: %w", err)
-	}
+	err = s.inTx(ctx, func(tx *sqlx.Tx) error {
+		duplicate, err := isDuplicate(ctx, tx, event)
+		if err != nil {
+			return err
+		}
+		if duplicate {
+			return storagecommon.ErrAlreadyExists
+		}
 
-	err = namedQuery.Get(&newID, event)
+		overlap, err := isOverlapping(ctx, tx, event, event.UserID)
+		if err != nil {
+			return fmt.Errorf("checking overlapping events: %w", err)
+		}
+		if overlap {
+			return storagecommon.ErrConflictOverlap
+		}
+
+		namedQuery, err := tx.PrepareNamedContext(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to prepare named query: %w", err)
+		}
+		defer namedQuery.Close()
+
+		if err := namedQuery.GetContext(ctx, &newID, rowOf(event)); err != nil {
+			return fmt.Errorf("failed to create event: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to create event: %w", err)
+		return "", err
 	}
 
 	return newID, nil
 }
 
-func (s *Storage) Update(event storagecommon.Event) error {
-	existing, err := s.GetByID(event.ID)
+// Update locks the row, so the version check and the write are atomic.
+func (s *Storage) Update(ctx context.Context, event storagecommon.Event) (int64, error) {
+	if err := event.ValidateRecurrence(); err != nil {
+		return 0, err
+	}
+	event, err := event.Normalize()
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	if existing.UserID == event.UserID {
-		overlap, err := s.isOverlapping(event)
+	err = s.inTx(ctx, func(tx *sqlx.Tx) error {
+		var existing storagecommon.Event
+		query := "SELECT " + eventColumns + " FROM events WHERE id = $1 FOR UPDATE"
+		err := tx.GetContext(ctx, &existing, query, event.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return storagecommon.ErrEventNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get event: %w", err)
+		}
+		if !existing.MatchesVersion(event.Version) {
+			return storagecommon.ErrVersionConflict
+		}
+
+		overlap, err := isOverlapping(ctx, tx, event, event.UserID)
 		if err != nil {
 			return fmt.Errorf("checking overlapping events: %w", err)
 		}
 		if overlap {
 			return storagecommon.ErrConflictOverlap
 		}
+
+		event.Version = existing.Version + 1
+		return updateEvent(ctx, tx, event)
+	})
+	if err != nil {
+		return 0, err
 	}
+	return event.Version, nil
+}
 
-	res, err := s.db.NamedExec(`
+func updateEvent(ctx context.Context, tx *sqlx.Tx, event storagecommon.Event) error {
+	_, err := tx.NamedExecContext(ctx, `
         UPDATE events SET
             title = :title,
             start_time = :start_time,
             end_time = :end_time,
             description = :description,
             user_id = :user_id,
-            notify_before = :notify_before
+            notify_before = :notify_before,
+            rrule = :rrule,
+            exdates = :exdates,
+            time_zone = :time_zone,
+            all_day = :all_day,
+            version = :version,
+            last_notify_at = :last_notify_at
         WHERE id = :id
-    `, event)
+    `, rowOf(event))
 	if err != nil {
 		return fmt.Errorf("failed to update event: %w", err)
 	}
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
-	if rowsAffected == 0 {
-		return storagecommon.ErrEventNotFound
-	}
 	return nil
 }
 
-func (s *Storage) Delete(id string) error {
-	res, err := s.db.Exec("DELETE FROM events WHERE id = $1", id)
+func (s *Storage) Delete(ctx context.Context, id string, version int64) error {
+	if !isUUID(id) {
+		return storagecommon.ErrEventNotFound
+	}
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM events WHERE id = $1 AND ($2 = 0 OR version = $2)", id, version)
 	if err != nil {
-		return err
+		return contextError(ctx, err)
 	}
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
-	if rowsAffected == 0 {
-		return storagecommon.ErrEventNotFound
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return storagecommon.ErrVersionConflict
+}
+
+func (s *Storage) DeleteOlder(ctx context.Context, t time.Time) error {
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM events WHERE rrule = '' AND end_time < $1", t); err != nil {
+		return contextError(ctx, err)
+	}
+
+	var series []storagecommon.Event
+	query := "SELECT " + eventColumns + " FROM events WHERE rrule <> '' AND end_time < $1"
+	if err := s.db.SelectContext(ctx, &series, query, t); err != nil {
+		return contextError(ctx, err)
+	}
+
+	for _, event := range series {
+		if end, ok := event.SeriesEnd(); ok && end.Before(t) {
+			if _, err := s.db.ExecContext(ctx, "DELETE FROM events WHERE id = $1", event.ID); err != nil {
+				return contextError(ctx, err)
+			}
+		}
 	}
 	return nil
 }
 
-func (s *Storage) DeleteOlder(t time.Time) error {
-	_, err := s.db.Exec("DELETE FROM events WHERE end_time < $1", t)
-	return err
+func (s *Storage) GetByID(ctx context.Context, id string) (storagecommon.Event, error) {
+	return getByID(ctx, s.db, id)
 }
 
-func (s *Storage) GetByID(id string) (storagecommon.Event, error) {
-	if id == "" {
+func getByID(ctx context.Context, q sqlx.QueryerContext, id string) (storagecommon.Event, error) {
+	if !isUUID(id) {
 		return storagecommon.Event{}, storagecommon.ErrEventNotFound
 	}
 
 	var event storagecommon.Event
-	err := s.db.Get(&event, "SELECT * FROM events WHERE id = $1", id)
+	err := sqlx.GetContext(ctx, q, &event, "SELECT "+eventColumns+" FROM events WHERE id = $1", id)
 	if errors.Is(err, sql.ErrNoRows) {
 		return storagecommon.Event{}, storagecommon.ErrEventNotFound
 	}
-	return event, err
+	return event, contextError(ctx, err)
 }
 
-func (s *Storage) List() ([]storagecommon.Event, error) {
+func (s *Storage) List(ctx context.Context) ([]storagecommon.Event, error) {
 	var events []storagecommon.Event
-	err := s.db.Select(&events, "SELECT * FROM events")
-	return events, err
+	err := s.db.SelectContext(ctx, &events, "SELECT "+eventColumns+" FROM events ORDER BY start_time, id")
+	return events, contextError(ctx, err)
 }
 
-func (s *Storage) ListByUser(userID string) ([]storagecommon.Event, error) {
-	var events []storagecommon.Event
-	err := s.db.Select(&events, "SELECT * FROM events WHERE user_id = $1", userID)
-	return events, err
+// ListPage reads events in keyset order. Recurring series can only be matched against the
+// time window after expansion, so rows are fetched in batches until the page is filled.
+func (s *Storage) ListPage(ctx context.Context, query storagecommon.ListQuery) (storagecommon.EventPage, error) {
+	cursor, hasCursor, err := query.Cursor()
+	if err != nil {
+		return storagecommon.EventPage{}, err
+	}
+
+	limit := query.Limit()
+	events := make([]storagecommon.Event, 0, limit+1)
+	for {
+		var args []any
+		arg := func(v any) string {
+			args = append(args, v)
+			return fmt.Sprintf("$%d", len(args))
+		}
+
+		conditions := make([]string, 0, 4)
+		if hasCursor {
+			conditions = append(conditions,
+				fmt.Sprintf("(start_time, id) > (%s, %s)", arg(cursor.StartTime), arg(cursor.ID)))
+		}
+		if query.UserID != "" {
+			conditions = append(conditions, "user_id = "+arg(query.UserID))
+		}
+		if query.Title != "" {
+			conditions = append(conditions, "title ILIKE "+arg("%"+escapeLike(query.Title)+"%"))
+		}
+		if !query.To.IsZero() {
+			conditions = append(conditions, "start_time < "+arg(query.To))
+		}
+		if !query.From.IsZero() {
+			conditions = append(conditions, "(rrule <> '' OR end_time > "+arg(query.From)+")")
+		}
+
+		statement := "SELECT " + eventColumns + " FROM events"
+		if len(conditions) > 0 {
+			statement += " WHERE " + strings.Join(conditions, " AND ")
+		}
+		statement += " ORDER BY start_time, id LIMIT " + arg(limit+1)
+
+		var batch []storagecommon.Event
+		if err := s.db.SelectContext(ctx, &batch, statement, args...); err != nil {
+			return storagecommon.EventPage{}, fmt.Errorf("failed to list events: %w", contextError(ctx, err))
+		}
+
+		for _, event := range batch {
+			if query.Matches(event) {
+				events = append(events, event)
+			}
+		}
+		if len(batch) <= limit || len(events) > limit {
+			break
+		}
+
+		last := batch[len(batch)-1]
+		cursor, hasCursor = storagecommon.Cursor{StartTime: last.StartTime, ID: last.ID}, true
+	}
+
+	page := storagecommon.EventPage{Events: events}
+	if len(events) > limit {
+		page.Events = events[:limit]
+		page.NextPageToken = storagecommon.PageToken(page.Events[limit-1])
+	}
+	return page, nil
 }
 
-func (s *Storage) ListByUserInRange(userID string, from, to time.Time) ([]storagecommon.Event, error) {
+func (s *Storage) ListByUser(ctx context.Context, userID string) ([]storagecommon.Event, error) {
 	var events []storagecommon.Event
+	query := "SELECT " + eventColumns + " FROM events WHERE user_id = $1 OR id IN (" + invitedUser + ")"
+	err := s.db.SelectContext(ctx, &events, query, userID)
+	return events, contextError(ctx, err)
+}
+
+func (s *Storage) ListByUserInRange(
+	ctx context.Context,
+	userID string,
+	from, to time.Time,
+) ([]storagecommon.Event, error) {
+	var candidates []storagecommon.Event
+	query := `
+        SELECT ` + eventColumns + ` FROM events
+        WHERE (user_id = $1 OR id IN (` + invitedUser + `))
+        AND start_time < $3
+        AND (rrule <> '' OR end_time > $2)
+    `
+	if err := s.db.SelectContext(ctx, &candidates, query, userID, from, to); err != nil {
+		return nil, contextError(ctx, err)
+	}
+
+	events := make([]storagecommon.Event, 0, len(candidates))
+	for _, candidate := range candidates {
+		events = append(events, candidate.OccurrencesWithin(from, to)...)
+	}
+	return events, nil
+}
+
+// ListDueOccurrences finds single events by the indexed notify_at column; a series is a candidate while
+// its first reminder is before the end of the window and its last one, if it ends, is not before the start.
+func (s *Storage) ListDueOccurrences(ctx context.Context, from, to time.Time) ([]storagecommon.Event, error) {
+	var candidates []storagecommon.Event
 	query := `
-        SELECT * FROM events 
-        WHERE user_id = $1
-        AND NOT (end_time <= $2 OR start_time >= $3)
+        SELECT ` + eventColumns + ` FROM events
+        WHERE (rrule = '' AND notify_at >= $1 AND notify_at < $2)
+           OR (rrule <> '' AND notify_at < $2 AND (last_notify_at IS NULL OR last_notify_at >= $1))
     `
-	err := s.db.Select(&events, query, userID, from, to)
-	return events, err
+	if err := s.db.SelectContext(ctx, &candidates, query, from, to); err != nil {
+		return nil, contextError(ctx, err)
+	}
+
+	events := make([]storagecommon.Event, 0, len(candidates))
+	for _, candidate := range candidates {
+		events = append(events, candidate.DueOccurrences(from, to)...)
+	}
+	storagecommon.SortDue(events)
+	return events, nil
+}
+
+func (s *Storage) AddAttendee(ctx context.Context, attendee storagecommon.Attendee) error {
+	if attendee.Status == "" {
+		attendee.Status = storagecommon.AttendeeNeedsAction
+	}
+	if attendee.UserID == "" || !storagecommon.ValidAttendeeStatus(attendee.Status) {
+		return storagecommon.ErrInvalidAttendee
+	}
+
+	return s.inTx(ctx, func(tx *sqlx.Tx) error {
+		event, err := getByID(ctx, tx, attendee.EventID)
+		if err != nil {
+			return err
+		}
+		if event.UserID == attendee.UserID {
+			return storagecommon.ErrInvalidAttendee
+		}
+
+		if attendee.Status == storagecommon.AttendeeAccepted {
+			overlap, err := isOverlapping(ctx, tx, event, attendee.UserID)
+			if err != nil {
+				return fmt.Errorf("checking overlapping events: %w", err)
+			}
+			if overlap {
+				return storagecommon.ErrConflictOverlap
+			}
+		}
+
+		res, err := tx.NamedExecContext(ctx, `
+            INSERT INTO attendees (event_id, user_id, status)
+            VALUES (:event_id, :user_id, :status)
+            ON CONFLICT (event_id, user_id) DO NOTHING
+        `, attendee)
+		if err != nil {
+			return fmt.Errorf("failed to add attendee: %w", err)
+		}
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return storagecommon.ErrAttendeeExists
+		}
+		return nil
+	})
+}
+
+func (s *Storage) UpdateAttendee(ctx context.Context, attendee storagecommon.Attendee) error {
+	if !storagecommon.ValidAttendeeStatus(attendee.Status) {
+		return storagecommon.ErrInvalidAttendee
+	}
+
+	return s.inTx(ctx, func(tx *sqlx.Tx) error {
+		event, err := getByID(ctx, tx, attendee.EventID)
+		if err != nil {
+			return err
+		}
+
+		var status string
+		err = tx.GetContext(ctx, &status, "SELECT status FROM attendees WHERE event_id = $1 AND user_id = $2",
+			attendee.EventID, attendee.UserID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return storagecommon.ErrAttendeeNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get attendee: %w", err)
+		}
+
+		if attendee.Status == storagecommon.AttendeeAccepted && status != storagecommon.AttendeeAccepted {
+			overlap, err := isOverlapping(ctx, tx, event, attendee.UserID)
+			if err != nil {
+				return fmt.Errorf("checking overlapping events: %w", err)
+			}
+			if overlap {
+				return storagecommon.ErrConflictOverlap
+			}
+		}
+
+		_, err = tx.NamedExecContext(ctx, `
+            UPDATE attendees SET status = :status
+            WHERE event_id = :event_id AND user_id = :user_id
+        `, attendee)
+		if err != nil {
+			return fmt.Errorf("failed to update attendee: %w", err)
+		}
+		return nil
+	})
+}
+
+func (s *Storage) RemoveAttendee(ctx context.Context, eventID, userID string) error {
+	if !isUUID(eventID) {
+		return storagecommon.ErrAttendeeNotFound
+	}
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM attendees WHERE event_id = $1 AND user_id = $2", eventID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove attendee: %w", contextError(ctx, err))
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return storagecommon.ErrAttendeeNotFound
+	}
+	return nil
 }
 
-func (s *Storage) isOverlapping(event storagecommon.Event) (bool, error) {
+func (s *Storage) ListAttendees(ctx context.Context, eventID string) ([]storagecommon.Attendee, error) {
+	if _, err := s.GetByID(ctx, eventID); err != nil {
+		return nil, err
+	}
+
+	attendees := make([]storagecommon.Attendee, 0)
+	err := s.db.SelectContext(ctx, &attendees, "SELECT * FROM attendees WHERE event_id = $1 ORDER BY user_id", eventID)
+	return attendees, contextError(ctx, err)
+}
+
+// isOverlapping checks the event against the events the user owns or has accepted.
+func isOverlapping(ctx context.Context, q sqlx.QueryerContext, event storagecommon.Event, userID string) (bool, error) {
+	to := event.EndTime
+	if event.IsRecurring() {
+		to = event.StartTime.Add(storagecommon.OverlapHorizon)
+		if end, ok := event.SeriesEnd(); ok {
+			to = end
+		}
+	}
+
 	var err error
-	var exists bool
+	var candidates []storagecommon.Event
 	if event.ID == "" {
 		query := `
-            SELECT EXISTS (
-                SELECT 1 FROM events 
-                WHERE user_id = $1
-                  AND end_time > $2
-                  AND start_time < $3
-            )`
-		err = s.db.Get(&exists, query,
-			event.UserID,
+            SELECT ` + eventColumns + ` FROM events
+            WHERE (user_id = $1 OR id IN (` + acceptedByUser + `))
+              AND (rrule <> '' OR end_time > $2)
+              AND start_time < $3`
+		err = sqlx.SelectContext(ctx, q, &candidates, query,
+			userID,
 			event.StartTime,
-			event.EndTime,
+			to,
 		)
 	} else {
 		query := `
-            SELECT EXISTS (
-                SELECT 1 FROM events 
-                WHERE user_id = $1
-                  AND end_time > $2
-                  AND start_time < $3
-                  AND id != $4
-            )`
-		err = s.db.Get(&exists, query,
-			event.UserID,
+            SELECT ` + eventColumns + ` FROM events
+            WHERE (user_id = $1 OR id IN (` + acceptedByUser + `))
+              AND (rrule <> '' OR end_time > $2)
+              AND start_time < $3
+              AND id != $4`
+		err = sqlx.SelectContext(ctx, q, &candidates, query,
+			userID,
 			event.StartTime,
-			event.EndTime,
+			to,
 			event.ID,
 		)
 	}
@@ -245,10 +593,26 @@ func (s *Storage) isOverlapping(event storagecommon.Event) (bool, error) {
 		return false, err
 	}
 
-	return exists, nil
+	for _, candidate := range candidates {
+		if storagecommon.Overlaps(candidate, event) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isUUID reports whether id can be the ID of an event or a notification; the columns are UUIDs,
+// so any other string would fail the query instead of finding nothing.
+func isUUID(id string) bool {
+	_, err := uuid.Parse(id)
+	return err == nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
 
-func (s *Storage) isDuplicate(event storagecommon.Event) (bool, error) {
+func isDuplicate(ctx context.Context, tx *sqlx.Tx, event storagecommon.Event) (bool, error) {
 	const query = `
         SELECT EXISTS (
             SELECT 1 FROM events
@@ -258,15 +622,17 @@ func (s *Storage) isDuplicate(event storagecommon.Event) (bool, error) {
               AND end_time = :end_time
               AND description = :description
               AND notify_before = :notify_before
+              AND rrule = :rrule
         )`
 
 	var exists bool
-	namedQuery, err := s.db.PrepareNamed(query)
+	namedQuery, err := tx.PrepareNamedContext(ctx, query)
 	if err != nil {
 		return false, err
 	}
+	defer namedQuery.Close()
 
-	err = namedQuery.Get(&exists, event)
+	err = namedQuery.GetContext(ctx, &exists, event)
 	if err != nil {
 		return false, fmt.Errorf("failed to check duplicate: %w", err)
 	}
@@ -0,0 +1,97 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
+	"github.com/jmoiron/sqlx" //nolint:depguard
+	"github.com/lib/pq"       //nolint:depguard
+)
+
+// PostgreSQL error codes the storage reacts to.
+const (
+	codeUniqueViolation     = "23505"
+	codeExclusionViolation  = "23P01"
+	codeSerializationFailed = "40001"
+	codeDeadlockDetected    = "40P01"
+)
+
+const (
+	maxTxAttempts = 5
+	txRetryDelay  = 10 * time.Millisecond
+)
+
+// inTx runs fn in a serializable transaction, so the checks made by fn and its writes are atomic.
+// The transaction is retried when PostgreSQL aborts it in favour of a concurrent one.
+func (s *Storage) inTx(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	var err error
+	for attempt := 1; attempt <= maxTxAttempts; attempt++ {
+		err = s.runTx(ctx, fn)
+		if !isRetryable(err) {
+			return translateError(contextError(ctx, err))
+		}
+
+		jitter := time.Duration(rand.Int63n(int64(txRetryDelay))) //nolint:gosec
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(txRetryDelay*time.Duration(attempt) + jitter):
+		}
+	}
+	return fmt.Errorf("transaction aborted after %d attempts: %w", maxTxAttempts, err)
+}
+
+func (s *Storage) runTx(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func isRetryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == codeSerializationFailed || pqErr.Code == codeDeadlockDetected
+}
+
+// contextError attributes a failed query to the context when it is done,
+// so that timeouts and cancellations can be told apart from database failures.
+func contextError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil || errors.Is(err, ctx.Err()) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ctx.Err(), err)
+}
+
+// translateError maps constraint violations to the storage errors.
+func translateError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	switch pqErr.Code {
+	case codeExclusionViolation:
+		return fmt.Errorf("%w: %s", storagecommon.ErrConflictOverlap, pqErr.Constraint)
+	case codeUniqueViolation:
+		return fmt.Errorf("%w: %s", storagecommon.ErrAlreadyExists, pqErr.Constraint)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,635 @@
+package sqlitestorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
+	"github.com/google/uuid"      //nolint:depguard
+	"github.com/jmoiron/sqlx"     //nolint:depguard
+	"github.com/pressly/goose/v3" //nolint:depguard
+	_ "modernc.org/sqlite"        //nolint:depguard
+)
+
+const driverName = "sqlite"
+
+// connectionParams are appended to the DSN: attendees rely on foreign keys for cascading deletes,
+// and times are written in a format whose text order matches the time order of UTC values.
+const connectionParams = "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_time_format=sqlite"
+
+// Subqueries selecting the events a user (?1) takes part in as an attendee.
+const (
+	acceptedByUser = `SELECT event_id FROM attendees WHERE user_id = ?1 AND status = 'accepted'`
+	invitedUser    = `SELECT event_id FROM attendees WHERE user_id = ?1 AND status <> 'declined'`
+)
+
+// eventColumns lists the columns of storagecommon.Event; last_notify_at is only queried.
+const eventColumns = `id, title, start_time, end_time, description, user_id, notify_before, rrule, exdates,
+	time_zone, all_day, version`
+
+// eventRow is the event as it is written, with the reminder time of its last occurrence in Unix seconds;
+// that is NULL for open-ended series.
+type eventRow struct {
+	storagecommon.Event
+	LastNotifyAt *int64 `db:"last_notify_at"`
+}
+
+func rowOf(event storagecommon.Event) eventRow {
+	row := eventRow{Event: event}
+	if notifyAt, ok := event.LastNotifyTime(); ok {
+		unix := notifyAt.Unix()
+		row.LastNotifyAt = &unix
+	}
+	return row
+}
+
+type Config struct {
+	DSN            string
+	MigrationsPath string
+}
+
+// Storage keeps events in an SQLite database file. It uses a single connection, so a transaction
+// checking for overlaps and writing the event is never interleaved with another write.
+type Storage struct {
+	dsn            string
+	migrationsPath string
+	db             *sqlx.DB
+}
+
+func New(cfg Config) *Storage {
+	return &Storage{
+		dsn:            cfg.DSN,
+		migrationsPath: cfg.MigrationsPath,
+	}
+}
+
+func (s *Storage) Connect(ctx context.Context) error {
+	separator := "?"
+	if strings.Contains(s.dsn, "?") {
+		separator = "&"
+	}
+
+	db, err := sqlx.Open(driverName, s.dsn+separator+connectionParams)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", driverName, err)
+	}
+	db.SetMaxOpenConns(1)
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return fmt.Errorf("failed to ping %s: %w", driverName, err)
+	}
+
+	s.db = db
+	return nil
+}
+
+// Ping checks that the database answers.
+func (s *Storage) Ping(ctx context.Context) error {
+	if s.db == nil {
+		return fmt.Errorf("%s is not connected", driverName)
+	}
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping %s: %w", driverName, err)
+	}
+	return nil
+}
+
+func (s *Storage) Close(_ context.Context) error {
+	if s.db != nil {
+		if err := s.db.Close(); err != nil {
+			return fmt.Errorf("failed to close DB connection: %w", err)
+		}
+		s.db = nil
+	}
+	return nil
+}
+
+func (s *Storage) Migrate() error {
+	if s.db == nil {
+		return fmt.Errorf("database connection is not established")
+	}
+
+	if err := goose.SetDialect("sqlite3"); err != nil {
+		return fmt.Errorf("failed to set dialect: %w", err)
+	}
+
+	if err := goose.Up(s.db.DB, s.migrationsPath); err != nil {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+
+	return s.fillLastNotifyAt(context.Background())
+}
+
+// fillLastNotifyAt fills the column for the series written before it existed.
+func (s *Storage) fillLastNotifyAt(ctx context.Context) error {
+	load := func(ctx context.Context) ([]storagecommon.Event, error) {
+		var series []storagecommon.Event
+		query := "SELECT " + eventColumns + ` FROM events
+            WHERE rrule <> '' AND last_notify_at IS NULL
+            AND (upper(rrule) LIKE '%COUNT=%' OR upper(rrule) LIKE '%UNTIL=%')`
+		err := s.db.SelectContext(ctx, &series, query)
+		return series, err
+	}
+	store := func(ctx context.Context, id string, notifyAt time.Time) error {
+		_, err := s.db.ExecContext(ctx, "UPDATE events SET last_notify_at = ? WHERE id = ?", notifyAt.Unix(), id)
+		return err
+	}
+	return storagecommon.FillLastNotifyAt(ctx, load, store)
+}
+
+func (s *Storage) Create(ctx context.Context, event storagecommon.Event) (string, error) {
+	if err := event.ValidateRecurrence(); err != nil {
+		return "", err
+	}
+	event, err := event.Normalize()
+	if err != nil {
+		return "", err
+	}
+	event = inUTC(event)
+	event.ID = uuid.NewString()
+	event.Version = storagecommon.InitialVersion
+
+	err = s.inTx(ctx, func(tx *sqlx.Tx) error {
+		duplicate, err := isDuplicate(ctx, tx, event)
+		if err != nil {
+			return err
+		}
+		if duplicate {
+			return storagecommon.ErrAlreadyExists
+		}
+
+		overlap, err := isOverlapping(ctx, tx, event, event.UserID)
+		if err != nil {
+			return fmt.Errorf("checking overlapping events: %w", err)
+		}
+		if overlap {
+			return storagecommon.ErrConflictOverlap
+		}
+
+		_, err = tx.NamedExecContext(ctx, `
+            INSERT INTO events (
+                id, user_id, title, start_time, end_time, description, notify_before, rrule, exdates,
+                time_zone, all_day, version, last_notify_at
+            ) VALUES (
+                :id, :user_id, :title, :start_time, :end_time, :description, :notify_before, :rrule, :exdates,
+                :time_zone, :all_day, :version, :last_notify_at
+            )`, rowOf(event))
+		if err != nil {
+			return fmt.Errorf("failed to create event: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return event.ID, nil
+}
+
+func (s *Storage) Update(ctx context.Context, event storagecommon.Event) (int64, error) {
+	if err := event.ValidateRecurrence(); err != nil {
+		return 0, err
+	}
+	event, err := event.Normalize()
+	if err != nil {
+		return 0, err
+	}
+	event = inUTC(event)
+
+	err = s.inTx(ctx, func(tx *sqlx.Tx) error {
+		existing, err := getByID(ctx, tx, event.ID)
+		if err != nil {
+			return err
+		}
+		if !existing.MatchesVersion(event.Version) {
+			return storagecommon.ErrVersionConflict
+		}
+
+		overlap, err := isOverlapping(ctx, tx, event, event.UserID)
+		if err != nil {
+			return fmt.Errorf("checking overlapping events: %w", err)
+		}
+		if overlap {
+			return storagecommon.ErrConflictOverlap
+		}
+
+		event.Version = existing.Version + 1
+		_, err = tx.NamedExecContext(ctx, `
+            UPDATE events SET
+                title = :title,
+                start_time = :start_time,
+                end_time = :end_time,
+                description = :description,
+                user_id = :user_id,
+                notify_before = :notify_before,
+                rrule = :rrule,
+                exdates = :exdates,
+                time_zone = :time_zone,
+                all_day = :all_day,
+                version = :version,
+                last_notify_at = :last_notify_at
+            WHERE id = :id`, rowOf(event))
+		if err != nil {
+			return fmt.Errorf("failed to update event: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return event.Version, nil
+}
+
+func (s *Storage) Delete(ctx context.Context, id string, version int64) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM events WHERE id = ?1 AND (?2 = 0 OR version = ?2)", id, version)
+	if err != nil {
+		return contextError(ctx, err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return storagecommon.ErrVersionConflict
+}
+
+func (s *Storage) DeleteOlder(ctx context.Context, t time.Time) error {
+	t = t.UTC()
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM events WHERE rrule = '' AND end_time < ?", t); err != nil {
+		return contextError(ctx, err)
+	}
+
+	var series []storagecommon.Event
+	query := "SELECT " + eventColumns + " FROM events WHERE rrule <> '' AND end_time < ?"
+	if err := s.db.SelectContext(ctx, &series, query, t); err != nil {
+		return contextError(ctx, err)
+	}
+
+	for _, event := range series {
+		if end, ok := event.SeriesEnd(); ok && end.Before(t) {
+			if _, err := s.db.ExecContext(ctx, "DELETE FROM events WHERE id = ?", event.ID); err != nil {
+				return contextError(ctx, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Storage) GetByID(ctx context.Context, id string) (storagecommon.Event, error) {
+	return getByID(ctx, s.db, id)
+}
+
+func getByID(ctx context.Context, q sqlx.QueryerContext, id string) (storagecommon.Event, error) {
+	if id == "" {
+		return storagecommon.Event{}, storagecommon.ErrEventNotFound
+	}
+
+	var event storagecommon.Event
+	err := sqlx.GetContext(ctx, q, &event, "SELECT "+eventColumns+" FROM events WHERE id = ?", id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return storagecommon.Event{}, storagecommon.ErrEventNotFound
+	}
+	return event, contextError(ctx, err)
+}
+
+func (s *Storage) List(ctx context.Context) ([]storagecommon.Event, error) {
+	var events []storagecommon.Event
+	err := s.db.SelectContext(ctx, &events, "SELECT "+eventColumns+" FROM events ORDER BY start_time, id")
+	return events, contextError(ctx, err)
+}
+
+// ListPage reads events in keyset order. Recurring series can only be matched against the
+// time window after expansion, so rows are fetched in batches until the page is filled.
+func (s *Storage) ListPage(ctx context.Context, query storagecommon.ListQuery) (storagecommon.EventPage, error) {
+	cursor, hasCursor, err := query.Cursor()
+	if err != nil {
+		return storagecommon.EventPage{}, err
+	}
+
+	limit := query.Limit()
+	events := make([]storagecommon.Event, 0, limit+1)
+	for {
+		var args []any
+		conditions := make([]string, 0, 4)
+		if hasCursor {
+			conditions = append(conditions, "(start_time, id) > (?, ?)")
+			args = append(args, cursor.StartTime.UTC(), cursor.ID)
+		}
+		if query.UserID != "" {
+			conditions = append(conditions, "user_id = ?")
+			args = append(args, query.UserID)
+		}
+		if query.Title != "" {
+			conditions = append(conditions, `title LIKE ? ESCAPE '\'`)
+			args = append(args, "%"+escapeLike(query.Title)+"%")
+		}
+		if !query.To.IsZero() {
+			conditions = append(conditions, "start_time < ?")
+			args = append(args, query.To.UTC())
+		}
+		if !query.From.IsZero() {
+			conditions = append(conditions, "(rrule <> '' OR end_time > ?)")
+			args = append(args, query.From.UTC())
+		}
+
+		statement := "SELECT " + eventColumns + " FROM events"
+		if len(conditions) > 0 {
+			statement += " WHERE " + strings.Join(conditions, " AND ")
+		}
+		statement += " ORDER BY start_time, id LIMIT ?"
+		args = append(args, limit+1)
+
+		var batch []storagecommon.Event
+		if err := s.db.SelectContext(ctx, &batch, statement, args...); err != nil {
+			return storagecommon.EventPage{}, fmt.Errorf("failed to list events: %w", contextError(ctx, err))
+		}
+
+		for _, event := range batch {
+			if query.Matches(event) {
+				events = append(events, event)
+			}
+		}
+		if len(batch) <= limit || len(events) > limit {
+			break
+		}
+
+		last := batch[len(batch)-1]
+		cursor, hasCursor = storagecommon.Cursor{StartTime: last.StartTime, ID: last.ID}, true
+	}
+
+	page := storagecommon.EventPage{Events: events}
+	if len(events) > limit {
+		page.Events = events[:limit]
+		page.NextPageToken = storagecommon.PageToken(page.Events[limit-1])
+	}
+	return page, nil
+}
+
+func (s *Storage) ListByUser(ctx context.Context, userID string) ([]storagecommon.Event, error) {
+	var events []storagecommon.Event
+	query := "SELECT " + eventColumns + " FROM events WHERE user_id = ?1 OR id IN (" + invitedUser + ")"
+	err := s.db.SelectContext(ctx, &events, query, userID)
+	return events, contextError(ctx, err)
+}
+
+func (s *Storage) ListByUserInRange(
+	ctx context.Context,
+	userID string,
+	from, to time.Time,
+) ([]storagecommon.Event, error) {
+	var candidates []storagecommon.Event
+	query := `
+        SELECT ` + eventColumns + ` FROM events
+        WHERE (user_id = ?1 OR id IN (` + invitedUser + `))
+        AND start_time < ?3
+        AND (rrule <> '' OR end_time > ?2)
+    `
+	if err := s.db.SelectContext(ctx, &candidates, query, userID, from.UTC(), to.UTC()); err != nil {
+		return nil, contextError(ctx, err)
+	}
+
+	events := make([]storagecommon.Event, 0, len(candidates))
+	for _, candidate := range candidates {
+		events = append(events, candidate.OccurrencesWithin(from, to)...)
+	}
+	return events, nil
+}
+
+// ListDueOccurrences narrows single events down by their reminder time and series by the reminder of
+// their last occurrence, in whole seconds; the exact window is applied after series are expanded.
+func (s *Storage) ListDueOccurrences(ctx context.Context, from, to time.Time) ([]storagecommon.Event, error) {
+	var candidates []storagecommon.Event
+	query := `
+        SELECT ` + eventColumns + ` FROM events
+        WHERE (rrule = '' AND unixepoch(start_time) - COALESCE(notify_before, 0) BETWEEN ?1 AND ?2)
+           OR (rrule <> '' AND unixepoch(start_time) - COALESCE(notify_before, 0) <= ?2
+               AND (last_notify_at IS NULL OR last_notify_at >= ?1))
+    `
+	if err := s.db.SelectContext(ctx, &candidates, query, from.Unix(), to.Unix()); err != nil {
+		return nil, contextError(ctx, err)
+	}
+
+	events := make([]storagecommon.Event, 0, len(candidates))
+	for _, candidate := range candidates {
+		events = append(events, candidate.DueOccurrences(from, to)...)
+	}
+	storagecommon.SortDue(events)
+	return events, nil
+}
+
+func (s *Storage) AddAttendee(ctx context.Context, attendee storagecommon.Attendee) error {
+	if attendee.Status == "" {
+		attendee.Status = storagecommon.AttendeeNeedsAction
+	}
+	if attendee.UserID == "" || !storagecommon.ValidAttendeeStatus(attendee.Status) {
+		return storagecommon.ErrInvalidAttendee
+	}
+
+	return s.inTx(ctx, func(tx *sqlx.Tx) error {
+		event, err := getByID(ctx, tx, attendee.EventID)
+		if err != nil {
+			return err
+		}
+		if event.UserID == attendee.UserID {
+			return storagecommon.ErrInvalidAttendee
+		}
+
+		if attendee.Status == storagecommon.AttendeeAccepted {
+			overlap, err := isOverlapping(ctx, tx, event, attendee.UserID)
+			if err != nil {
+				return fmt.Errorf("checking overlapping events: %w", err)
+			}
+			if overlap {
+				return storagecommon.ErrConflictOverlap
+			}
+		}
+
+		res, err := tx.NamedExecContext(ctx, `
+            INSERT INTO attendees (event_id, user_id, status)
+            VALUES (:event_id, :user_id, :status)
+            ON CONFLICT (event_id, user_id) DO NOTHING
+        `, attendee)
+		if err != nil {
+			return fmt.Errorf("failed to add attendee: %w", err)
+		}
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return storagecommon.ErrAttendeeExists
+		}
+		return nil
+	})
+}
+
+func (s *Storage) UpdateAttendee(ctx context.Context, attendee storagecommon.Attendee) error {
+	if !storagecommon.ValidAttendeeStatus(attendee.Status) {
+		return storagecommon.ErrInvalidAttendee
+	}
+
+	return s.inTx(ctx, func(tx *sqlx.Tx) error {
+		event, err := getByID(ctx, tx, attendee.EventID)
+		if err != nil {
+			return err
+		}
+
+		var status string
+		err = tx.GetContext(ctx, &status, "SELECT status FROM attendees WHERE event_id = ? AND user_id = ?",
+			attendee.EventID, attendee.UserID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return storagecommon.ErrAttendeeNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get attendee: %w", err)
+		}
+
+		if attendee.Status == storagecommon.AttendeeAccepted && status != storagecommon.AttendeeAccepted {
+			overlap, err := isOverlapping(ctx, tx, event, attendee.UserID)
+			if err != nil {
+				return fmt.Errorf("checking overlapping events: %w", err)
+			}
+			if overlap {
+				return storagecommon.ErrConflictOverlap
+			}
+		}
+
+		_, err = tx.NamedExecContext(ctx, `
+            UPDATE attendees SET status = :status
+            WHERE event_id = :event_id AND user_id = :user_id
+        `, attendee)
+		if err != nil {
+			return fmt.Errorf("failed to update attendee: %w", err)
+		}
+		return nil
+	})
+}
+
+func (s *Storage) RemoveAttendee(ctx context.Context, eventID, userID string) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM attendees WHERE event_id = ? AND user_id = ?", eventID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove attendee: %w", contextError(ctx, err))
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return storagecommon.ErrAttendeeNotFound
+	}
+	return nil
+}
+
+func (s *Storage) ListAttendees(ctx context.Context, eventID string) ([]storagecommon.Attendee, error) {
+	if _, err := s.GetByID(ctx, eventID); err != nil {
+		return nil, err
+	}
+
+	attendees := make([]storagecommon.Attendee, 0)
+	err := s.db.SelectContext(ctx, &attendees, "SELECT * FROM attendees WHERE event_id = ? ORDER BY user_id", eventID)
+	return attendees, contextError(ctx, err)
+}
+
+// inTx runs fn in a transaction. With a single connection the transaction has the database to itself.
+func (s *Storage) inTx(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", contextError(ctx, err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(tx); err != nil {
+		return contextError(ctx, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", contextError(ctx, err))
+	}
+	return nil
+}
+
+// isOverlapping checks the event against the events the user owns or has accepted.
+func isOverlapping(ctx context.Context, tx *sqlx.Tx, event storagecommon.Event, userID string) (bool, error) {
+	to := event.EndTime
+	if event.IsRecurring() {
+		to = event.StartTime.Add(storagecommon.OverlapHorizon)
+		if end, ok := event.SeriesEnd(); ok {
+			to = end
+		}
+	}
+
+	var candidates []storagecommon.Event
+	query := `
+        SELECT ` + eventColumns + ` FROM events
+        WHERE (user_id = ?1 OR id IN (` + acceptedByUser + `))
+          AND (rrule <> '' OR end_time > ?2)
+          AND start_time < ?3
+          AND id <> ?4`
+	err := tx.SelectContext(ctx, &candidates, query, userID, event.StartTime.UTC(), to.UTC(), event.ID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, candidate := range candidates {
+		if storagecommon.Overlaps(candidate, event) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func isDuplicate(ctx context.Context, tx *sqlx.Tx, event storagecommon.Event) (bool, error) {
+	const query = `
+        SELECT EXISTS (
+            SELECT 1 FROM events
+            WHERE user_id = :user_id
+              AND title = :title
+              AND start_time = :start_time
+              AND end_time = :end_time
+              AND description = :description
+              AND notify_before = :notify_before
+              AND rrule = :rrule
+        )`
+
+	namedQuery, err := tx.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return false, err
+	}
+	defer namedQuery.Close()
+
+	var exists bool
+	if err := namedQuery.GetContext(ctx, &exists, event); err != nil {
+		return false, fmt.Errorf("failed to check duplicate: %w", err)
+	}
+	return exists, nil
+}
+
+// inUTC converts the event times to UTC, so that they compare correctly as text.
+func inUTC(event storagecommon.Event) storagecommon.Event {
+	event.StartTime = event.StartTime.UTC()
+	event.EndTime = event.EndTime.UTC()
+	return event
+}
+
+// contextError attributes a failed query to the context when it is done,
+// so that timeouts and cancellations can be told apart from database failures.
+func contextError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil || errors.Is(err, ctx.Err()) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ctx.Err(), err)
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -0,0 +1,87 @@
+package sqlitestorage
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	i "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/interface"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/storagetest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage_Conformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) i.Storage {
+		return newSQLiteStorage(t)
+	})
+}
+
+func TestStorage_LastNotifyAt(t *testing.T) {
+	ctx := context.Background()
+	storage := newSQLiteStorage(t)
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	create := func(event storagecommon.Event) string {
+		t.Helper()
+		event.UserID = event.Title
+		event.EndTime = event.StartTime.Add(15 * time.Minute)
+		id, err := storage.Create(ctx, event)
+		require.NoError(t, err)
+		return id
+	}
+	singleID := create(storagecommon.Event{Title: "Review", StartTime: start, NotifyBefore: 600})
+	boundedID := create(storagecommon.Event{
+		Title: "Standup", StartTime: start, NotifyBefore: 600, RRule: "FREQ=DAILY;COUNT=3",
+	})
+	openID := create(storagecommon.Event{Title: "Sync", StartTime: start, RRule: "FREQ=DAILY"})
+
+	lastNotifyAt := func(id string) sql.NullInt64 {
+		t.Helper()
+		var value sql.NullInt64
+		require.NoError(t, storage.db.GetContext(ctx, &value, "SELECT last_notify_at FROM events WHERE id = ?", id))
+		return value
+	}
+	want := map[string]sql.NullInt64{
+		singleID:  {Int64: start.Add(-10 * time.Minute).Unix(), Valid: true},
+		boundedID: {Int64: start.Add(48*time.Hour - 10*time.Minute).Unix(), Valid: true},
+		openID:    {},
+	}
+	for id, value := range want {
+		require.Equal(t, value, lastNotifyAt(id))
+	}
+
+	_, err := storage.db.ExecContext(ctx, "UPDATE events SET last_notify_at = NULL")
+	require.NoError(t, err)
+	require.NoError(t, storage.Migrate())
+	require.Equal(t, want[boundedID], lastNotifyAt(boundedID))
+	require.Equal(t, want[openID], lastNotifyAt(openID))
+}
+
+func newSQLiteStorage(t *testing.T) *Storage {
+	t.Helper()
+
+	storageDB := New(Config{
+		DSN:            filepath.Join(t.TempDir(), "calendar.db"),
+		MigrationsPath: filepath.Join(RootDir(), "migrations", "sqlite"),
+	})
+
+	err := storageDB.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, storageDB.Close(context.Background()))
+	})
+
+	err = storageDB.Migrate()
+	require.NoError(t, err)
+
+	return storageDB
+}
+
+func RootDir() string {
+	_, currentFile, _, _ := runtime.Caller(0) //nolint:dogsled
+	return filepath.Join(filepath.Dir(currentFile), "..", "..", "..")
+}
@@ -6,8 +6,10 @@ import (
 	"time"
 
 	i "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/interface"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/leader"
 	memorystorage "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/memory"
 	sqlstorage "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/sql"
+	sqlitestorage "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/sqlite"
 )
 
 type Config struct {
@@ -16,12 +18,56 @@ type Config struct {
 	MigrationsPath string
 	Timeout        time.Duration
 	Migration      bool
+	DataDir        string
+	SnapshotEvery  int
 }
 
+// NewLeaderLock returns an advisory lock for a Postgres storage, shared by the schedulers of every process.
+// Other storages have no lock of their own, so the lock is local to the process and shared is false: two
+// schedulers on one SQLite file, or on copies of one memory data directory, both become leaders.
+func NewLeaderLock(storage i.Storage, key int64) (lock i.LeaderLock, shared bool) {
+	if wrapped, ok := storage.(interface{ Unwrap() i.Storage }); ok {
+		storage = wrapped.Unwrap()
+	}
+	if sqlStorage, ok := storage.(*sqlstorage.Storage); ok {
+		return sqlStorage.LeaderLock(key), true
+	}
+	return leader.NewMemoryLock(key), false
+}
+
+// Ping checks that the database of the storage answers; storages that keep the events in memory always do.
+func Ping(ctx context.Context, storage i.Storage) error {
+	if pinger, ok := storage.(interface{ Ping(context.Context) error }); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// InitStorage opens the storage of the configured type, instrumented with metrics and tracing.
 func InitStorage(cfg Config) (i.Storage, error) {
+	storage, err := open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return Instrument(storage, cfg.Type), nil
+}
+
+func open(cfg Config) (i.Storage, error) {
 	switch cfg.Type {
 	case "memory":
-		return memorystorage.New(), nil
+		if cfg.DataDir == "" {
+			return memorystorage.New(), nil
+		}
+
+		memoryStorage, err := memorystorage.Open(memorystorage.Config{
+			DataDir:       cfg.DataDir,
+			SnapshotEvery: cfg.SnapshotEvery,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore memory storage: %w", err)
+		}
+
+		return memoryStorage, nil
 	case "postgres":
 		sqlStorage := sqlstorage.New(sqlstorage.Config{
 			StorageType:    cfg.Type,
@@ -43,6 +89,26 @@ func InitStorage(cfg Config) (i.Storage, error) {
 		}
 
 		return sqlStorage, nil
+	case "sqlite":
+		sqliteStorage := sqlitestorage.New(sqlitestorage.Config{
+			DSN:            cfg.DSN,
+			MigrationsPath: cfg.MigrationsPath,
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+		defer cancel()
+
+		if err := sqliteStorage.Connect(ctx); err != nil {
+			return nil, fmt.Errorf("failed to open database: %w", err)
+		}
+
+		if cfg.Migration {
+			if err := sqliteStorage.Migrate(); err != nil {
+				return nil, fmt.Errorf("failed to migrate database: %w", err)
+			}
+		}
+
+		return sqliteStorage, nil
 	default:
 		return nil, fmt.Errorf("unknown storage type: %s", cfg.Type)
 	}
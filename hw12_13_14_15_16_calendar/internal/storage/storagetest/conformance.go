@@ -0,0 +1,1474 @@
+package storagetest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	i "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/interface"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
+	"github.com/stretchr/testify/assert"  //nolint:depguard
+	"github.com/stretchr/testify/require" //nolint:depguard
+)
+
+// Factory returns a new empty storage; it is called once per test case.
+type Factory func(t *testing.T) i.Storage
+
+// Run checks that the storage satisfies the contract of interfaces.Storage shared by all backends.
+func Run(t *testing.T, newStorage Factory) {
+	t.Helper()
+
+	tests := []struct {
+		name string
+		test func(t *testing.T, newStorage Factory)
+	}{
+		{name: "Create", test: testCreate},
+		{name: "ConcurrentCreate", test: testConcurrentCreate},
+		{name: "Update", test: testUpdate},
+		{name: "Delete", test: testDelete},
+		{name: "DeleteOlder", test: testDeleteOlder},
+		{name: "GetByID", test: testGetByID},
+		{name: "List", test: testList},
+		{name: "ListPage", test: testListPage},
+		{name: "Attendees", test: testAttendees},
+		{name: "TimeZones", test: testTimeZones},
+		{name: "ListByUser", test: testListByUser},
+		{name: "ListByUserInRange", test: testListByUserInRange},
+		{name: "RecurringEvents", test: testRecurringEvents},
+		{name: "DueOccurrences", test: testDueOccurrences},
+		{name: "IDs", test: testIDs},
+		{name: "RangeBounds", test: testRangeBounds},
+		{name: "UpdateOverlap", test: testUpdateOverlap},
+		{name: "Errors", test: testErrors},
+		{name: "Notifications", test: testNotifications},
+		{name: "NotificationStatuses", test: testNotificationStatuses},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newStorage)
+		})
+	}
+}
+
+type EventNoTime struct {
+	ID           string
+	Title        string
+	Description  string
+	UserID       string
+	NotifyBefore int
+}
+
+func eventToNoTime(e storagecommon.Event) EventNoTime {
+	return EventNoTime{
+		ID:           e.ID,
+		Title:        e.Title,
+		Description:  e.Description,
+		UserID:       e.UserID,
+		NotifyBefore: e.NotifyBefore,
+	}
+}
+
+func testCreate(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+
+	now := time.Now()
+	event := storagecommon.Event{
+		Title:       "Meeting",
+		StartTime:   now.UTC(),
+		EndTime:     now.Add(time.Hour).UTC(),
+		Description: "Discuss project",
+		UserID:      "user1",
+	}
+
+	tests := []struct {
+		name    string
+		input   storagecommon.Event
+		setup   func(i.Storage) i.Storage
+		wantErr error
+	}{
+		{
+			name:  "success create new event",
+			input: event,
+			setup: func(storageDB i.Storage) i.Storage {
+				return storageDB
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "fail event already exists",
+			input: event,
+			setup: func(storageDB i.Storage) i.Storage {
+				_, _ = storageDB.Create(ctx, event)
+				return storageDB
+			},
+			wantErr: storagecommon.ErrAlreadyExists,
+		},
+		{
+			name: "fail time overlap",
+			input: storagecommon.Event{
+				Title:     "Another Meeting",
+				StartTime: now.Add(30 * time.Minute),
+				EndTime:   now.Add(time.Hour + 30*time.Minute),
+				UserID:    "user1",
+			},
+			setup: func(storageDB i.Storage) i.Storage {
+				_, _ = storageDB.Create(ctx, event)
+				return storageDB
+			},
+			wantErr: storagecommon.ErrConflictOverlap,
+		},
+		{
+			name: "success different user same time",
+			input: storagecommon.Event{
+				Title:     "Another Meeting",
+				StartTime: now.Add(30 * time.Minute),
+				EndTime:   now.Add(time.Hour + 30*time.Minute),
+				UserID:    "user2",
+			},
+			setup: func(storageDB i.Storage) i.Storage {
+				_, _ = storageDB.Create(ctx, event)
+				return storageDB
+			},
+			wantErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageDB := newStorage(t)
+			s := tt.setup(storageDB)
+
+			expect := tt.input
+			id, err := s.Create(ctx, tt.input)
+			expect.ID = id
+
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				got, err := s.GetByID(ctx, id)
+				require.NoError(t, err)
+
+				require.Equal(t, eventToNoTime(expect), eventToNoTime(got))
+
+				require.WithinDuration(t, tt.input.StartTime.UTC(), got.StartTime.UTC(), time.Microsecond)
+				require.WithinDuration(t, tt.input.EndTime.UTC(), got.EndTime.UTC(), time.Microsecond)
+			}
+		})
+	}
+}
+
+func testConcurrentCreate(t *testing.T, newStorage Factory) {
+	const workers = 20
+	now := time.Now().UTC().Truncate(time.Minute)
+
+	tests := []struct {
+		name  string
+		event func(n int) storagecommon.Event
+	}{
+		{
+			name: "one-off events",
+			event: func(n int) storagecommon.Event {
+				start := now.Add(time.Duration(n) * time.Minute)
+				return storagecommon.Event{
+					UserID: "user1", Title: fmt.Sprintf("Meeting %d", n),
+					StartTime: start, EndTime: start.Add(time.Hour),
+				}
+			},
+		},
+		{
+			name: "recurring series",
+			event: func(n int) storagecommon.Event {
+				start := now.Add(time.Duration(n) * time.Minute)
+				return storagecommon.Event{
+					UserID: "user1", Title: fmt.Sprintf("Standup %d", n), RRule: "FREQ=DAILY;COUNT=5",
+					StartTime: start, EndTime: start.Add(time.Hour),
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageDB := newStorage(t)
+
+			errs := make([]error, workers)
+			var wg sync.WaitGroup
+			for n := 0; n < workers; n++ {
+				wg.Add(1)
+				go func(n int) {
+					defer wg.Done()
+					_, errs[n] = storageDB.Create(context.Background(), tt.event(n))
+				}(n)
+			}
+			wg.Wait()
+
+			created := 0
+			for _, err := range errs {
+				if err == nil {
+					created++
+					continue
+				}
+				require.ErrorIs(t, err, storagecommon.ErrConflictOverlap)
+			}
+			require.Equal(t, 1, created)
+			require.Equal(t, 1, countAllEvents(t, storageDB))
+		})
+	}
+}
+
+func testUpdate(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+
+	baseEvent := storagecommon.Event{
+		Title:       "Meeting",
+		StartTime:   now,
+		EndTime:     now.Add(time.Hour),
+		Description: "Discuss project",
+		UserID:      "user1",
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(i.Storage) (string, error)
+		input   func(id string) storagecommon.Event
+		wantErr error
+	}{
+		{
+			name: "success update event",
+			setup: func(s i.Storage) (string, error) {
+				return s.Create(ctx, baseEvent)
+			},
+			input: func(id string) storagecommon.Event {
+				return baseEvent.WithID(id).With(
+					func(e storagecommon.Event) storagecommon.Event {
+						e.Title = "Updated Meeting"
+						e.StartTime = e.EndTime.Add(-30 * time.Minute)
+						e.EndTime = e.StartTime.Add(time.Hour)
+						return e
+					},
+				)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "fail event not found",
+			setup: func(i.Storage) (string, error) {
+				return "12345678-1234-1234-1234-123456780001", nil
+			},
+			input:   baseEvent.WithID,
+			wantErr: storagecommon.ErrEventNotFound,
+		},
+		{
+			name: "fail time overlap",
+			setup: func(s i.Storage) (string, error) {
+				id, err := s.Create(ctx, baseEvent)
+				if err != nil {
+					return "", err
+				}
+
+				_, err = s.Create(ctx, storagecommon.Event{
+					UserID:      "user1",
+					Title:       "Another Event",
+					StartTime:   now.Add(time.Hour + 29*time.Minute),
+					EndTime:     now.Add(2 * time.Hour),
+					Description: "Some other meeting",
+				})
+				if err != nil {
+					return "", err
+				}
+
+				return id, nil
+			},
+			input: func(id string) storagecommon.Event {
+				return baseEvent.WithID(id).With(func(e storagecommon.Event) storagecommon.Event {
+					e.StartTime = now.Add(30 * time.Minute)
+					e.EndTime = now.Add(time.Hour + 30*time.Minute)
+					return e
+				})
+			},
+			wantErr: storagecommon.ErrConflictOverlap,
+		},
+		{
+			name: "fail stale version",
+			setup: func(s i.Storage) (string, error) {
+				return s.Create(ctx, baseEvent)
+			},
+			input: func(id string) storagecommon.Event {
+				return baseEvent.WithID(id).With(func(e storagecommon.Event) storagecommon.Event {
+					e.Version = 2
+					return e
+				})
+			},
+			wantErr: storagecommon.ErrVersionConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageDB := newStorage(t)
+
+			id, err := tt.setup(storageDB)
+			require.NoError(t, err)
+
+			input := tt.input(id)
+
+			version, err := storageDB.Update(ctx, input)
+
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+
+				got, err := storageDB.GetByID(ctx, input.ID)
+				require.NoError(t, err)
+
+				require.Equal(t, input.UserID, got.UserID)
+				require.Equal(t, input.Title, got.Title)
+				require.Equal(t, int64(2), version)
+				require.Equal(t, version, got.Version)
+				require.WithinDuration(t, input.StartTime.UTC(), got.StartTime.UTC(), time.Microsecond)
+				require.WithinDuration(t, input.EndTime.UTC(), got.EndTime.UTC(), time.Microsecond)
+			}
+		})
+	}
+}
+
+func testDelete(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+
+	event := storagecommon.Event{
+		Title:       "Meeting",
+		StartTime:   now,
+		EndTime:     now.Add(time.Hour),
+		Description: "Discuss project",
+		UserID:      "user1",
+	}
+
+	tests := []struct {
+		name    string
+		inputID *string
+		setup   func(i.Storage) (i.Storage, string)
+		wantErr error
+	}{
+		{
+			name:    "success delete existing event",
+			inputID: nil,
+			setup: func(storageDB i.Storage) (i.Storage, string) {
+				id, _ := storageDB.Create(ctx, event)
+				return storageDB, id
+			},
+			wantErr: nil,
+		},
+		{
+			name: "fail delete nonexistent event",
+			inputID: func() *string {
+				id := "12345678-1234-1234-1234-123456780002"
+				return &id
+			}(),
+			setup: func(storageDB i.Storage) (i.Storage, string) {
+				id, _ := storageDB.Create(ctx, event)
+				return storageDB, id
+			},
+			wantErr: storagecommon.ErrEventNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageDB := newStorage(t)
+			s, realID := tt.setup(storageDB)
+
+			deleteID := realID
+			if tt.inputID != nil {
+				deleteID = *tt.inputID
+			}
+			err := s.Delete(ctx, deleteID, 0)
+
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				_, err := s.GetByID(ctx, realID)
+				require.ErrorIs(t, err, storagecommon.ErrEventNotFound)
+			}
+		})
+	}
+}
+
+func testDeleteOlder(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name        string
+		cutoffTime  time.Time
+		setupEvents []storagecommon.Event
+		expected    int
+	}{
+		{
+			name:       "delete old events",
+			cutoffTime: now,
+			setupEvents: []storagecommon.Event{
+				{
+					Title:       "Past Event",
+					StartTime:   now.Add(-2 * time.Hour),
+					EndTime:     now.Add(-1 * time.Hour),
+					Description: "Should be deleted",
+					UserID:      "user1",
+				},
+				{
+					Title:       "Future Event",
+					StartTime:   now.Add(1 * time.Hour),
+					EndTime:     now.Add(2 * time.Hour),
+					Description: "Should stay",
+					UserID:      "user1",
+				},
+			},
+			expected: 1,
+		},
+		{
+			name:       "no deletion if all events are newer",
+			cutoffTime: now.Add(-1 * time.Hour),
+			setupEvents: []storagecommon.Event{
+				{
+					Title:       "Event A",
+					StartTime:   now,
+					EndTime:     now.Add(1 * time.Hour),
+					Description: "Should stay",
+					UserID:      "user1",
+				},
+				{
+					Title:       "Event B",
+					StartTime:   now.Add(2 * time.Hour),
+					EndTime:     now.Add(3 * time.Hour),
+					Description: "Should stay",
+					UserID:      "user1",
+				},
+			},
+			expected: 0,
+		},
+		{
+			name:       "all events should be deleted",
+			cutoffTime: now.Add(1 * time.Hour),
+			setupEvents: []storagecommon.Event{
+				{
+					Title:       "Event A",
+					StartTime:   now.Add(-3 * time.Hour),
+					EndTime:     now.Add(-2 * time.Hour),
+					Description: "Deleted",
+					UserID:      "user1",
+				},
+				{
+					Title:       "Event B",
+					StartTime:   now.Add(-1 * time.Hour),
+					EndTime:     now.Add(-30 * time.Minute),
+					Description: "Deleted",
+					UserID:      "user1",
+				},
+			},
+			expected: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageDB := newStorage(t)
+
+			for _, event := range tt.setupEvents {
+				_, err := storageDB.Create(ctx, event)
+				require.NoError(t, err)
+			}
+
+			initialCount := countAllEvents(t, storageDB)
+
+			err := storageDB.DeleteOlder(ctx, tt.cutoffTime)
+			require.NoError(t, err)
+
+			finalCount := countAllEvents(t, storageDB)
+			assert.Equal(t, initialCount-func() int { return finalCount }(), tt.expected)
+		})
+	}
+}
+
+func countAllEvents(t *testing.T, storage i.Storage) int {
+	t.Helper()
+	events, err := storage.List(context.Background())
+	require.NoError(t, err)
+	return len(events)
+}
+
+func testGetByID(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+
+	event := storagecommon.Event{
+		Title:       "Meeting",
+		StartTime:   now,
+		EndTime:     now.Add(time.Hour),
+		Description: "Discuss project",
+		UserID:      "user1",
+	}
+
+	tests := []struct {
+		name    string
+		inputID *string
+		setup   func(i.Storage) (i.Storage, string)
+		wantErr error
+	}{
+		{
+			name:    "success get existing event",
+			inputID: nil,
+			setup: func(storageDB i.Storage) (i.Storage, string) {
+				id, _ := storageDB.Create(ctx, event)
+				return storageDB, id
+			},
+			wantErr: nil,
+		},
+		{
+			name: "fail get nonexistent event",
+			inputID: func() *string {
+				id := "12345678-1234-1234-1234-123456780003"
+				return &id
+			}(),
+			setup: func(storageDB i.Storage) (i.Storage, string) {
+				id, _ := storageDB.Create(ctx, event)
+				return storageDB, id
+			},
+			wantErr: storagecommon.ErrEventNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageDB := newStorage(t)
+			s, realID := tt.setup(storageDB)
+
+			getID := realID
+			if tt.inputID != nil {
+				getID = *tt.inputID
+			}
+			got, err := s.GetByID(ctx, getID)
+
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, event.UserID, got.UserID)
+				require.Equal(t, event.Title, got.Title)
+				require.WithinDuration(t, event.StartTime.UTC(), got.StartTime.UTC(), time.Microsecond)
+				require.WithinDuration(t, event.EndTime.UTC(), got.EndTime.UTC(), time.Microsecond)
+			}
+		})
+	}
+}
+
+func testList(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+
+	baseEvents := []storagecommon.Event{
+		{
+			Title:     "Meeting 1",
+			StartTime: now,
+			EndTime:   now.Add(time.Hour),
+			UserID:    "user1",
+		},
+		{
+			Title:     "Meeting 2",
+			StartTime: now.Add(2 * time.Hour),
+			EndTime:   now.Add(3 * time.Hour),
+			UserID:    "user2",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(i.Storage) error // добавляем события в БД
+		wantLen int
+	}{
+		{
+			name: "list with events",
+			setup: func(s i.Storage) error {
+				for _, e := range baseEvents {
+					_, err := s.Create(ctx, e)
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			wantLen: len(baseEvents),
+		},
+		{
+			name: "empty list",
+			setup: func(i.Storage) error {
+				// ничего не создаём
+				return nil
+			},
+			wantLen: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageDB := newStorage(t)
+
+			// Шаг 1: подготовка данных
+			err := tt.setup(storageDB)
+			require.NoError(t, err)
+
+			// Шаг 2: получаем список событий
+			list, err := storageDB.List(ctx)
+			require.NoError(t, err)
+
+			// Шаг 3: проверяем длину
+			require.Len(t, list, tt.wantLen)
+		})
+	}
+}
+
+func testListPage(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+
+	base := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	storageDB := newStorage(t)
+
+	created := make(map[string]string)
+	for _, e := range []storagecommon.Event{
+		{Title: "Standup", StartTime: base, EndTime: base.Add(time.Hour), UserID: "user1"},
+		{Title: "Review 100%", StartTime: base, EndTime: base.Add(time.Hour), UserID: "user2"},
+		{Title: "Daily standup", StartTime: base.Add(-time.Hour), EndTime: base, UserID: "user3"},
+		{Title: "Retro", StartTime: base.Add(48 * time.Hour), EndTime: base.Add(49 * time.Hour), UserID: "user1"},
+		{
+			Title: "Weekly sync", StartTime: base.AddDate(0, 0, -14), EndTime: base.AddDate(0, 0, -14).Add(time.Hour),
+			UserID: "user4", RRule: "FREQ=WEEKLY;COUNT=4",
+		},
+	} {
+		id, err := storageDB.Create(ctx, e)
+		require.NoError(t, err)
+		created[e.Title] = id
+	}
+
+	var all []storagecommon.Event
+	query := storagecommon.ListQuery{PageSize: 2}
+	for {
+		page, err := storageDB.ListPage(ctx, query)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(page.Events), 2)
+		all = append(all, page.Events...)
+		if page.NextPageToken == "" {
+			break
+		}
+		query.PageToken = page.NextPageToken
+	}
+	require.Len(t, all, len(created))
+	for k := 1; k < len(all); k++ {
+		require.True(t, storagecommon.Less(all[k-1], all[k]))
+	}
+
+	page, err := storageDB.ListPage(ctx, storagecommon.ListQuery{Title: "STANDUP"})
+	require.NoError(t, err)
+	require.Equal(t, []string{created["Daily standup"], created["Standup"]}, extractIDs(page.Events))
+
+	page, err = storageDB.ListPage(ctx, storagecommon.ListQuery{Title: "%"})
+	require.NoError(t, err)
+	require.Equal(t, []string{created["Review 100%"]}, extractIDs(page.Events))
+
+	page, err = storageDB.ListPage(ctx, storagecommon.ListQuery{From: base.AddDate(0, 0, 6), To: base.AddDate(0, 0, 8)})
+	require.NoError(t, err)
+	require.Equal(t, []string{created["Weekly sync"]}, extractIDs(page.Events))
+
+	_, err = storageDB.ListPage(ctx, storagecommon.ListQuery{PageToken: "not a token"})
+	require.ErrorIs(t, err, storagecommon.ErrInvalidPageToken)
+}
+
+func testAttendees(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	storageDB := newStorage(t)
+
+	meetingID, err := storageDB.Create(ctx, storagecommon.Event{
+		UserID: "owner", Title: "Meeting", StartTime: now, EndTime: now.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	busyID, err := storageDB.Create(ctx, storagecommon.Event{
+		UserID: "guest", Title: "Busy", StartTime: now.Add(30 * time.Minute), EndTime: now.Add(2 * time.Hour),
+	})
+	require.NoError(t, err)
+
+	require.ErrorIs(t, storageDB.AddAttendee(ctx, storagecommon.Attendee{EventID: meetingID, UserID: "owner"}),
+		storagecommon.ErrInvalidAttendee)
+	require.NoError(t, storageDB.AddAttendee(ctx, storagecommon.Attendee{EventID: meetingID, UserID: "guest"}))
+	require.ErrorIs(t, storageDB.AddAttendee(ctx, storagecommon.Attendee{EventID: meetingID, UserID: "guest"}),
+		storagecommon.ErrAttendeeExists)
+
+	attendees, err := storageDB.ListAttendees(ctx, meetingID)
+	require.NoError(t, err)
+	require.Equal(t, []storagecommon.Attendee{
+		{EventID: meetingID, UserID: "guest", Status: storagecommon.AttendeeNeedsAction},
+	}, attendees)
+
+	events, err := storageDB.ListByUser(ctx, "guest")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{meetingID, busyID}, extractIDs(events))
+
+	accept := storagecommon.Attendee{EventID: meetingID, UserID: "guest", Status: storagecommon.AttendeeAccepted}
+	require.ErrorIs(t, storageDB.UpdateAttendee(ctx, accept), storagecommon.ErrConflictOverlap)
+
+	require.NoError(t, storageDB.Delete(ctx, busyID, 0))
+	require.NoError(t, storageDB.UpdateAttendee(ctx, accept))
+
+	_, err = storageDB.Create(ctx, storagecommon.Event{
+		UserID: "guest", Title: "Own", StartTime: now.Add(15 * time.Minute), EndTime: now.Add(45 * time.Minute),
+	})
+	require.ErrorIs(t, err, storagecommon.ErrConflictOverlap)
+
+	require.NoError(t, storageDB.UpdateAttendee(ctx, storagecommon.Attendee{
+		EventID: meetingID, UserID: "guest", Status: storagecommon.AttendeeDeclined,
+	}))
+	events, err = storageDB.ListByUserInRange(ctx, "guest", now, now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	require.NoError(t, storageDB.RemoveAttendee(ctx, meetingID, "guest"))
+	require.ErrorIs(t, storageDB.RemoveAttendee(ctx, meetingID, "guest"), storagecommon.ErrAttendeeNotFound)
+}
+
+func testTimeZones(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	storageDB := newStorage(t)
+
+	syncID, err := storageDB.Create(ctx, storagecommon.Event{
+		UserID: "user1", Title: "Sync", TimeZone: "Europe/Berlin", RRule: "FREQ=WEEKLY;COUNT=3",
+		StartTime: time.Date(2025, 3, 24, 8, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 3, 24, 9, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	stored, err := storageDB.GetByID(ctx, syncID)
+	require.NoError(t, err)
+	require.Equal(t, "Europe/Berlin", stored.TimeZone)
+
+	events, err := storageDB.ListByUserInRange(ctx, "user1", time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 4, 20, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	for _, e := range events {
+		require.Equal(t, 9, e.StartTime.In(berlin).Hour())
+	}
+
+	holidayID, err := storageDB.Create(ctx, storagecommon.Event{
+		UserID: "user2", Title: "Holiday", TimeZone: "Europe/Berlin", AllDay: true,
+		StartTime: time.Date(2025, 6, 2, 15, 0, 0, 0, berlin), EndTime: time.Date(2025, 6, 2, 16, 0, 0, 0, berlin),
+	})
+	require.NoError(t, err)
+
+	holiday, err := storageDB.GetByID(ctx, holidayID)
+	require.NoError(t, err)
+	require.True(t, holiday.AllDay)
+	require.True(t, holiday.StartTime.Equal(time.Date(2025, 6, 2, 0, 0, 0, 0, berlin)))
+	require.True(t, holiday.EndTime.Equal(time.Date(2025, 6, 3, 0, 0, 0, 0, berlin)))
+
+	_, err = storageDB.Create(ctx, storagecommon.Event{
+		UserID: "user3", Title: "Bad", TimeZone: "Mars/Olympus",
+		StartTime: time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC),
+	})
+	require.ErrorIs(t, err, storagecommon.ErrInvalidEvent)
+}
+
+func testListByUser(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+
+	events := []storagecommon.Event{
+		{
+			Title:     "User1 Event 1",
+			StartTime: now,
+			EndTime:   now.Add(time.Hour),
+			UserID:    "user1",
+		},
+		{
+			Title:     "User1 Event 2",
+			StartTime: now.Add(2 * time.Hour),
+			EndTime:   now.Add(3 * time.Hour),
+			UserID:    "user1",
+		},
+		{
+			Title:     "User2 Event",
+			StartTime: now,
+			EndTime:   now.Add(time.Hour),
+			UserID:    "user2",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		userID  string
+		setup   func(i.Storage) ([]string, error)
+		wantLen int
+	}{
+		{
+			name:   "list user1 events",
+			userID: "user1",
+			setup: func(s i.Storage) ([]string, error) {
+				var ids []string
+				for _, e := range events[:2] {
+					id, err := s.Create(ctx, e)
+					if err != nil {
+						return nil, err
+					}
+					ids = append(ids, id)
+				}
+				return ids, nil
+			},
+			wantLen: 2,
+		},
+		{
+			name:   "list user2 events",
+			userID: "user2",
+			setup: func(s i.Storage) ([]string, error) {
+				e := events[2]
+				id, err := s.Create(ctx, e)
+				if err != nil {
+					return nil, err
+				}
+				return []string{id}, nil
+			},
+			wantLen: 1,
+		},
+		{
+			name:   "list empty for unknown user",
+			userID: "unknown",
+			setup: func(s i.Storage) ([]string, error) {
+				for _, e := range events {
+					_, err := s.Create(ctx, e)
+					if err != nil {
+						return nil, err
+					}
+				}
+				return []string{}, nil
+			},
+			wantLen: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageDB := newStorage(t)
+
+			ids, err := tt.setup(storageDB)
+			require.NoError(t, err)
+
+			list, err := storageDB.ListByUser(ctx, tt.userID)
+			require.NoError(t, err)
+
+			require.Len(t, list, tt.wantLen)
+
+			for _, item := range list {
+				require.Equal(t, tt.userID, item.UserID)
+			}
+
+			if tt.wantLen > 0 {
+				require.ElementsMatch(t, ids, extractIDs(list))
+			}
+		})
+	}
+}
+
+func testListByUserInRange(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(24 * time.Hour) // нормализуем до начала дня
+
+	events := []storagecommon.Event{
+		{
+			Title:     "Morning Meeting",
+			StartTime: now.Add(9 * time.Hour),
+			EndTime:   now.Add(10 * time.Hour),
+			UserID:    "user1",
+		},
+		{
+			Title:     "Lunch Break",
+			StartTime: now.Add(12 * time.Hour),
+			EndTime:   now.Add(13 * time.Hour),
+			UserID:    "user1",
+		},
+		{
+			Title:     "Evening Walk",
+			StartTime: now.Add(18 * time.Hour),
+			EndTime:   now.Add(19 * time.Hour),
+			UserID:    "user1",
+		},
+		{
+			Title:     "Another User",
+			StartTime: now.Add(10 * time.Hour),
+			EndTime:   now.Add(11 * time.Hour),
+			UserID:    "user2",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		userID  string
+		from    time.Time
+		to      time.Time
+		wantLen int
+	}{
+		{
+			name:    "range covers first two events",
+			userID:  "user1",
+			from:    now.Add(8 * time.Hour),
+			to:      now.Add(12*time.Hour + 30*time.Minute),
+			wantLen: 2,
+		},
+		{
+			name:    "range covers only second event",
+			userID:  "user1",
+			from:    now.Add(12*time.Hour + 15*time.Minute),
+			to:      now.Add(12*time.Hour + 45*time.Minute),
+			wantLen: 1,
+		},
+		{
+			name:    "range has no events",
+			userID:  "user1",
+			from:    now.Add(20 * time.Hour),
+			to:      now.Add(21 * time.Hour),
+			wantLen: 0,
+		},
+		{
+			name:    "other user's events not included",
+			userID:  "user2",
+			from:    now.Add(8 * time.Hour),
+			to:      now.Add(12 * time.Hour),
+			wantLen: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageDB := newStorage(t)
+
+			for _, e := range events {
+				_, err := storageDB.Create(ctx, e)
+				require.NoError(t, err)
+			}
+
+			list, err := storageDB.ListByUserInRange(ctx, tt.userID, tt.from, tt.to)
+			require.NoError(t, err)
+
+			require.Len(t, list, tt.wantLen)
+
+			for _, item := range list {
+				require.Equal(t, tt.userID, item.UserID)
+			}
+		})
+	}
+}
+
+func testRecurringEvents(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC) // Monday
+
+	standup := storagecommon.Event{
+		Title:     "Standup",
+		StartTime: start,
+		EndTime:   start.Add(15 * time.Minute),
+		UserID:    "user1",
+		RRule:     "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+		ExDates:   storagecommon.TimeList{start.AddDate(0, 0, 9)},
+	}
+
+	t.Run("range expands occurrences", func(t *testing.T) {
+		s := newStorage(t)
+		id, err := s.Create(ctx, standup)
+		require.NoError(t, err)
+
+		list, err := s.ListByUserInRange(ctx, "user1", start.AddDate(0, 0, 7), start.AddDate(0, 0, 12))
+		require.NoError(t, err)
+
+		starts := make([]time.Time, 0, len(list))
+		for _, e := range list {
+			require.Equal(t, id, e.ID)
+			require.Equal(t, 15*time.Minute, e.EndTime.Sub(e.StartTime))
+			starts = append(starts, e.StartTime)
+		}
+		require.Equal(t, []time.Time{start.AddDate(0, 0, 7), start.AddDate(0, 0, 11)}, starts)
+	})
+
+	t.Run("overlap with future occurrence", func(t *testing.T) {
+		s := newStorage(t)
+		_, err := s.Create(ctx, standup)
+		require.NoError(t, err)
+
+		_, err = s.Create(ctx, storagecommon.Event{
+			Title:     "Review",
+			StartTime: start.AddDate(0, 0, 14).Add(10 * time.Minute),
+			EndTime:   start.AddDate(0, 0, 14).Add(time.Hour),
+			UserID:    "user1",
+		})
+		require.ErrorIs(t, err, storagecommon.ErrConflictOverlap)
+	})
+
+	t.Run("excluded occurrence is free", func(t *testing.T) {
+		s := newStorage(t)
+		_, err := s.Create(ctx, standup)
+		require.NoError(t, err)
+
+		_, err = s.Create(ctx, storagecommon.Event{
+			Title:     "Offsite",
+			StartTime: start.AddDate(0, 0, 9),
+			EndTime:   start.AddDate(0, 0, 9).Add(time.Hour),
+			UserID:    "user1",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		s := newStorage(t)
+		invalid := standup
+		invalid.RRule = "FREQ=SOMETIMES"
+		_, err := s.Create(ctx, invalid)
+		require.ErrorIs(t, err, storagecommon.ErrInvalidEvent)
+	})
+
+	t.Run("delete older keeps open-ended series", func(t *testing.T) {
+		s := newStorage(t)
+		id, err := s.Create(ctx, standup)
+		require.NoError(t, err)
+
+		finished := standup
+		finished.UserID = "user2"
+		finished.RRule = "FREQ=DAILY;COUNT=2"
+		_, err = s.Create(ctx, finished)
+		require.NoError(t, err)
+
+		require.NoError(t, s.DeleteOlder(ctx, start.AddDate(0, 1, 0)))
+
+		list, err := s.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		require.Equal(t, id, list[0].ID)
+	})
+}
+
+// testDueOccurrences checks that the reminder window is half-open, series are expanded and updated or
+// deleted events are found by their current reminder time.
+func testDueOccurrences(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	create := func(event storagecommon.Event) string {
+		t.Helper()
+		event.UserID = event.Title
+		id, err := s.Create(ctx, event)
+		require.NoError(t, err)
+		return id
+	}
+	standupID := create(storagecommon.Event{
+		Title: "Standup", StartTime: start, EndTime: start.Add(15 * time.Minute),
+		NotifyBefore: 600, RRule: "FREQ=DAILY;COUNT=3",
+	})
+	reviewID := create(storagecommon.Event{
+		Title: "Review", StartTime: start.Add(time.Hour), EndTime: start.Add(2 * time.Hour),
+	})
+	planningID := create(storagecommon.Event{
+		Title: "Planning", StartTime: start.Add(3 * time.Hour), EndTime: start.Add(4 * time.Hour), NotifyBefore: 3600,
+	})
+	retroID := create(storagecommon.Event{
+		Title: "Retro", StartTime: start.Add(30 * time.Minute), EndTime: start.Add(time.Hour), NotifyBefore: 1800,
+	})
+
+	planning, err := s.GetByID(ctx, planningID)
+	require.NoError(t, err)
+	planning.StartTime = planning.StartTime.Add(time.Hour)
+	planning.EndTime = planning.EndTime.Add(time.Hour)
+	_, err = s.Update(ctx, planning)
+	require.NoError(t, err)
+	require.NoError(t, s.Delete(ctx, retroID, 0))
+
+	due, err := s.ListDueOccurrences(ctx, start.Add(-10*time.Minute), start.Add(24*time.Hour-10*time.Minute))
+	require.NoError(t, err)
+
+	type occurrence struct {
+		ID    string
+		Start time.Time
+	}
+	got := make([]occurrence, 0, len(due))
+	for _, e := range due {
+		got = append(got, occurrence{ID: e.ID, Start: e.StartTime.UTC()})
+	}
+	require.Equal(t, []occurrence{
+		{ID: standupID, Start: start},
+		{ID: reviewID, Start: start.Add(time.Hour)},
+		{ID: planningID, Start: start.Add(4 * time.Hour)},
+	}, got)
+
+	due, err = s.ListDueOccurrences(ctx, start.Add(-time.Hour), start.Add(-10*time.Minute))
+	require.NoError(t, err)
+	require.Empty(t, due)
+
+	lastReminder := start.Add(48*time.Hour - 10*time.Minute)
+	due, err = s.ListDueOccurrences(ctx, lastReminder, lastReminder.Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, []string{standupID}, extractIDs(due))
+
+	syncID := create(storagecommon.Event{
+		Title: "Sync", StartTime: start, EndTime: start.Add(15 * time.Minute), RRule: "FREQ=DAILY",
+	})
+	due, err = s.ListDueOccurrences(ctx, lastReminder.Add(time.Second), start.Add(72*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, []string{syncID}, extractIDs(due))
+}
+
+func extractIDs(events []storagecommon.Event) []string {
+	ids := make([]string, 0, len(events))
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+// testIDs checks that Create always assigns a new ID and ignores the one supplied by the caller.
+func testIDs(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	ids := make(map[string]bool)
+	for n := 0; n < 3; n++ {
+		id, err := s.Create(ctx, storagecommon.Event{
+			ID:        "caller-supplied",
+			UserID:    "user1",
+			Title:     fmt.Sprintf("Meeting %d", n),
+			StartTime: start.Add(time.Duration(n) * time.Hour),
+			EndTime:   start.Add(time.Duration(n)*time.Hour + 30*time.Minute),
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, id)
+		require.NotEqual(t, "caller-supplied", id)
+		require.False(t, ids[id], "duplicate id %s", id)
+		ids[id] = true
+
+		got, err := s.GetByID(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, id, got.ID)
+		require.Equal(t, int64(storagecommon.InitialVersion), got.Version)
+	}
+
+	_, err := s.GetByID(ctx, "caller-supplied")
+	require.ErrorIs(t, err, storagecommon.ErrEventNotFound)
+}
+
+// testRangeBounds checks that ListByUserInRange returns events intersecting the half-open window [from, to).
+func testRangeBounds(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	id, err := s.Create(ctx, storagecommon.Event{
+		UserID: "user1", Title: "Meeting", StartTime: start, EndTime: start.Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     []string
+	}{
+		{name: "window ends at start", from: start.Add(-time.Hour), to: start, want: []string{}},
+		{name: "window starts at end", from: start.Add(time.Hour), to: start.Add(2 * time.Hour), want: []string{}},
+		{name: "window covers start", from: start, to: start.Add(time.Minute), want: []string{id}},
+		{name: "window covers end", from: start.Add(59 * time.Minute), to: start.Add(2 * time.Hour), want: []string{id}},
+		{name: "window inside event", from: start.Add(time.Minute), to: start.Add(2 * time.Minute), want: []string{id}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := s.ListByUserInRange(ctx, "user1", tt.from, tt.to)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, extractIDs(events))
+		})
+	}
+}
+
+// testUpdateOverlap checks that Update rejects overlaps in the calendar of the event's new owner.
+func testUpdateOverlap(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	_, err := s.Create(ctx, storagecommon.Event{
+		UserID: "user2", Title: "Busy", StartTime: start, EndTime: start.Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	event := storagecommon.Event{UserID: "user1", Title: "Handover", StartTime: start, EndTime: start.Add(time.Hour)}
+	id, err := s.Create(ctx, event)
+	require.NoError(t, err)
+
+	event.ID = id
+	event.UserID = "user2"
+	_, err = s.Update(ctx, event)
+	require.ErrorIs(t, err, storagecommon.ErrConflictOverlap)
+
+	event.StartTime = start.Add(time.Hour)
+	event.EndTime = start.Add(2 * time.Hour)
+	version, err := s.Update(ctx, event)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), version)
+
+	back := event
+	back.StartTime, back.EndTime = start.Add(30*time.Minute), start.Add(90*time.Minute)
+	_, err = s.Update(ctx, back)
+	require.ErrorIs(t, err, storagecommon.ErrConflictOverlap)
+}
+
+// testErrors checks the error values returned for unknown IDs and invalid events.
+func testErrors(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	for _, id := range []string{"", "missing", "12345678-1234-1234-1234-123456780001"} {
+		_, err := s.GetByID(ctx, id)
+		require.ErrorIs(t, err, storagecommon.ErrEventNotFound, "get %q", id)
+
+		_, err = s.Update(ctx, storagecommon.Event{
+			ID: id, UserID: "user1", Title: "Missing", StartTime: start, EndTime: start.Add(time.Hour),
+		})
+		require.ErrorIs(t, err, storagecommon.ErrEventNotFound, "update %q", id)
+
+		require.ErrorIs(t, s.Delete(ctx, id, 0), storagecommon.ErrEventNotFound, "delete %q", id)
+
+		_, err = s.ListAttendees(ctx, id)
+		require.ErrorIs(t, err, storagecommon.ErrEventNotFound, "list attendees %q", id)
+
+		err = s.AddAttendee(ctx, storagecommon.Attendee{EventID: id, UserID: "guest"})
+		require.ErrorIs(t, err, storagecommon.ErrEventNotFound, "add attendee %q", id)
+
+		require.ErrorIs(t, s.RemoveAttendee(ctx, id, "guest"), storagecommon.ErrAttendeeNotFound, "remove attendee %q", id)
+	}
+
+	invalid := []storagecommon.Event{
+		{UserID: "user1", Title: "Backwards", StartTime: start, EndTime: start.Add(-time.Hour)},
+		{UserID: "user1", Title: "Empty", StartTime: start, EndTime: start},
+		{UserID: "user1", Title: "Nowhere", StartTime: start, EndTime: start.Add(time.Hour), TimeZone: "Mars/Olympus"},
+	}
+	for _, event := range invalid {
+		_, err := s.Create(ctx, event)
+		require.ErrorIs(t, err, storagecommon.ErrInvalidEvent, event.Title)
+	}
+
+	events, err := s.List(ctx)
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
+// testNotifications checks the outbox: one entry per occurrence and state changes that only move forward.
+func testNotifications(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	standupID, err := s.Create(ctx, storagecommon.Event{
+		UserID: "user1", Title: "Standup", StartTime: start, EndTime: start.Add(15 * time.Minute),
+		NotifyBefore: 600, RRule: "FREQ=DAILY;COUNT=3",
+	})
+	require.NoError(t, err)
+	reviewID, err := s.Create(ctx, storagecommon.Event{
+		UserID: "user2", Title: "Review", StartTime: start.Add(time.Hour), EndTime: start.Add(2 * time.Hour),
+	})
+	require.NoError(t, err)
+
+	standup, err := s.GetByID(ctx, standupID)
+	require.NoError(t, err)
+	review, err := s.GetByID(ctx, reviewID)
+	require.NoError(t, err)
+
+	notifications := make([]storagecommon.Notification, 0)
+	for _, occurrence := range standup.Occurrences(start, start.Add(36*time.Hour)) {
+		notifications = append(notifications, storagecommon.NewNotification(occurrence, start))
+	}
+	notifications = append(notifications, storagecommon.NewNotification(review, start))
+	require.Len(t, notifications, 3)
+
+	missing := storagecommon.NewNotification(review, start)
+	missing.EventID = "12345678-1234-1234-1234-123456780001"
+
+	added, err := s.AddNotifications(ctx, append(notifications, missing))
+	require.NoError(t, err)
+	require.Equal(t, 3, added)
+
+	added, err = s.AddNotifications(ctx, notifications[:2])
+	require.NoError(t, err)
+	require.Zero(t, added, "an occurrence is scheduled once")
+
+	pending, err := s.ListPendingNotifications(ctx, time.Time{}, 0)
+	require.NoError(t, err)
+	require.Len(t, pending, 3)
+	require.Equal(t, standupID, pending[0].EventID)
+	require.True(t, start.Add(-10*time.Minute).Equal(pending[0].NotifyAt))
+	require.True(t, start.Equal(pending[0].Occurrence))
+	require.Equal(t, reviewID, pending[1].EventID)
+	require.Equal(t, "Review", pending[1].Title)
+	require.Equal(t, standupID, pending[2].EventID)
+	require.True(t, start.AddDate(0, 0, 1).Equal(pending[2].Occurrence))
+	for _, notification := range pending {
+		require.NotEmpty(t, notification.ID)
+		require.Equal(t, storagecommon.NotificationPending, notification.State)
+	}
+
+	limited, err := s.ListPendingNotifications(ctx, time.Time{}, 2)
+	require.NoError(t, err)
+	require.Len(t, limited, 2)
+
+	first := pending[0].ID
+	require.NoError(t, s.SetNotificationState(ctx, first, storagecommon.NotificationPublished, start))
+	require.NoError(t, s.SetNotificationState(ctx, first, storagecommon.NotificationDelivered, start.Add(time.Minute)))
+	require.NoError(t, s.SetNotificationState(ctx, first, storagecommon.NotificationPublished, start.Add(time.Hour)))
+	require.NoError(t, s.SetNotificationState(ctx, first, storagecommon.NotificationPending, start.Add(time.Hour)))
+
+	got, err := s.GetNotification(ctx, first)
+	require.NoError(t, err)
+	require.Equal(t, storagecommon.NotificationDelivered, got.State)
+	require.True(t, start.Add(time.Minute).Equal(got.UpdatedAt))
+
+	pending, err = s.ListPendingNotifications(ctx, time.Time{}, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{reviewID, standupID}, []string{pending[0].EventID, pending[1].EventID})
+
+	err = s.SetNotificationState(ctx, first, "sent", start)
+	require.ErrorIs(t, err, storagecommon.ErrInvalidNotification)
+	for _, id := range []string{"missing", "12345678-1234-1234-1234-123456780001"} {
+		_, err = s.GetNotification(ctx, id)
+		require.ErrorIs(t, err, storagecommon.ErrNotificationNotFound)
+		err = s.SetNotificationState(ctx, id, storagecommon.NotificationPublished, start)
+		require.ErrorIs(t, err, storagecommon.ErrNotificationNotFound)
+	}
+
+	require.NoError(t, s.Delete(ctx, standupID, 0))
+	_, err = s.GetNotification(ctx, first)
+	require.ErrorIs(t, err, storagecommon.ErrNotificationNotFound)
+	pending, err = s.ListPendingNotifications(ctx, time.Time{}, 0)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, reviewID, pending[0].EventID)
+}
+
+// testNotificationStatuses checks the delivery history and the resending of unconfirmed notifications.
+func testNotificationStatuses(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	eventID, err := s.Create(ctx, storagecommon.Event{
+		UserID: "user1", Title: "Standup", StartTime: start, EndTime: start.Add(15 * time.Minute),
+		RRule: "FREQ=DAILY;COUNT=2",
+	})
+	require.NoError(t, err)
+	event, err := s.GetByID(ctx, eventID)
+	require.NoError(t, err)
+
+	notifications := make([]storagecommon.Notification, 0)
+	for _, occurrence := range event.Occurrences(start, start.Add(36*time.Hour)) {
+		notifications = append(notifications, storagecommon.NewNotification(occurrence, start))
+	}
+	added, err := s.AddNotifications(ctx, notifications)
+	require.NoError(t, err)
+	require.Equal(t, 2, added)
+
+	listed, err := s.ListNotifications(ctx, eventID)
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+	require.True(t, start.Equal(listed[0].Occurrence))
+	require.True(t, start.AddDate(0, 0, 1).Equal(listed[1].Occurrence))
+	first, second := listed[0].ID, listed[1].ID
+
+	require.NoError(t, s.SetNotificationState(ctx, first, storagecommon.NotificationPublished, start))
+	require.NoError(t, s.SetNotificationState(ctx, second, storagecommon.NotificationPublished, start))
+
+	pending, err := s.ListPendingNotifications(ctx, time.Time{}, 0)
+	require.NoError(t, err)
+	require.Empty(t, pending)
+	pending, err = s.ListPendingNotifications(ctx, start.Add(time.Minute), 0)
+	require.NoError(t, err)
+	require.Len(t, pending, 2, "published notifications are resent until confirmed")
+
+	require.NoError(t, s.SetNotificationState(ctx, second, storagecommon.NotificationPublished, start.Add(time.Hour)))
+	pending, err = s.ListPendingNotifications(ctx, start.Add(time.Minute), 0)
+	require.NoError(t, err)
+	require.Len(t, pending, 1, "publishing again restarts the wait")
+	require.Equal(t, first, pending[0].ID)
+
+	failedAt, deliveredAt := start.Add(time.Second), start.Add(2*time.Second)
+	reports := []storagecommon.NotificationStatus{
+		{NotificationID: first, Status: "failed", ReportedAt: failedAt},
+		{NotificationID: first, Status: storagecommon.NotificationDelivered, ReportedAt: deliveredAt},
+		{NotificationID: first, Status: storagecommon.NotificationDelivered, ReportedAt: deliveredAt},
+	}
+	for _, report := range reports {
+		require.NoError(t, s.AddNotificationStatus(ctx, report))
+	}
+
+	got, err := s.GetNotification(ctx, first)
+	require.NoError(t, err)
+	require.Equal(t, storagecommon.NotificationDelivered, got.State)
+	require.True(t, deliveredAt.Equal(got.UpdatedAt))
+
+	pending, err = s.ListPendingNotifications(ctx, start.Add(2*time.Hour), 0)
+	require.NoError(t, err)
+	require.Len(t, pending, 1, "delivered notifications are not resent")
+	require.Equal(t, second, pending[0].ID)
+
+	statuses, err := s.ListNotificationStatuses(ctx, eventID)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2, "a repeated report is recorded once")
+	require.Equal(t, "failed", statuses[0].Status)
+	require.Equal(t, storagecommon.NotificationDelivered, statuses[1].Status)
+	for _, status := range statuses {
+		require.Equal(t, first, status.NotificationID)
+		require.Equal(t, eventID, status.EventID)
+		require.Equal(t, "user1", status.UserID)
+	}
+	require.True(t, failedAt.Equal(statuses[0].ReportedAt))
+
+	err = s.AddNotificationStatus(ctx, storagecommon.NotificationStatus{NotificationID: second, ReportedAt: start})
+	require.ErrorIs(t, err, storagecommon.ErrInvalidNotification)
+	for _, id := range []string{"", "missing", "12345678-1234-1234-1234-123456780001"} {
+		err = s.AddNotificationStatus(ctx, storagecommon.NotificationStatus{NotificationID: id, Status: "failed"})
+		require.ErrorIs(t, err, storagecommon.ErrNotificationNotFound, "add status %q", id)
+		_, err = s.ListNotifications(ctx, id)
+		require.ErrorIs(t, err, storagecommon.ErrEventNotFound, "list notifications %q", id)
+		_, err = s.ListNotificationStatuses(ctx, id)
+		require.ErrorIs(t, err, storagecommon.ErrEventNotFound, "list statuses %q", id)
+	}
+
+	require.NoError(t, s.Delete(ctx, eventID, 0))
+	pending, err = s.ListPendingNotifications(ctx, start.Add(2*time.Hour), 0)
+	require.NoError(t, err)
+	require.Empty(t, pending)
+}
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/auth"
+	internalhttp "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/server/http"
+	storagecommon "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttendees(t *testing.T) {
+	key := auth.Key{ID: "test", Secret: "secret"}
+	authenticator, err := auth.NewJWTAuthenticator(auth.JWTConfig{Keys: []auth.Key{key}})
+	require.NoError(t, err)
+
+	testApp := tests.NewTestAppForCalendar()
+	testApp.Authenticator = authenticator
+	require.NoError(t, testApp.Setup())
+	defer testApp.Teardown()
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	meeting := storagecommon.Event{UserID: "alice", Title: "Meeting", StartTime: now, EndTime: now.Add(time.Hour)}
+	require.NoError(t, testApp.Seed(&meeting))
+
+	token := func(userID string) string {
+		signed, err := auth.Sign(auth.NewClaims(userID, nil, time.Hour), key)
+		require.NoError(t, err)
+		return signed
+	}
+	alice, bob, carol := token("alice"), token("bob"), token("carol")
+
+	do := func(method, target, bearer string, body any) *httptest.ResponseRecorder {
+		var raw []byte
+		if body != nil {
+			raw, err = json.Marshal(body)
+			require.NoError(t, err)
+		}
+		req, _ := http.NewRequestWithContext(context.Background(), method, target, bytes.NewReader(raw))
+		req.Header.Set("Authorization", "Bearer "+bearer)
+		w := httptest.NewRecorder()
+		testApp.Server.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	invite := internalhttp.InviteAttendeeRequest{EventID: meeting.ID, UserID: "bob"}
+	accept := internalhttp.RespondToInvitationRequest{EventID: meeting.ID, UserID: "bob", Status: "accepted"}
+
+	cases := []struct {
+		name   string
+		method string
+		target string
+		bearer string
+		body   any
+		want   int
+	}{
+		{name: "invite by non-owner", method: "POST", target: "/event/attendees/invite", bearer: carol, body: invite,
+			want: http.StatusForbidden},
+		{name: "invite", method: "POST", target: "/event/attendees/invite", bearer: alice, body: invite,
+			want: http.StatusCreated},
+		{name: "invite twice", method: "POST", target: "/event/attendees/invite", bearer: alice, body: invite,
+			want: http.StatusConflict},
+		{
+			name: "invite owner", method: "POST", target: "/event/attendees/invite", bearer: alice,
+			body: internalhttp.InviteAttendeeRequest{EventID: meeting.ID, UserID: "alice"},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "invite to missing event", method: "POST", target: "/event/attendees/invite", bearer: alice,
+			body: internalhttp.InviteAttendeeRequest{EventID: "missing", UserID: "bob"},
+			want: http.StatusNotFound,
+		},
+		{name: "attendee reads event", method: "GET", target: "/event/get?id=" + meeting.ID, bearer: bob,
+			want: http.StatusOK},
+		{name: "stranger reads event", method: "GET", target: "/event/get?id=" + meeting.ID, bearer: carol,
+			want: http.StatusForbidden},
+		{name: "respond for another user", method: "POST", target: "/event/attendees/respond", bearer: carol,
+			body: accept, want: http.StatusForbidden},
+		{
+			name: "respond with invalid status", method: "POST", target: "/event/attendees/respond", bearer: bob,
+			body: internalhttp.RespondToInvitationRequest{EventID: meeting.ID, UserID: "bob", Status: "maybe"},
+			want: http.StatusBadRequest,
+		},
+		{name: "respond", method: "POST", target: "/event/attendees/respond", bearer: bob, body: accept,
+			want: http.StatusOK},
+		{name: "stranger lists attendees", method: "GET", target: "/event/attendees?eventId=" + meeting.ID, bearer: carol,
+			want: http.StatusForbidden},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			w := do(tt.method, tt.target, tt.bearer, tt.body)
+			assert.Equal(t, tt.want, w.Code, w.Body.String())
+		})
+	}
+
+	w := do("GET", "/event/attendees?eventId="+meeting.ID, bob, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var response internalhttp.ListAttendeesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []internalhttp.AttendeeResponse{{UserID: "bob", Status: "accepted"}}, response.Attendees)
+
+	w = do("DELETE", "/event/attendees/remove?eventId="+meeting.ID+"&userId=bob", carol, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = do("DELETE", "/event/attendees/remove?eventId="+meeting.ID+"&userId=bob", bob, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = do("DELETE", "/event/attendees/remove?eventId="+meeting.ID+"&userId=bob", alice, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/auth"
+	internalhttp "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/server/http"
+	storagecommon "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorization(t *testing.T) {
+	key := auth.Key{ID: "test", Secret: "secret"}
+	authenticator, err := auth.NewJWTAuthenticator(auth.JWTConfig{Keys: []auth.Key{key}})
+	require.NoError(t, err)
+
+	testApp := tests.NewTestAppForCalendar()
+	testApp.Authenticator = authenticator
+	require.NoError(t, testApp.Setup())
+	defer testApp.Teardown()
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	own := storagecommon.Event{UserID: "alice", Title: "Alice", StartTime: now, EndTime: now.Add(time.Hour)}
+	foreign := storagecommon.Event{UserID: "bob", Title: "Bob", StartTime: now, EndTime: now.Add(time.Hour)}
+	require.NoError(t, testApp.Seed(&own, &foreign))
+
+	token := func(userID string, roles ...string) string {
+		signed, err := auth.Sign(auth.NewClaims(userID, roles, time.Hour), key)
+		require.NoError(t, err)
+		return signed
+	}
+	alice, admin := token("alice"), token("root", auth.RoleAdmin)
+
+	do := func(method, target, bearer string, body []byte) *httptest.ResponseRecorder {
+		req, _ := http.NewRequestWithContext(context.Background(), method, target, bytes.NewReader(body))
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		req.Header.Set("If-Match", "*")
+		w := httptest.NewRecorder()
+		testApp.Server.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	cases := []struct {
+		name   string
+		method string
+		target string
+		bearer string
+		body   any
+		want   int
+	}{
+		{name: "missing token", method: "GET", target: "/event/get?id=" + own.ID, want: http.StatusUnauthorized},
+		{
+			name: "invalid token", method: "GET", target: "/event/get?id=" + own.ID, bearer: "garbage",
+			want: http.StatusUnauthorized,
+		},
+		{name: "public path", method: "GET", target: "/", want: http.StatusOK},
+		{name: "own event", method: "GET", target: "/event/get?id=" + own.ID, bearer: alice, want: http.StatusOK},
+		{
+			name: "foreign event", method: "GET", target: "/event/get?id=" + foreign.ID, bearer: alice,
+			want: http.StatusForbidden,
+		},
+		{
+			name: "admin reads foreign event", method: "GET", target: "/event/get?id=" + foreign.ID, bearer: admin,
+			want: http.StatusOK,
+		},
+		{
+			name: "foreign user list", method: "GET", target: "/events/user?userId=bob", bearer: alice,
+			want: http.StatusForbidden,
+		},
+		{
+			name: "delete foreign event", method: "DELETE", target: "/event/delete?id=" + foreign.ID, bearer: alice,
+			want: http.StatusForbidden,
+		},
+		{
+			name:   "update foreign event",
+			method: "POST",
+			target: "/event/update",
+			bearer: alice,
+			body: internalhttp.UpdateEventRequest{
+				ID: foreign.ID, UserID: "alice", Title: "Mine now", StartTime: now.Unix(), EndTime: now.Add(time.Hour).Unix(),
+			},
+			want: http.StatusForbidden,
+		},
+		{
+			name:   "create event for another user",
+			method: "POST",
+			target: "/event/create",
+			bearer: alice,
+			body: internalhttp.CreateEventRequest{
+				UserID: "bob", Title: "Spam", StartTime: now.Add(5 * time.Hour).Unix(), EndTime: now.Add(6 * time.Hour).Unix(),
+			},
+			want: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var body []byte
+			if tt.body != nil {
+				body, err = json.Marshal(tt.body)
+				require.NoError(t, err)
+			}
+			w := do(tt.method, tt.target, tt.bearer, body)
+			assert.Equal(t, tt.want, w.Code, w.Body.String())
+		})
+	}
+
+	listIDs := func(bearer string) []string {
+		w := do("GET", "/events/list", bearer, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response internalhttp.ListEventsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		ids := make([]string, 0, len(response.Events))
+		for _, e := range response.Events {
+			ids = append(ids, e.ID)
+		}
+		return ids
+	}
+	assert.Equal(t, []string{own.ID}, listIDs(alice))
+	assert.ElementsMatch(t, []string{own.ID, foreign.ID}, listIDs(admin))
+}
@@ -51,7 +51,7 @@ func TestCreateEvent(t *testing.T) {
 
 	assert.Equal(t, "created", response.Status)
 
-	list, err := testApp.Storage.List()
+	list, err := testApp.Storage.List(context.Background())
 	require.NoError(t, err)
 	assert.GreaterOrEqual(t, len(list), 1)
 
@@ -65,3 +65,72 @@ func TestCreateEvent(t *testing.T) {
 		assert.Equal(t, int(event.NotifyBefore), created.NotifyBefore)
 	}
 }
+
+func TestCreateEvent_AllDayInTimeZone(t *testing.T) {
+	testApp := tests.NewTestAppForCalendar()
+	require.NoError(t, testApp.Setup())
+	defer testApp.Teardown()
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	day := time.Date(2025, 6, 2, 15, 30, 0, 0, tokyo)
+
+	create := func(event internalhttp.CreateEventRequest) *httptest.ResponseRecorder {
+		body, err := json.Marshal(event)
+		require.NoError(t, err)
+		req, _ := http.NewRequestWithContext(context.Background(), "POST", "/event/create", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+		testApp.Server.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	w := create(internalhttp.CreateEventRequest{
+		UserID: "user123", Title: "Holiday", StartTime: day.Unix(), EndTime: day.Unix(),
+		TimeZone: "Asia/Tokyo", AllDay: true,
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	list, err := testApp.Storage.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.True(t, list[0].AllDay)
+	assert.Equal(t, "Asia/Tokyo", list[0].TimeZone)
+	assert.True(t, list[0].StartTime.Equal(time.Date(2025, 6, 2, 0, 0, 0, 0, tokyo)))
+	assert.True(t, list[0].EndTime.Equal(time.Date(2025, 6, 3, 0, 0, 0, 0, tokyo)))
+
+	w = create(internalhttp.CreateEventRequest{
+		UserID: "user123", Title: "Nowhere", StartTime: day.AddDate(0, 0, 5).Unix(),
+		EndTime: day.AddDate(0, 0, 5).Add(time.Hour).Unix(), TimeZone: "Nowhere/Land",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreateEvent_Duplicate(t *testing.T) {
+	testApp := tests.NewTestAppForCalendar()
+	require.NoError(t, testApp.Setup())
+	defer testApp.Teardown()
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	body, err := json.Marshal(internalhttp.CreateEventRequest{
+		UserID: "user123", Title: "Team Meeting", StartTime: now.Unix(), EndTime: now.Add(time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+
+	create := func() *httptest.ResponseRecorder {
+		req, _ := http.NewRequestWithContext(context.Background(), "POST", "/event/create", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		testApp.Server.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	w := create()
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	w = create()
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "event already exists")
+
+	list, err := testApp.Storage.List(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+}
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	internalhttp "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/server/http"
 	storagecommon "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/tests"
 	"github.com/stretchr/testify/assert"
@@ -23,7 +24,6 @@ func TestDeleteEvent(t *testing.T) {
 	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
 
 	initialEvent := storagecommon.Event{
-		ID:           "event123",
 		UserID:       "user123",
 		Title:        "Old Title",
 		Description:  "Old Description",
@@ -31,13 +31,23 @@ func TestDeleteEvent(t *testing.T) {
 		EndTime:      now.Add(time.Hour),
 		NotifyBefore: 600,
 	}
-	_, err = testApp.Storage.Create(initialEvent)
-	require.NoError(t, err)
+	require.NoError(t, testApp.Seed(&initialEvent))
+
+	deleteWith := func(ifMatch string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequestWithContext(context.Background(), "DELETE", "/event/delete?id="+initialEvent.ID, nil)
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		w := httptest.NewRecorder()
+		testApp.Server.Handler().ServeHTTP(w, req)
+		return w
+	}
 
-	req, _ := http.NewRequestWithContext(context.Background(), "DELETE", "/event/delete?id=event123", nil)
-	w := httptest.NewRecorder()
+	assert.Equal(t, http.StatusPreconditionRequired, deleteWith("").Code)
+	assert.Equal(t, http.StatusBadRequest, deleteWith("W/\"1\"").Code)
+	assert.Equal(t, http.StatusPreconditionFailed, deleteWith(internalhttp.ETag(2)).Code)
 
-	testApp.Server.Handler().ServeHTTP(w, req)
+	w := deleteWith(internalhttp.ETag(1))
 
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
@@ -48,7 +58,7 @@ func TestDeleteEvent(t *testing.T) {
 
 	assert.Equal(t, "deleted", response["status"])
 
-	_, err = testApp.Storage.GetByID("event123")
+	_, err = testApp.Storage.GetByID(context.Background(), initialEvent.ID)
 	require.Error(t, err)
 	assert.ErrorIs(t, err, storagecommon.ErrEventNotFound)
 }
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	internalhttp "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/server/http"
+	storagecommon "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreeBusy(t *testing.T) {
+	testApp := tests.NewTestAppForCalendar()
+	require.NoError(t, testApp.Setup())
+	defer testApp.Teardown()
+
+	day := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	at := func(hour int) time.Time { return day.Add(time.Duration(hour) * time.Hour) }
+
+	for _, e := range []storagecommon.Event{
+		{UserID: "alice", Title: "Focus", StartTime: at(9), EndTime: at(11)},
+		{UserID: "bob", Title: "Standup", StartTime: at(10), EndTime: at(12)},
+		{UserID: "bob", Title: "Lunch", StartTime: at(13), EndTime: at(14)},
+	} {
+		_, err := testApp.Storage.Create(context.Background(), e)
+		require.NoError(t, err)
+	}
+
+	do := func(body any) *httptest.ResponseRecorder {
+		raw, err := json.Marshal(body)
+		require.NoError(t, err)
+		req, _ := http.NewRequestWithContext(context.Background(), "POST", "/events/freebusy", bytes.NewReader(raw))
+		w := httptest.NewRecorder()
+		testApp.Server.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(internalhttp.FreeBusyRequest{
+		UserIDs:      []string{"alice", "bob"},
+		From:         day.Unix(),
+		To:           day.Add(24 * time.Hour).Unix(),
+		Duration:     3600,
+		Step:         3600,
+		Limit:        3,
+		WorkingHours: &internalhttp.WorkingHoursRequest{Start: "09:00", End: "18:00", Weekdays: []int{1, 2, 3, 4, 5}},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response internalhttp.FreeBusyResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []internalhttp.IntervalResponse{{Start: at(9).Unix(), End: at(11).Unix()}}, response.Busy["alice"])
+	assert.Equal(t, []internalhttp.IntervalResponse{
+		{Start: at(10).Unix(), End: at(12).Unix()},
+		{Start: at(13).Unix(), End: at(14).Unix()},
+	}, response.Busy["bob"])
+	assert.Equal(t, []internalhttp.IntervalResponse{
+		{Start: at(12).Unix(), End: at(13).Unix()},
+		{Start: at(14).Unix(), End: at(15).Unix()},
+		{Start: at(15).Unix(), End: at(16).Unix()},
+	}, response.Slots)
+
+	cases := []struct {
+		name string
+		body internalhttp.FreeBusyRequest
+	}{
+		{name: "no users", body: internalhttp.FreeBusyRequest{From: day.Unix(), To: at(24).Unix(), Duration: 60}},
+		{
+			name: "empty window",
+			body: internalhttp.FreeBusyRequest{UserIDs: []string{"alice"}, From: day.Unix(), To: day.Unix(), Duration: 60},
+		},
+		{
+			name: "bad working hours",
+			body: internalhttp.FreeBusyRequest{
+				UserIDs: []string{"alice"}, From: day.Unix(), To: at(24).Unix(), Duration: 60,
+				WorkingHours: &internalhttp.WorkingHoursRequest{Start: "9am", End: "18:00"},
+			},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, http.StatusBadRequest, do(tt.body).Code)
+		})
+	}
+}
@@ -24,7 +24,6 @@ func TestGetEventByID(t *testing.T) {
 	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
 
 	initialEvent := storagecommon.Event{
-		ID:           "event123",
 		UserID:       "user123",
 		Title:        "Old Title",
 		Description:  "Old Description",
@@ -32,10 +31,9 @@ func TestGetEventByID(t *testing.T) {
 		EndTime:      now.Add(time.Hour),
 		NotifyBefore: 600,
 	}
-	_, err = testApp.Storage.Create(initialEvent)
-	require.NoError(t, err)
+	require.NoError(t, testApp.Seed(&initialEvent))
 
-	req, _ := http.NewRequestWithContext(context.Background(), "GET", "/event/get?id=event123", nil)
+	req, _ := http.NewRequestWithContext(context.Background(), "GET", "/event/get?id="+initialEvent.ID, nil)
 	w := httptest.NewRecorder()
 
 	testApp.Server.Handler().ServeHTTP(w, req)
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/auth"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/health"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/tests"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbes(t *testing.T) {
+	authenticator, err := auth.NewJWTAuthenticator(auth.JWTConfig{Keys: []auth.Key{{Secret: "secret"}}})
+	require.NoError(t, err)
+
+	var grpcErr error
+	testApp := tests.NewTestAppForCalendar()
+	testApp.Authenticator = authenticator
+	testApp.Checker = health.NewChecker(health.DefaultTimeout).
+		Add("storage", func(ctx context.Context) error { return storage.Ping(ctx, testApp.Storage) }).
+		Add("grpc", func(context.Context) error { return grpcErr })
+	require.NoError(t, testApp.Setup())
+	defer testApp.Teardown()
+
+	probe := func(path string) (int, health.Report) {
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		testApp.Server.Handler().ServeHTTP(w, req)
+
+		var report health.Report
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		return w.Code, report
+	}
+
+	code, report := probe("/healthz")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, health.StatusOK, report.Status)
+
+	code, report = probe("/readyz")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, map[string]string{"storage": "ok", "grpc": "ok"}, report.Checks)
+
+	grpcErr = errors.New("grpc server is not_serving")
+	code, report = probe("/readyz")
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, health.StatusUnavailable, report.Status)
+	require.Equal(t, "grpc server is not_serving", report.Checks["grpc"])
+
+	code, _ = probe("/healthz")
+	require.Equal(t, http.StatusOK, code)
+}
@@ -0,0 +1,149 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/ical"
+	internalhttp "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/server/http"
+	storagecommon "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportEvents(t *testing.T) {
+	testApp := tests.NewTestAppForCalendar()
+	err := testApp.Setup()
+	require.NoError(t, err)
+	defer testApp.Teardown()
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	events := []storagecommon.Event{
+		{
+			UserID:    "user123",
+			Title:     "Weekly Sync",
+			StartTime: now,
+			EndTime:   now.Add(time.Hour),
+			RRule:     "FREQ=WEEKLY;COUNT=4",
+		},
+		{
+			UserID:    "user123",
+			Title:     "Later",
+			StartTime: now.AddDate(0, 2, 0),
+			EndTime:   now.AddDate(0, 2, 0).Add(time.Hour),
+		},
+		{
+			UserID:    "user789",
+			Title:     "Another User",
+			StartTime: now,
+			EndTime:   now.Add(time.Hour),
+		},
+	}
+	require.NoError(t, testApp.Seed(&events[0], &events[1], &events[2]))
+
+	from := now.AddDate(0, 0, 14).Unix()
+	to := now.AddDate(0, 0, 15).Unix()
+	url := fmt.Sprintf("/events/export?userId=user123&from=%d&to=%d", from, to)
+
+	req, _ := http.NewRequestWithContext(context.Background(), "GET", url, nil)
+	w := httptest.NewRecorder()
+
+	testApp.Server.Handler().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, ical.ContentType, w.Header().Get("Content-Type"))
+
+	items, err := ical.Decode(w.Body)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, events[0].ID, items[0].UID)
+	assert.Equal(t, "FREQ=WEEKLY;COUNT=4", items[0].Event.RRule)
+	assert.True(t, now.Equal(items[0].Event.StartTime))
+}
+
+func TestImportEvents(t *testing.T) {
+	testApp := tests.NewTestAppForCalendar()
+	err := testApp.Setup()
+	require.NoError(t, err)
+	defer testApp.Teardown()
+
+	_, err = testApp.Storage.Create(context.Background(), storagecommon.Event{
+		UserID:    "user123",
+		Title:     "Existing",
+		StartTime: time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2025, 6, 2, 11, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	doc := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:overlap@example.com",
+		"DTSTART:20250602T103000Z",
+		"DTEND:20250602T113000Z",
+		"SUMMARY:Overlapping",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:ok@example.com",
+		"DTSTART:20250602T120000Z",
+		"DTEND:20250602T130000Z",
+		"SUMMARY:Lunch",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:invalid@example.com",
+		"DTSTART:20250603T120000Z",
+		"DTEND:20250603T130000Z",
+		"RRULE:FREQ=NEVER",
+		"SUMMARY:Broken",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	req, _ := http.NewRequestWithContext(context.Background(), "POST", "/events/import?userId=user123",
+		strings.NewReader(doc))
+	req.Header.Set("Content-Type", "text/calendar")
+	w := httptest.NewRecorder()
+
+	testApp.Server.Handler().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response internalhttp.ImportEventsResponse
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, response.Created)
+	assert.Equal(t, 2, response.Failed)
+	require.Len(t, response.Results, 3)
+	assert.Equal(t, ical.StatusOverlap, response.Results[0].Status)
+	assert.Equal(t, ical.StatusCreated, response.Results[1].Status)
+	assert.Equal(t, ical.StatusInvalid, response.Results[2].Status)
+
+	list, err := testApp.Storage.ListByUser(context.Background(), "user123")
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+}
+
+func TestImportEvents_InvalidCalendar(t *testing.T) {
+	testApp := tests.NewTestAppForCalendar()
+	err := testApp.Setup()
+	require.NoError(t, err)
+	defer testApp.Teardown()
+
+	req, _ := http.NewRequestWithContext(context.Background(), "POST", "/events/import?userId=user123",
+		strings.NewReader("not a calendar"))
+	w := httptest.NewRecorder()
+
+	testApp.Server.Handler().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
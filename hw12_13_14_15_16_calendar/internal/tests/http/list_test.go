@@ -3,8 +3,10 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
@@ -25,7 +27,6 @@ func TestListEvents(t *testing.T) {
 
 	eventsToCreate := []storagecommon.Event{
 		{
-			ID:           "event1",
 			UserID:       "user123",
 			Title:        "Event 1",
 			Description:  "Desc 1",
@@ -34,7 +35,6 @@ func TestListEvents(t *testing.T) {
 			NotifyBefore: 600,
 		},
 		{
-			ID:           "event2",
 			UserID:       "user456",
 			Title:        "Event 2",
 			Description:  "Desc 2",
@@ -44,10 +44,7 @@ func TestListEvents(t *testing.T) {
 		},
 	}
 
-	for _, e := range eventsToCreate {
-		_, err := testApp.Storage.Create(e)
-		require.NoError(t, err)
-	}
+	require.NoError(t, testApp.Seed(&eventsToCreate[0], &eventsToCreate[1]))
 
 	req, _ := http.NewRequestWithContext(context.Background(), "GET", "/events/list", nil)
 	w := httptest.NewRecorder()
@@ -76,3 +73,59 @@ func TestListEvents(t *testing.T) {
 
 	assert.ElementsMatch(t, eventsToCreate, actualEvents)
 }
+
+func TestListEvents_Pagination(t *testing.T) {
+	testApp := tests.NewTestAppForCalendar()
+	err := testApp.Setup()
+	require.NoError(t, err)
+	defer testApp.Teardown()
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	ids := make([]string, 0, 4)
+	for k, title := range []string{"Sync A", "Lunch", "Sync B", "Sync C"} {
+		id, err := testApp.Storage.Create(context.Background(), storagecommon.Event{
+			UserID:    "user123",
+			Title:     title,
+			StartTime: now.Add(time.Duration(k) * time.Hour),
+			EndTime:   now.Add(time.Duration(k)*time.Hour + 30*time.Minute),
+		})
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	list := func(query string) (int, internalhttp.ListEventsResponse) {
+		req, _ := http.NewRequestWithContext(context.Background(), "GET", "/events/list?"+query, nil)
+		w := httptest.NewRecorder()
+		testApp.Server.Handler().ServeHTTP(w, req)
+
+		var response internalhttp.ListEventsResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		}
+		return w.Code, response
+	}
+
+	code, first := list("title=sync&pageSize=2")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, first.Events, 2)
+	assert.Equal(t, ids[0], first.Events[0].ID)
+	assert.Equal(t, ids[2], first.Events[1].ID)
+	require.NotEmpty(t, first.NextPageToken)
+
+	code, second := list("title=sync&pageSize=2&pageToken=" + url.QueryEscape(first.NextPageToken))
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, second.Events, 1)
+	assert.Equal(t, ids[3], second.Events[0].ID)
+	assert.Empty(t, second.NextPageToken)
+
+	code, window := list(fmt.Sprintf("from=%d&to=%d", now.Add(time.Hour).Unix(), now.Add(2*time.Hour).Unix()))
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, window.Events, 1)
+	assert.Equal(t, ids[1], window.Events[0].ID)
+
+	code, _ = list("pageToken=garbage")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = list("pageSize=-1")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
@@ -26,7 +26,6 @@ func TestListEventsByUserInRange(t *testing.T) {
 
 	events := []storagecommon.Event{
 		{
-			ID:           "event1",
 			UserID:       "user123",
 			Title:        "In Range",
 			Description:  "Within time",
@@ -35,7 +34,6 @@ func TestListEventsByUserInRange(t *testing.T) {
 			NotifyBefore: 600,
 		},
 		{
-			ID:           "event2",
 			UserID:       "user123",
 			Title:        "Out of Range",
 			Description:  "Outside window",
@@ -44,7 +42,6 @@ func TestListEventsByUserInRange(t *testing.T) {
 			NotifyBefore: 900,
 		},
 		{
-			ID:           "event3",
 			UserID:       "user789",
 			Title:        "Another User",
 			Description:  "Different user",
@@ -54,10 +51,7 @@ func TestListEventsByUserInRange(t *testing.T) {
 		},
 	}
 
-	for _, e := range events {
-		_, err := testApp.Storage.Create(e)
-		require.NoError(t, err)
-	}
+	require.NoError(t, testApp.Seed(&events[0], &events[1], &events[2]))
 
 	from := now.Add(time.Minute * 30).Unix()
 	to := now.Add(2*time.Hour + 30*time.Minute).Unix()
@@ -76,5 +70,5 @@ func TestListEventsByUserInRange(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.Len(t, response.Events, 1)
-	assert.Equal(t, "event1", response.Events[0].ID)
+	assert.Equal(t, events[0].ID, response.Events[0].ID)
 }
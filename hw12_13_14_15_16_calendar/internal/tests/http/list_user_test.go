@@ -24,7 +24,6 @@ func TestListEventsByUser(t *testing.T) {
 	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
 
 	userA := storagecommon.Event{
-		ID:           "event1",
 		UserID:       "user123",
 		Title:        "User A Event",
 		Description:  "Desc user A",
@@ -33,7 +32,6 @@ func TestListEventsByUser(t *testing.T) {
 		NotifyBefore: 600,
 	}
 	userB := storagecommon.Event{
-		ID:           "event2",
 		UserID:       "user789",
 		Title:        "User B Event",
 		Description:  "Desc user B",
@@ -42,10 +40,7 @@ func TestListEventsByUser(t *testing.T) {
 		NotifyBefore: 900,
 	}
 
-	for _, e := range []storagecommon.Event{userA, userB} {
-		_, err := testApp.Storage.Create(e)
-		require.NoError(t, err)
-	}
+	require.NoError(t, testApp.Seed(&userA, &userB))
 
 	url := "/events/user?userId=user123"
 
@@ -62,5 +57,5 @@ func TestListEventsByUser(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.Len(t, response.Events, 1)
-	assert.Equal(t, "event1", response.Events[0].ID)
+	assert.Equal(t, userA.ID, response.Events[0].ID)
 }
@@ -0,0 +1,30 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	testApp := tests.NewTestAppForCalendar()
+	require.NoError(t, testApp.Setup())
+	defer testApp.Teardown()
+
+	req, _ := http.NewRequestWithContext(context.Background(), "GET", "/events/list", nil)
+	testApp.Server.Handler().ServeHTTP(httptest.NewRecorder(), req)
+
+	req, _ = http.NewRequestWithContext(context.Background(), "GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	testApp.Server.Handler().ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `calendar_http_requests_total{code="200",method="GET",route="/events/list"}`)
+	assert.Contains(t, body, `calendar_storage_operation_duration_seconds_count{operation="list_page",result="ok"}`)
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/auth"
+	internalhttp "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/server/http"
+	storagecommon "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/tests"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifications(t *testing.T) {
+	key := auth.Key{ID: "test", Secret: "secret"}
+	authenticator, err := auth.NewJWTAuthenticator(auth.JWTConfig{Keys: []auth.Key{key}})
+	require.NoError(t, err)
+
+	testApp := tests.NewTestAppForCalendar()
+	testApp.Authenticator = authenticator
+	require.NoError(t, testApp.Setup())
+	defer testApp.Teardown()
+
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	meeting := storagecommon.Event{
+		UserID: "alice", Title: "Meeting", StartTime: now, EndTime: now.Add(time.Hour), NotifyBefore: 600,
+	}
+	require.NoError(t, testApp.Seed(&meeting))
+
+	added, err := testApp.Storage.AddNotifications(ctx, []storagecommon.Notification{
+		storagecommon.NewNotification(meeting, now.Add(-time.Hour)),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, added)
+	system := auth.WithSystem(ctx)
+	pending, err := testApp.App.PendingNotifications(system, time.Time{}, 0)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+
+	deliveredAt := now.Add(-10 * time.Minute)
+	require.NoError(t, testApp.App.RecordNotificationStatus(system, types.NotificationStatus{
+		NotificationID: pending[0].ID, Status: storagecommon.NotificationDelivered, ReportedAt: deliveredAt,
+	}))
+
+	token := func(userID string) string {
+		signed, err := auth.Sign(auth.NewClaims(userID, nil, time.Hour), key)
+		require.NoError(t, err)
+		return signed
+	}
+	alice, carol := token("alice"), token("carol")
+
+	do := func(target, bearer string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequestWithContext(ctx, "GET", target, nil)
+		req.Header.Set("Authorization", "Bearer "+bearer)
+		w := httptest.NewRecorder()
+		testApp.Server.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	cases := []struct {
+		name   string
+		target string
+		bearer string
+		want   int
+	}{
+		{name: "missing event id", target: "/event/notifications", bearer: alice, want: http.StatusBadRequest},
+		{name: "missing event", target: "/event/notifications?eventId=missing", bearer: alice, want: http.StatusNotFound},
+		{name: "stranger", target: "/event/notifications?eventId=" + meeting.ID, bearer: carol, want: http.StatusForbidden},
+		{name: "owner", target: "/event/notifications?eventId=" + meeting.ID, bearer: alice, want: http.StatusOK},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			w := do(tt.target, tt.bearer)
+			assert.Equal(t, tt.want, w.Code, w.Body.String())
+		})
+	}
+
+	w := do("/event/notifications?eventId="+meeting.ID, alice)
+	require.Equal(t, http.StatusOK, w.Code)
+	var response internalhttp.ListNotificationsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []internalhttp.NotificationResponse{{
+		ID:         pending[0].ID,
+		Occurrence: now.Unix(),
+		NotifyAt:   now.Add(-10 * time.Minute).Unix(),
+		State:      storagecommon.NotificationDelivered,
+		Statuses: []internalhttp.NotificationStatusResponse{
+			{Status: storagecommon.NotificationDelivered, Timestamp: deliveredAt.Unix()},
+		},
+	}}, response.Notifications)
+}
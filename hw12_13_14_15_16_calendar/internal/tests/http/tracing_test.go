@@ -0,0 +1,77 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/tests"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/tracing"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	callerTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	callerSpanID  = "00f067aa0ba902b7"
+)
+
+type exportedSpan struct {
+	SpanContext struct {
+		TraceID string
+		SpanID  string
+	}
+	Parent struct {
+		SpanID string
+	}
+}
+
+func TestTracing(t *testing.T) {
+	var buf bytes.Buffer
+	shutdown, err := tracing.Init(context.Background(), tracing.Config{
+		ServiceName: "calendar",
+		Exporter:    tracing.ExporterStdout,
+		Writer:      &buf,
+	})
+	require.NoError(t, err)
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	testApp := tests.NewTestAppForCalendar()
+	require.NoError(t, testApp.Setup())
+	defer testApp.Teardown()
+
+	req, _ := http.NewRequestWithContext(context.Background(), "GET", "/events/list", nil)
+	req.Header.Set("traceparent", "00-"+callerTraceID+"-"+callerSpanID+"-01")
+	w := httptest.NewRecorder()
+	testApp.Server.Handler().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, shutdown(context.Background()))
+
+	spans := make(map[string]exportedSpan)
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		var span struct {
+			Name string
+			exportedSpan
+		}
+		require.NoError(t, decoder.Decode(&span))
+		spans[span.Name] = span.exportedSpan
+	}
+
+	server, ok := spans["GET /events/list"]
+	require.True(t, ok, "no span of the request in %v", spans)
+	require.Equal(t, callerTraceID, server.SpanContext.TraceID)
+	require.Equal(t, callerSpanID, server.Parent.SpanID)
+
+	app := spans["App.ListEvents"]
+	require.Equal(t, callerTraceID, app.SpanContext.TraceID)
+	require.Equal(t, server.SpanContext.SpanID, app.Parent.SpanID)
+
+	storage := spans["storage.list_page"]
+	require.Equal(t, callerTraceID, storage.SpanContext.TraceID)
+	require.Equal(t, app.SpanContext.SpanID, storage.Parent.SpanID)
+}
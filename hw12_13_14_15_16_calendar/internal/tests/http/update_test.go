@@ -25,7 +25,6 @@ func TestUpdateEvent(t *testing.T) {
 	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
 
 	initialEvent := storagecommon.Event{
-		ID:           "event123",
 		UserID:       "user123",
 		Title:        "Old Title",
 		Description:  "Old Description",
@@ -33,7 +32,7 @@ func TestUpdateEvent(t *testing.T) {
 		EndTime:      now.Add(time.Hour),
 		NotifyBefore: 600,
 	}
-	id, err := testApp.Storage.Create(initialEvent)
+	id, err := testApp.Storage.Create(context.Background(), initialEvent)
 	require.NoError(t, err)
 
 	updateReq := internalhttp.UpdateEventRequest{
@@ -51,21 +50,24 @@ func TestUpdateEvent(t *testing.T) {
 
 	req, _ := http.NewRequestWithContext(context.Background(), "POST", "/event/update", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", internalhttp.ETag(storagecommon.InitialVersion))
 	w := httptest.NewRecorder()
 
 	testApp.Server.Handler().ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, internalhttp.ETag(2), w.Header().Get("ETag"))
 
 	var response internalhttp.UpdateEventResponse
 	err = json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 
 	assert.Equal(t, "updated", response.Status)
-	assert.Equal(t, "event123", response.ID)
+	assert.Equal(t, id, response.ID)
+	assert.Equal(t, int64(2), response.Version)
 
-	updatedEvent, err := testApp.Storage.GetByID("event123")
+	updatedEvent, err := testApp.Storage.GetByID(context.Background(), id)
 	require.NoError(t, err)
 
 	assert.Equal(t, updateReq.Title, updatedEvent.Title)
@@ -73,4 +75,34 @@ func TestUpdateEvent(t *testing.T) {
 	assert.Equal(t, time.Unix(updateReq.StartTime, 0), updatedEvent.StartTime)
 	assert.Equal(t, time.Unix(updateReq.EndTime, 0), updatedEvent.EndTime)
 	assert.Equal(t, int(updateReq.NotifyBefore), updatedEvent.NotifyBefore)
+
+	cases := []struct {
+		name     string
+		ifMatch  string
+		wantCode int
+	}{
+		{name: "missing If-Match", ifMatch: "", wantCode: http.StatusPreconditionRequired},
+		{name: "stale version", ifMatch: internalhttp.ETag(1), wantCode: http.StatusPreconditionFailed},
+		{name: "malformed If-Match", ifMatch: "two", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequestWithContext(context.Background(), "POST", "/event/update", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+			w := httptest.NewRecorder()
+
+			testApp.Server.Handler().ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantCode, w.Code)
+		})
+	}
+
+	req, _ = http.NewRequestWithContext(context.Background(), "GET", "/event/get?id="+id, nil)
+	w = httptest.NewRecorder()
+	testApp.Server.Handler().ServeHTTP(w, req)
+	assert.Equal(t, internalhttp.ETag(2), w.Header().Get("ETag"))
 }
@@ -5,17 +5,21 @@ import (
 	"time"
 
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/app"
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/health"
 	i "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/interface"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/logger"
 	internalhttp "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/server/http"
 	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage"
+	storagecommon "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/storage/common"
 )
 
 type TestAppForCalendar struct {
-	App     *app.App
-	Server  *internalhttp.Server
-	Storage i.Storage
-	Logger  i.Logger
+	App           *app.App
+	Server        *internalhttp.Server
+	Storage       i.Storage
+	Logger        i.Logger
+	Authenticator i.Authenticator
+	Checker       *health.Checker
 }
 
 func NewTestAppForCalendar() *TestAppForCalendar {
@@ -30,10 +34,7 @@ func NewTestAppForCalendar() *TestAppForCalendar {
 }
 
 func (t *TestAppForCalendar) Setup() error {
-	t.App = &app.App{
-		Logger:  t.Logger,
-		Storage: t.Storage,
-	}
+	t.App = app.NewApp(t.Storage, t.Logger)
 
 	handlers := internalhttp.NewCalendarHandlers(t.App, t.App.Logger)
 
@@ -44,7 +45,7 @@ func (t *TestAppForCalendar) Setup() error {
 		WriteTimeout:      5 * time.Second,
 		IdleTimeout:       30 * time.Second,
 		ReadHeaderTimeout: 2 * time.Second,
-	}, handlers)
+	}, handlers, t.Authenticator, t.Checker)
 
 	go func() {
 		_ = t.Server.Start(context.Background())
@@ -53,6 +54,18 @@ func (t *TestAppForCalendar) Setup() error {
 	return nil
 }
 
+// Seed stores the events and fills in the IDs the storage assigned to them.
+func (t *TestAppForCalendar) Seed(events ...*storagecommon.Event) error {
+	for _, event := range events {
+		id, err := t.Storage.Create(context.Background(), *event)
+		if err != nil {
+			return err
+		}
+		event.ID = id
+	}
+	return nil
+}
+
 func (t *TestAppForCalendar) Teardown() {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
 	defer cancel()
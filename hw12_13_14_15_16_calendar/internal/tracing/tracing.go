@@ -0,0 +1,120 @@
+// Package tracing sets up OpenTelemetry for the calendar services. Spans are started with Start from the
+// global tracer provider that Init installs, and the trace context crosses the service boundaries through
+// the W3C trace context headers.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"                                        //nolint:depguard
+	"go.opentelemetry.io/otel/codes"                                  //nolint:depguard
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc" //nolint:depguard
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"           //nolint:depguard
+	"go.opentelemetry.io/otel/propagation"                            //nolint:depguard
+	"go.opentelemetry.io/otel/sdk/resource"                           //nolint:depguard
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"                     //nolint:depguard
+	semconv "go.opentelemetry.io/otel/semconv/v1.30.0"                //nolint:depguard
+	"go.opentelemetry.io/otel/trace"                                  //nolint:depguard
+)
+
+const instrumentationName = "github.com/dimryb/go-hw/hw12_13_14_15_calendar"
+
+const (
+	ExporterNone   = ""
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Config selects the exporter of the spans of the service: "otlp" sends them over gRPC to Endpoint,
+// "stdout" writes them as JSON to Writer, or to the standard output when Writer is nil, and an empty
+// exporter records nothing. SampleRatio is the share of new traces that are recorded, every trace when
+// it is not positive; traces continued from another service follow the decision of their parent.
+type Config struct {
+	ServiceName string
+	Exporter    string
+	Endpoint    string
+	Insecure    bool
+	SampleRatio float64
+	Writer      io.Writer
+}
+
+// Init installs the tracer provider and the propagator of the trace context globally. The returned
+// function flushes the spans that are not exported yet and stops the exporter.
+func Init(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var opt sdktrace.TracerProviderOption
+	switch cfg.Exporter {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		opts := make([]otlptracegrpc.Option, 0, 2)
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err := otlptracegrpc.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create otlp exporter: %w", err)
+		}
+		opt = sdktrace.WithBatcher(exporter)
+	case ExporterStdout:
+		writer := cfg.Writer
+		if writer == nil {
+			writer = os.Stdout
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(writer))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+		}
+		// Spans are written as they end, so that tests and debugging sessions see them without a flush.
+		opt = sdktrace.WithSyncer(exporter)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter: %s", cfg.Exporter)
+	}
+
+	ratio := cfg.SampleRatio
+	if ratio <= 0 {
+		ratio = 1
+	}
+	provider := sdktrace.NewTracerProvider(
+		opt,
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(cfg.ServiceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span of the calendar services; the returned context carries it to the spans started
+// below it.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// End marks the span failed when err is not nil and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Inject writes the trace context of ctx into the carrier of an outgoing message or request.
+func Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+}
+
+// Extract returns ctx with the trace context read from the carrier of an incoming message or request.
+func Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, carrier)
+}
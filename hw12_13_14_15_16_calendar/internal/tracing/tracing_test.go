@@ -0,0 +1,75 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/tracing"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+type exportedSpan struct {
+	Name        string
+	SpanContext struct {
+		TraceID string
+		SpanID  string
+	}
+	Parent struct {
+		TraceID string
+		SpanID  string
+	}
+	Status struct {
+		Code string
+	}
+}
+
+func decodeSpans(t *testing.T, buf *bytes.Buffer) map[string]exportedSpan {
+	t.Helper()
+	spans := make(map[string]exportedSpan)
+	decoder := json.NewDecoder(buf)
+	for decoder.More() {
+		var span exportedSpan
+		require.NoError(t, decoder.Decode(&span))
+		spans[span.Name] = span
+	}
+	return spans
+}
+
+func TestPropagation(t *testing.T) {
+	var buf bytes.Buffer
+	shutdown, err := tracing.Init(context.Background(), tracing.Config{
+		ServiceName: "test",
+		Exporter:    tracing.ExporterStdout,
+		Writer:      &buf,
+	})
+	require.NoError(t, err)
+
+	ctx, publish := tracing.Start(context.Background(), "publish")
+	carrier := propagation.MapCarrier{}
+	tracing.Inject(ctx, carrier)
+	publish.End()
+
+	_, process := tracing.Start(tracing.Extract(context.Background(), carrier), "process")
+	tracing.End(process, errors.New("failed"))
+	require.NoError(t, shutdown(context.Background()))
+
+	spans := decodeSpans(t, &buf)
+	require.Contains(t, spans, "publish")
+	require.Contains(t, spans, "process")
+	require.Equal(t, spans["publish"].SpanContext.TraceID, spans["process"].SpanContext.TraceID)
+	require.Equal(t, spans["publish"].SpanContext.SpanID, spans["process"].Parent.SpanID)
+	require.Equal(t, "Error", spans["process"].Status.Code)
+}
+
+func TestInit(t *testing.T) {
+	shutdown, err := tracing.Init(context.Background(), tracing.Config{Exporter: tracing.ExporterNone})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+
+	_, err = tracing.Init(context.Background(), tracing.Config{Exporter: "zipkin"})
+	require.Error(t, err)
+}
@@ -10,4 +10,15 @@ type Event struct {
 	EndTime      time.Time
 	UserID       string
 	NotifyBefore int
+	RRule        string
+	ExDates      []time.Time
+	TimeZone     string
+	AllDay       bool
+	Version      int64
+}
+
+type Attendee struct {
+	EventID string
+	UserID  string
+	Status  string
 }
@@ -0,0 +1,35 @@
+package types
+
+import "time"
+
+// Interval is the half-open time range [Start, End).
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// WorkingHours limits meeting slots to a daily clock range in a time zone.
+// Start and End are offsets from local midnight; empty Weekdays means every day.
+type WorkingHours struct {
+	Start    time.Duration
+	End      time.Duration
+	Location *time.Location
+	Weekdays []time.Weekday
+}
+
+type FreeBusyQuery struct {
+	UserIDs      []string
+	From         time.Time
+	To           time.Time
+	Duration     time.Duration
+	Step         time.Duration
+	Limit        int
+	WorkingHours *WorkingHours
+}
+
+// FreeBusy holds the merged busy intervals of every requested user and
+// the slots in which all of them are free, soonest first.
+type FreeBusy struct {
+	Busy  map[string][]Interval
+	Slots []Interval
+}
@@ -0,0 +1,29 @@
+package types
+
+import "time"
+
+// Notification is a reminder about one occurrence of an event; State is one of the
+// storagecommon.Notification* states. Statuses is the delivery history reported by the sender.
+type Notification struct {
+	ID          string
+	EventID     string
+	UserID      string
+	Title       string
+	Description string
+	Occurrence  time.Time
+	NotifyAt    time.Time
+	TimeZone    string
+	AllDay      bool
+	State       string
+	UpdatedAt   time.Time
+	Statuses    []NotificationStatus
+}
+
+// NotificationStatus is a delivery outcome the sender reported for a notification.
+type NotificationStatus struct {
+	NotificationID string
+	EventID        string
+	UserID         string
+	Status         string
+	ReportedAt     time.Time
+}
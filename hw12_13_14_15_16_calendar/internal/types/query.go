@@ -0,0 +1,17 @@
+package types
+
+import "time"
+
+type ListEventsQuery struct {
+	UserID    string
+	Title     string
+	From      time.Time
+	To        time.Time
+	PageSize  int
+	PageToken string
+}
+
+type EventPage struct {
+	Events        []Event
+	NextPageToken string
+}
@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS rrule TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS exdates TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_user_recurring ON events(user_id) WHERE rrule <> '';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_user_recurring;
+ALTER TABLE events
+    DROP COLUMN IF EXISTS exdates,
+    DROP COLUMN IF EXISTS rrule;
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS attendees (
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id VARCHAR NOT NULL,
+    status VARCHAR NOT NULL DEFAULT 'needs-action',
+    PRIMARY KEY (event_id, user_id),
+    CONSTRAINT valid_status CHECK (status IN ('needs-action', 'accepted', 'declined', 'tentative'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_attendees_user ON attendees(user_id, status);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_attendees_user;
+DROP TABLE IF EXISTS attendees;
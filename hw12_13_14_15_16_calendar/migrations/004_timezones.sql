@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS time_zone TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS all_day BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE events
+    DROP COLUMN IF EXISTS all_day,
+    DROP COLUMN IF EXISTS time_zone;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+-- +goose Down
+ALTER TABLE events
+    DROP COLUMN IF EXISTS version;
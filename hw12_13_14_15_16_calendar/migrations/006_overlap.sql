@@ -0,0 +1,45 @@
+-- +goose Up
+-- The constraint rejects overlapping single events of a user, so that concurrent inserts cannot both pass
+-- the overlap check of the storage. Recurring events are not covered: their occurrences are not rows, and
+-- the overlaps of a series are only caught by the check of the storage, which runs in a SERIALIZABLE
+-- transaction that PostgreSQL aborts, and the storage retries, when a concurrent one conflicts with it.
+--
+-- Overlapping single events created before the constraint make adding it fail. The migration reports them
+-- first; move or delete one event of every reported pair and run the migration again.
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- +goose StatementBegin
+DO $$
+DECLARE
+    total INTEGER;
+    sample TEXT;
+BEGIN
+    SELECT count(*), string_agg(pair, ', ')
+    INTO total, sample
+    FROM (
+        SELECT a.id || ' and ' || b.id AS pair
+        FROM events a
+        JOIN events b ON a.user_id = b.user_id AND a.id < b.id
+            AND tstzrange(a.start_time, a.end_time) && tstzrange(b.start_time, b.end_time)
+        WHERE a.rrule = '' AND b.rrule = ''
+        ORDER BY a.id, b.id
+        LIMIT 10
+    ) pairs;
+
+    IF total > 0 THEN
+        RAISE EXCEPTION 'cannot add events_no_overlap: single events overlap, first pairs: %', sample
+            USING HINT = 'Move or delete one event of every pair and run the migration again.';
+    END IF;
+END
+$$;
+-- +goose StatementEnd
+
+ALTER TABLE events
+    ADD CONSTRAINT events_no_overlap EXCLUDE USING gist (
+        user_id WITH =,
+        tstzrange(start_time, end_time) WITH &&
+    ) WHERE (rrule = '');
+
+-- +goose Down
+ALTER TABLE events
+    DROP CONSTRAINT IF EXISTS events_no_overlap;
@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY,
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id VARCHAR NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    occurrence TIMESTAMPTZ NOT NULL,
+    notify_at TIMESTAMPTZ NOT NULL,
+    time_zone VARCHAR NOT NULL DEFAULT '',
+    all_day BOOLEAN NOT NULL DEFAULT FALSE,
+    state VARCHAR NOT NULL DEFAULT 'pending',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT notifications_occurrence UNIQUE (event_id, occurrence),
+    CONSTRAINT valid_state CHECK (state IN ('pending', 'published', 'delivered'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_pending ON notifications(notify_at, id) WHERE state = 'pending';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_notifications_pending;
+DROP TABLE IF EXISTS notifications;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS notification_statuses (
+    notification_id UUID NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL,
+    user_id VARCHAR NOT NULL,
+    status VARCHAR NOT NULL,
+    reported_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (notification_id, status, reported_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_statuses_event ON notification_statuses(event_id, reported_at);
+CREATE INDEX IF NOT EXISTS idx_notifications_published ON notifications(updated_at) WHERE state = 'published';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_notifications_published;
+DROP INDEX IF EXISTS idx_notification_statuses_event;
+DROP TABLE IF EXISTS notification_statuses;
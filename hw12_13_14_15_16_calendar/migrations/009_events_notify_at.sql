@@ -0,0 +1,22 @@
+-- +goose Up
+-- A generated column needs an IMMUTABLE expression, while timestamptz - interval is only STABLE: the day
+-- and month parts of an interval are applied in the TimeZone of the session. notify_before is stored as
+-- whole seconds and make_interval(secs => ...) has neither part, so the result does not depend on the
+-- zone, which is what makes declaring event_notify_at IMMUTABLE correct.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION event_notify_at(start_time TIMESTAMPTZ, notify_before INTEGER)
+    RETURNS TIMESTAMPTZ
+    LANGUAGE sql IMMUTABLE
+AS $$ SELECT start_time - make_interval(secs => COALESCE(notify_before, 0)) $$;
+-- +goose StatementEnd
+
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS notify_at TIMESTAMPTZ GENERATED ALWAYS AS (event_notify_at(start_time, notify_before)) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_events_notify_at ON events(notify_at) WHERE rrule = '';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_events_notify_at;
+ALTER TABLE events
+    DROP COLUMN IF EXISTS notify_at;
+DROP FUNCTION IF EXISTS event_notify_at(TIMESTAMPTZ, INTEGER);
@@ -0,0 +1,36 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS events (
+    id TEXT PRIMARY KEY,
+    title TEXT NOT NULL,
+    start_time DATETIME NOT NULL,
+    end_time DATETIME NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    user_id TEXT NOT NULL,
+    notify_before INTEGER NOT NULL DEFAULT 0,
+    rrule TEXT NOT NULL DEFAULT '',
+    exdates TEXT NOT NULL DEFAULT '',
+    time_zone TEXT NOT NULL DEFAULT '',
+    all_day BOOLEAN NOT NULL DEFAULT FALSE,
+    version INTEGER NOT NULL DEFAULT 1,
+    CONSTRAINT valid_time CHECK (end_time > start_time)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_start ON events(user_id, start_time);
+CREATE INDEX IF NOT EXISTS idx_start ON events(start_time, id);
+
+CREATE TABLE IF NOT EXISTS attendees (
+    event_id TEXT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'needs-action',
+    PRIMARY KEY (event_id, user_id),
+    CONSTRAINT valid_status CHECK (status IN ('needs-action', 'accepted', 'declined', 'tentative'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_attendees_user ON attendees(user_id, status);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_attendees_user;
+DROP TABLE IF EXISTS attendees;
+DROP INDEX IF EXISTS idx_start;
+DROP INDEX IF EXISTS idx_user_start;
+DROP TABLE IF EXISTS events;
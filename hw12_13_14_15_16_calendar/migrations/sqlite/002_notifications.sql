@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS notifications (
+    id TEXT PRIMARY KEY,
+    event_id TEXT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    occurrence DATETIME NOT NULL,
+    notify_at DATETIME NOT NULL,
+    time_zone TEXT NOT NULL DEFAULT '',
+    all_day BOOLEAN NOT NULL DEFAULT FALSE,
+    state TEXT NOT NULL DEFAULT 'pending',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT notifications_occurrence UNIQUE (event_id, occurrence),
+    CONSTRAINT valid_state CHECK (state IN ('pending', 'published', 'delivered'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_pending ON notifications(notify_at, id) WHERE state = 'pending';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_notifications_pending;
+DROP TABLE IF EXISTS notifications;
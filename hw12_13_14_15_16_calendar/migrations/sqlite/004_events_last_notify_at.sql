@@ -0,0 +1,11 @@
+-- +goose Up
+-- The reminder time of the last occurrence in Unix seconds bounds a series in ListDueOccurrences, so that
+-- ended series are not loaded and expanded on every tick. The storage writes it with the event; it stays
+-- NULL for open-ended series, and the storage fills it in for bounded series created before on Migrate.
+ALTER TABLE events ADD COLUMN last_notify_at INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_series_last_notify_at ON events(last_notify_at) WHERE rrule <> '';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_series_last_notify_at;
+ALTER TABLE events DROP COLUMN last_notify_at;
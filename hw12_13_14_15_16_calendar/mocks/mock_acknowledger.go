@@ -0,0 +1,76 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: delivery.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAcknowledger is a mock of Acknowledger interface.
+type MockAcknowledger struct {
+	ctrl     *gomock.Controller
+	recorder *MockAcknowledgerMockRecorder
+}
+
+// MockAcknowledgerMockRecorder is the mock recorder for MockAcknowledger.
+type MockAcknowledgerMockRecorder struct {
+	mock *MockAcknowledger
+}
+
+// NewMockAcknowledger creates a new mock instance.
+func NewMockAcknowledger(ctrl *gomock.Controller) *MockAcknowledger {
+	mock := &MockAcknowledger{ctrl: ctrl}
+	mock.recorder = &MockAcknowledgerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAcknowledger) EXPECT() *MockAcknowledgerMockRecorder {
+	return m.recorder
+}
+
+// Ack mocks base method.
+func (m *MockAcknowledger) Ack() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ack")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ack indicates an expected call of Ack.
+func (mr *MockAcknowledgerMockRecorder) Ack() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ack", reflect.TypeOf((*MockAcknowledger)(nil).Ack))
+}
+
+// DeadLetter mocks base method.
+func (m *MockAcknowledger) DeadLetter(reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeadLetter", reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeadLetter indicates an expected call of DeadLetter.
+func (mr *MockAcknowledgerMockRecorder) DeadLetter(reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeadLetter", reflect.TypeOf((*MockAcknowledger)(nil).DeadLetter), reason)
+}
+
+// Retry mocks base method.
+func (m *MockAcknowledger) Retry(reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Retry", reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Retry indicates an expected call of Retry.
+func (mr *MockAcknowledgerMockRecorder) Retry(reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retry", reflect.TypeOf((*MockAcknowledger)(nil).Retry), reason)
+}
@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 	time "time"
 
+	changefeed "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/changefeed"
 	types "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/types"
 	gomock "github.com/golang/mock/gomock"
 )
@@ -52,17 +53,17 @@ func (mr *MockApplicationMockRecorder) CreateEvent(arg0, arg1 interface{}) *gomo
 }
 
 // DeleteEvent mocks base method.
-func (m *MockApplication) DeleteEvent(arg0 context.Context, arg1 string) error {
+func (m *MockApplication) DeleteEvent(ctx context.Context, id string, version int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteEvent", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeleteEvent", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteEvent indicates an expected call of DeleteEvent.
-func (mr *MockApplicationMockRecorder) DeleteEvent(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockApplicationMockRecorder) DeleteEvent(ctx, id, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEvent", reflect.TypeOf((*MockApplication)(nil).DeleteEvent), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEvent", reflect.TypeOf((*MockApplication)(nil).DeleteEvent), ctx, id, version)
 }
 
 // DeleteOlderThan mocks base method.
@@ -79,6 +80,21 @@ func (mr *MockApplicationMockRecorder) DeleteOlderThan(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOlderThan", reflect.TypeOf((*MockApplication)(nil).DeleteOlderThan), arg0, arg1)
 }
 
+// FreeBusy mocks base method.
+func (m *MockApplication) FreeBusy(arg0 context.Context, arg1 types.FreeBusyQuery) (types.FreeBusy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FreeBusy", arg0, arg1)
+	ret0, _ := ret[0].(types.FreeBusy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FreeBusy indicates an expected call of FreeBusy.
+func (mr *MockApplicationMockRecorder) FreeBusy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreeBusy", reflect.TypeOf((*MockApplication)(nil).FreeBusy), arg0, arg1)
+}
+
 // GetEventByID mocks base method.
 func (m *MockApplication) GetEventByID(arg0 context.Context, arg1 string) (types.Event, error) {
 	m.ctrl.T.Helper()
@@ -94,19 +110,48 @@ func (mr *MockApplicationMockRecorder) GetEventByID(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventByID", reflect.TypeOf((*MockApplication)(nil).GetEventByID), arg0, arg1)
 }
 
+// InviteAttendee mocks base method.
+func (m *MockApplication) InviteAttendee(arg0 context.Context, arg1 types.Attendee) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InviteAttendee", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InviteAttendee indicates an expected call of InviteAttendee.
+func (mr *MockApplicationMockRecorder) InviteAttendee(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InviteAttendee", reflect.TypeOf((*MockApplication)(nil).InviteAttendee), arg0, arg1)
+}
+
+// ListAttendees mocks base method.
+func (m *MockApplication) ListAttendees(ctx context.Context, eventID string) ([]types.Attendee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAttendees", ctx, eventID)
+	ret0, _ := ret[0].([]types.Attendee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAttendees indicates an expected call of ListAttendees.
+func (mr *MockApplicationMockRecorder) ListAttendees(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAttendees", reflect.TypeOf((*MockApplication)(nil).ListAttendees), ctx, eventID)
+}
+
 // ListEvents mocks base method.
-func (m *MockApplication) ListEvents(arg0 context.Context) ([]types.Event, error) {
+func (m *MockApplication) ListEvents(arg0 context.Context, arg1 types.ListEventsQuery) (types.EventPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListEvents", arg0)
-	ret0, _ := ret[0].([]types.Event)
+	ret := m.ctrl.Call(m, "ListEvents", arg0, arg1)
+	ret0, _ := ret[0].(types.EventPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListEvents indicates an expected call of ListEvents.
-func (mr *MockApplicationMockRecorder) ListEvents(arg0 interface{}) *gomock.Call {
+func (mr *MockApplicationMockRecorder) ListEvents(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEvents", reflect.TypeOf((*MockApplication)(nil).ListEvents), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEvents", reflect.TypeOf((*MockApplication)(nil).ListEvents), arg0, arg1)
 }
 
 // ListEventsByUser mocks base method.
@@ -139,31 +184,133 @@ func (mr *MockApplicationMockRecorder) ListEventsByUserInRange(arg0, arg1, arg2,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEventsByUserInRange", reflect.TypeOf((*MockApplication)(nil).ListEventsByUserInRange), arg0, arg1, arg2, arg3)
 }
 
-// ListEventsDueBefore mocks base method.
-func (m *MockApplication) ListEventsDueBefore(arg0 context.Context, arg1 time.Time) ([]types.Event, error) {
+// ListNotifications mocks base method.
+func (m *MockApplication) ListNotifications(ctx context.Context, eventID string) ([]types.Notification, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListEventsDueBefore", arg0, arg1)
-	ret0, _ := ret[0].([]types.Event)
+	ret := m.ctrl.Call(m, "ListNotifications", ctx, eventID)
+	ret0, _ := ret[0].([]types.Notification)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListEventsDueBefore indicates an expected call of ListEventsDueBefore.
-func (mr *MockApplicationMockRecorder) ListEventsDueBefore(arg0, arg1 interface{}) *gomock.Call {
+// ListNotifications indicates an expected call of ListNotifications.
+func (mr *MockApplicationMockRecorder) ListNotifications(ctx, eventID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEventsDueBefore", reflect.TypeOf((*MockApplication)(nil).ListEventsDueBefore), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotifications", reflect.TypeOf((*MockApplication)(nil).ListNotifications), ctx, eventID)
 }
 
-// UpdateEvent mocks base method.
-func (m *MockApplication) UpdateEvent(arg0 context.Context, arg1 types.Event) error {
+// MarkNotificationPublished mocks base method.
+func (m *MockApplication) MarkNotificationPublished(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateEvent", arg0, arg1)
+	ret := m.ctrl.Call(m, "MarkNotificationPublished", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkNotificationPublished indicates an expected call of MarkNotificationPublished.
+func (mr *MockApplicationMockRecorder) MarkNotificationPublished(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationPublished", reflect.TypeOf((*MockApplication)(nil).MarkNotificationPublished), ctx, id)
+}
+
+// PendingNotifications mocks base method.
+func (m *MockApplication) PendingNotifications(ctx context.Context, resendBefore time.Time, limit int) ([]types.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PendingNotifications", ctx, resendBefore, limit)
+	ret0, _ := ret[0].([]types.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PendingNotifications indicates an expected call of PendingNotifications.
+func (mr *MockApplicationMockRecorder) PendingNotifications(ctx, resendBefore, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingNotifications", reflect.TypeOf((*MockApplication)(nil).PendingNotifications), ctx, resendBefore, limit)
+}
+
+// RecordNotificationStatus mocks base method.
+func (m *MockApplication) RecordNotificationStatus(ctx context.Context, status types.NotificationStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordNotificationStatus", ctx, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordNotificationStatus indicates an expected call of RecordNotificationStatus.
+func (mr *MockApplicationMockRecorder) RecordNotificationStatus(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordNotificationStatus", reflect.TypeOf((*MockApplication)(nil).RecordNotificationStatus), ctx, status)
+}
+
+// RemoveAttendee mocks base method.
+func (m *MockApplication) RemoveAttendee(ctx context.Context, eventID, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAttendee", ctx, eventID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAttendee indicates an expected call of RemoveAttendee.
+func (mr *MockApplicationMockRecorder) RemoveAttendee(ctx, eventID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAttendee", reflect.TypeOf((*MockApplication)(nil).RemoveAttendee), ctx, eventID, userID)
+}
+
+// RespondToInvitation mocks base method.
+func (m *MockApplication) RespondToInvitation(arg0 context.Context, arg1 types.Attendee) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RespondToInvitation", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// RespondToInvitation indicates an expected call of RespondToInvitation.
+func (mr *MockApplicationMockRecorder) RespondToInvitation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondToInvitation", reflect.TypeOf((*MockApplication)(nil).RespondToInvitation), arg0, arg1)
+}
+
+// ScheduleNotifications mocks base method.
+func (m *MockApplication) ScheduleNotifications(ctx context.Context, from, to time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleNotifications", ctx, from, to)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScheduleNotifications indicates an expected call of ScheduleNotifications.
+func (mr *MockApplicationMockRecorder) ScheduleNotifications(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleNotifications", reflect.TypeOf((*MockApplication)(nil).ScheduleNotifications), ctx, from, to)
+}
+
+// UpdateEvent mocks base method.
+func (m *MockApplication) UpdateEvent(arg0 context.Context, arg1 types.Event) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEvent", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // UpdateEvent indicates an expected call of UpdateEvent.
 func (mr *MockApplicationMockRecorder) UpdateEvent(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEvent", reflect.TypeOf((*MockApplication)(nil).UpdateEvent), arg0, arg1)
 }
+
+// WatchEvents mocks base method.
+func (m *MockApplication) WatchEvents(arg0 context.Context, arg1, arg2 string) (*changefeed.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchEvents", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*changefeed.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchEvents indicates an expected call of WatchEvents.
+func (mr *MockApplicationMockRecorder) WatchEvents(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchEvents", reflect.TypeOf((*MockApplication)(nil).WatchEvents), arg0, arg1, arg2)
+}
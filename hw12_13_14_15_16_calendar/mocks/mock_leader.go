@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: leader.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLeaderLock is a mock of LeaderLock interface.
+type MockLeaderLock struct {
+	ctrl     *gomock.Controller
+	recorder *MockLeaderLockMockRecorder
+}
+
+// MockLeaderLockMockRecorder is the mock recorder for MockLeaderLock.
+type MockLeaderLockMockRecorder struct {
+	mock *MockLeaderLock
+}
+
+// NewMockLeaderLock creates a new mock instance.
+func NewMockLeaderLock(ctrl *gomock.Controller) *MockLeaderLock {
+	mock := &MockLeaderLock{ctrl: ctrl}
+	mock.recorder = &MockLeaderLockMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLeaderLock) EXPECT() *MockLeaderLockMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockLeaderLock) Check(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockLeaderLockMockRecorder) Check(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockLeaderLock)(nil).Check), ctx)
+}
+
+// TryLock mocks base method.
+func (m *MockLeaderLock) TryLock(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TryLock", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TryLock indicates an expected call of TryLock.
+func (mr *MockLeaderLockMockRecorder) TryLock(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TryLock", reflect.TypeOf((*MockLeaderLock)(nil).TryLock), ctx)
+}
+
+// Unlock mocks base method.
+func (m *MockLeaderLock) Unlock(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockLeaderLockMockRecorder) Unlock(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockLeaderLock)(nil).Unlock), ctx)
+}
+
+// MockLeader is a mock of Leader interface.
+type MockLeader struct {
+	ctrl     *gomock.Controller
+	recorder *MockLeaderMockRecorder
+}
+
+// MockLeaderMockRecorder is the mock recorder for MockLeader.
+type MockLeaderMockRecorder struct {
+	mock *MockLeader
+}
+
+// NewMockLeader creates a new mock instance.
+func NewMockLeader(ctrl *gomock.Controller) *MockLeader {
+	mock := &MockLeader{ctrl: ctrl}
+	mock.recorder = &MockLeaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLeader) EXPECT() *MockLeaderMockRecorder {
+	return m.recorder
+}
+
+// IsLeader mocks base method.
+func (m *MockLeader) IsLeader() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsLeader")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsLeader indicates an expected call of IsLeader.
+func (mr *MockLeaderMockRecorder) IsLeader() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsLeader", reflect.TypeOf((*MockLeader)(nil).IsLeader))
+}
@@ -5,8 +5,10 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
+	rmq "github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/rmq"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -48,10 +50,10 @@ func (mr *MockRmqClientMockRecorder) Close() *gomock.Call {
 }
 
 // Consume mocks base method.
-func (m *MockRmqClient) Consume(queueName string) (<-chan []byte, error) {
+func (m *MockRmqClient) Consume(queueName string) (<-chan rmq.Delivery, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Consume", queueName)
-	ret0, _ := ret[0].(<-chan []byte)
+	ret0, _ := ret[0].(<-chan rmq.Delivery)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -63,15 +65,29 @@ func (mr *MockRmqClientMockRecorder) Consume(queueName interface{}) *gomock.Call
 }
 
 // Publish mocks base method.
-func (m *MockRmqClient) Publish(routingKey string, body []byte) error {
+func (m *MockRmqClient) Publish(ctx context.Context, routingKey string, body []byte) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Publish", routingKey, body)
+	ret := m.ctrl.Call(m, "Publish", ctx, routingKey, body)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Publish indicates an expected call of Publish.
-func (mr *MockRmqClientMockRecorder) Publish(routingKey, body interface{}) *gomock.Call {
+func (mr *MockRmqClientMockRecorder) Publish(ctx, routingKey, body interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockRmqClient)(nil).Publish), routingKey, body)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockRmqClient)(nil).Publish), ctx, routingKey, body)
+}
+
+// State mocks base method.
+func (m *MockRmqClient) State() rmq.State {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "State")
+	ret0, _ := ret[0].(rmq.State)
+	return ret0
+}
+
+// State indicates an expected call of State.
+func (mr *MockRmqClientMockRecorder) State() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "State", reflect.TypeOf((*MockRmqClient)(nil).State))
 }
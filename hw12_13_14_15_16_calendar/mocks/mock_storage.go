@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 	time "time"
 
@@ -35,119 +36,310 @@ func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
 	return m.recorder
 }
 
+// AddAttendee mocks base method.
+func (m *MockStorage) AddAttendee(ctx context.Context, attendee storagecommon.Attendee) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAttendee", ctx, attendee)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAttendee indicates an expected call of AddAttendee.
+func (mr *MockStorageMockRecorder) AddAttendee(ctx, attendee interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAttendee", reflect.TypeOf((*MockStorage)(nil).AddAttendee), ctx, attendee)
+}
+
+// AddNotificationStatus mocks base method.
+func (m *MockStorage) AddNotificationStatus(ctx context.Context, status storagecommon.NotificationStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddNotificationStatus", ctx, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddNotificationStatus indicates an expected call of AddNotificationStatus.
+func (mr *MockStorageMockRecorder) AddNotificationStatus(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNotificationStatus", reflect.TypeOf((*MockStorage)(nil).AddNotificationStatus), ctx, status)
+}
+
+// AddNotifications mocks base method.
+func (m *MockStorage) AddNotifications(ctx context.Context, notifications []storagecommon.Notification) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddNotifications", ctx, notifications)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddNotifications indicates an expected call of AddNotifications.
+func (mr *MockStorageMockRecorder) AddNotifications(ctx, notifications interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNotifications", reflect.TypeOf((*MockStorage)(nil).AddNotifications), ctx, notifications)
+}
+
 // Create mocks base method.
-func (m *MockStorage) Create(event storagecommon.Event) (string, error) {
+func (m *MockStorage) Create(ctx context.Context, event storagecommon.Event) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", event)
+	ret := m.ctrl.Call(m, "Create", ctx, event)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockStorageMockRecorder) Create(event interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) Create(ctx, event interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockStorage)(nil).Create), event)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockStorage)(nil).Create), ctx, event)
 }
 
 // Delete mocks base method.
-func (m *MockStorage) Delete(id string) error {
+func (m *MockStorage) Delete(ctx context.Context, id string, version int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockStorageMockRecorder) Delete(id interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) Delete(ctx, id, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStorage)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStorage)(nil).Delete), ctx, id, version)
 }
 
 // DeleteOlder mocks base method.
-func (m *MockStorage) DeleteOlder(t time.Time) error {
+func (m *MockStorage) DeleteOlder(ctx context.Context, t time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteOlder", t)
+	ret := m.ctrl.Call(m, "DeleteOlder", ctx, t)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteOlder indicates an expected call of DeleteOlder.
-func (mr *MockStorageMockRecorder) DeleteOlder(t interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) DeleteOlder(ctx, t interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOlder", reflect.TypeOf((*MockStorage)(nil).DeleteOlder), t)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOlder", reflect.TypeOf((*MockStorage)(nil).DeleteOlder), ctx, t)
 }
 
 // GetByID mocks base method.
-func (m *MockStorage) GetByID(id string) (storagecommon.Event, error) {
+func (m *MockStorage) GetByID(ctx context.Context, id string) (storagecommon.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", id)
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
 	ret0, _ := ret[0].(storagecommon.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockStorageMockRecorder) GetByID(id interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockStorage)(nil).GetByID), ctx, id)
+}
+
+// GetNotification mocks base method.
+func (m *MockStorage) GetNotification(ctx context.Context, id string) (storagecommon.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotification", ctx, id)
+	ret0, _ := ret[0].(storagecommon.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotification indicates an expected call of GetNotification.
+func (mr *MockStorageMockRecorder) GetNotification(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockStorage)(nil).GetByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotification", reflect.TypeOf((*MockStorage)(nil).GetNotification), ctx, id)
 }
 
 // List mocks base method.
-func (m *MockStorage) List() ([]storagecommon.Event, error) {
+func (m *MockStorage) List(ctx context.Context) ([]storagecommon.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List")
+	ret := m.ctrl.Call(m, "List", ctx)
 	ret0, _ := ret[0].([]storagecommon.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MockStorageMockRecorder) List() *gomock.Call {
+func (mr *MockStorageMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockStorage)(nil).List), ctx)
+}
+
+// ListAttendees mocks base method.
+func (m *MockStorage) ListAttendees(ctx context.Context, eventID string) ([]storagecommon.Attendee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAttendees", ctx, eventID)
+	ret0, _ := ret[0].([]storagecommon.Attendee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAttendees indicates an expected call of ListAttendees.
+func (mr *MockStorageMockRecorder) ListAttendees(ctx, eventID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockStorage)(nil).List))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAttendees", reflect.TypeOf((*MockStorage)(nil).ListAttendees), ctx, eventID)
 }
 
 // ListByUser mocks base method.
-func (m *MockStorage) ListByUser(userID string) ([]storagecommon.Event, error) {
+func (m *MockStorage) ListByUser(ctx context.Context, userID string) ([]storagecommon.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListByUser", userID)
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID)
 	ret0, _ := ret[0].([]storagecommon.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListByUser indicates an expected call of ListByUser.
-func (mr *MockStorageMockRecorder) ListByUser(userID interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) ListByUser(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockStorage)(nil).ListByUser), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockStorage)(nil).ListByUser), ctx, userID)
 }
 
 // ListByUserInRange mocks base method.
-func (m *MockStorage) ListByUserInRange(userID string, from, to time.Time) ([]storagecommon.Event, error) {
+func (m *MockStorage) ListByUserInRange(ctx context.Context, userID string, from, to time.Time) ([]storagecommon.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListByUserInRange", userID, from, to)
+	ret := m.ctrl.Call(m, "ListByUserInRange", ctx, userID, from, to)
 	ret0, _ := ret[0].([]storagecommon.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListByUserInRange indicates an expected call of ListByUserInRange.
-func (mr *MockStorageMockRecorder) ListByUserInRange(userID, from, to interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) ListByUserInRange(ctx, userID, from, to interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUserInRange", reflect.TypeOf((*MockStorage)(nil).ListByUserInRange), userID, from, to)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUserInRange", reflect.TypeOf((*MockStorage)(nil).ListByUserInRange), ctx, userID, from, to)
 }
 
-// Update mocks base method.
-func (m *MockStorage) Update(event storagecommon.Event) error {
+// ListDueOccurrences mocks base method.
+func (m *MockStorage) ListDueOccurrences(ctx context.Context, from, to time.Time) ([]storagecommon.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", event)
+	ret := m.ctrl.Call(m, "ListDueOccurrences", ctx, from, to)
+	ret0, _ := ret[0].([]storagecommon.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueOccurrences indicates an expected call of ListDueOccurrences.
+func (mr *MockStorageMockRecorder) ListDueOccurrences(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueOccurrences", reflect.TypeOf((*MockStorage)(nil).ListDueOccurrences), ctx, from, to)
+}
+
+// ListNotificationStatuses mocks base method.
+func (m *MockStorage) ListNotificationStatuses(ctx context.Context, eventID string) ([]storagecommon.NotificationStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotificationStatuses", ctx, eventID)
+	ret0, _ := ret[0].([]storagecommon.NotificationStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotificationStatuses indicates an expected call of ListNotificationStatuses.
+func (mr *MockStorageMockRecorder) ListNotificationStatuses(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotificationStatuses", reflect.TypeOf((*MockStorage)(nil).ListNotificationStatuses), ctx, eventID)
+}
+
+// ListNotifications mocks base method.
+func (m *MockStorage) ListNotifications(ctx context.Context, eventID string) ([]storagecommon.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotifications", ctx, eventID)
+	ret0, _ := ret[0].([]storagecommon.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotifications indicates an expected call of ListNotifications.
+func (mr *MockStorageMockRecorder) ListNotifications(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotifications", reflect.TypeOf((*MockStorage)(nil).ListNotifications), ctx, eventID)
+}
+
+// ListPage mocks base method.
+func (m *MockStorage) ListPage(ctx context.Context, query storagecommon.ListQuery) (storagecommon.EventPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPage", ctx, query)
+	ret0, _ := ret[0].(storagecommon.EventPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPage indicates an expected call of ListPage.
+func (mr *MockStorageMockRecorder) ListPage(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPage", reflect.TypeOf((*MockStorage)(nil).ListPage), ctx, query)
+}
+
+// ListPendingNotifications mocks base method.
+func (m *MockStorage) ListPendingNotifications(ctx context.Context, resendBefore time.Time, limit int) ([]storagecommon.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingNotifications", ctx, resendBefore, limit)
+	ret0, _ := ret[0].([]storagecommon.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingNotifications indicates an expected call of ListPendingNotifications.
+func (mr *MockStorageMockRecorder) ListPendingNotifications(ctx, resendBefore, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingNotifications", reflect.TypeOf((*MockStorage)(nil).ListPendingNotifications), ctx, resendBefore, limit)
+}
+
+// RemoveAttendee mocks base method.
+func (m *MockStorage) RemoveAttendee(ctx context.Context, eventID, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAttendee", ctx, eventID, userID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// RemoveAttendee indicates an expected call of RemoveAttendee.
+func (mr *MockStorageMockRecorder) RemoveAttendee(ctx, eventID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAttendee", reflect.TypeOf((*MockStorage)(nil).RemoveAttendee), ctx, eventID, userID)
+}
+
+// SetNotificationState mocks base method.
+func (m *MockStorage) SetNotificationState(ctx context.Context, id, state string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNotificationState", ctx, id, state, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetNotificationState indicates an expected call of SetNotificationState.
+func (mr *MockStorageMockRecorder) SetNotificationState(ctx, id, state, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNotificationState", reflect.TypeOf((*MockStorage)(nil).SetNotificationState), ctx, id, state, at)
+}
+
+// Update mocks base method.
+func (m *MockStorage) Update(ctx context.Context, event storagecommon.Event) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, event)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // Update indicates an expected call of Update.
-func (mr *MockStorageMockRecorder) Update(event interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) Update(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockStorage)(nil).Update), ctx, event)
+}
+
+// UpdateAttendee mocks base method.
+func (m *MockStorage) UpdateAttendee(ctx context.Context, attendee storagecommon.Attendee) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAttendee", ctx, attendee)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAttendee indicates an expected call of UpdateAttendee.
+func (mr *MockStorageMockRecorder) UpdateAttendee(ctx, attendee interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockStorage)(nil).Update), event)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAttendee", reflect.TypeOf((*MockStorage)(nil).UpdateAttendee), ctx, attendee)
 }
@@ -21,6 +21,58 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ChangeType int32
+
+const (
+	ChangeType_CHANGE_TYPE_UNSPECIFIED ChangeType = 0
+	ChangeType_CHANGE_TYPE_CREATED     ChangeType = 1
+	ChangeType_CHANGE_TYPE_UPDATED     ChangeType = 2
+	ChangeType_CHANGE_TYPE_DELETED     ChangeType = 3
+)
+
+// Enum value maps for ChangeType.
+var (
+	ChangeType_name = map[int32]string{
+		0: "CHANGE_TYPE_UNSPECIFIED",
+		1: "CHANGE_TYPE_CREATED",
+		2: "CHANGE_TYPE_UPDATED",
+		3: "CHANGE_TYPE_DELETED",
+	}
+	ChangeType_value = map[string]int32{
+		"CHANGE_TYPE_UNSPECIFIED": 0,
+		"CHANGE_TYPE_CREATED":     1,
+		"CHANGE_TYPE_UPDATED":     2,
+		"CHANGE_TYPE_DELETED":     3,
+	}
+)
+
+func (x ChangeType) Enum() *ChangeType {
+	p := new(ChangeType)
+	*p = x
+	return p
+}
+
+func (x ChangeType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChangeType) Descriptor() protoreflect.EnumDescriptor {
+	return file_calendar_calendar_proto_enumTypes[0].Descriptor()
+}
+
+func (ChangeType) Type() protoreflect.EnumType {
+	return &file_calendar_calendar_proto_enumTypes[0]
+}
+
+func (x ChangeType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChangeType.Descriptor instead.
+func (ChangeType) EnumDescriptor() ([]byte, []int) {
+	return file_calendar_calendar_proto_rawDescGZIP(), []int{0}
+}
+
 type CreateEventResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -76,6 +128,7 @@ func (x *CreateEventResponse) GetSuccess() bool {
 type UpdateEventResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Version       int64                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -117,9 +170,18 @@ func (x *UpdateEventResponse) GetSuccess() bool {
 	return false
 }
 
+func (x *UpdateEventResponse) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 type DeleteEventRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Current version of the event.
+	Version       int64 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -161,6 +223,13 @@ func (x *DeleteEventRequest) GetId() string {
 	return ""
 }
 
+func (x *DeleteEventRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 type DeleteEventResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
@@ -295,6 +364,11 @@ func (x *GetEventByIDResponse) GetEvent() *Event {
 
 type ListEventsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	From          int64                  `protobuf:"varint,4,opt,name=from,proto3" json:"from,omitempty"`
+	To            int64                  `protobuf:"varint,5,opt,name=to,proto3" json:"to,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -329,9 +403,45 @@ func (*ListEventsRequest) Descriptor() ([]byte, []int) {
 	return file_calendar_calendar_proto_rawDescGZIP(), []int{6}
 }
 
+func (x *ListEventsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListEventsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListEventsRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ListEventsRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *ListEventsRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
 type ListEventsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Events        []*Event               `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -373,6 +483,13 @@ func (x *ListEventsResponse) GetEvents() []*Event {
 	return nil
 }
 
+func (x *ListEventsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
 type ListEventsByUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -477,33 +594,1304 @@ func (x *ListEventsByUserInRangeRequest) GetTo() int64 {
 	return 0
 }
 
-var File_calendar_calendar_proto protoreflect.FileDescriptor
+type ExportEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	From          int64                  `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            int64                  `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_calendar_calendar_proto_rawDesc = "" +
-	"\n" +
-	"\x17calendar/calendar.proto\x12\bcalendar\x1a\x15calendar/events.proto\"?\n" +
-	"\x13CreateEventResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
-	"\asuccess\x18\x02 \x01(\bR\asuccess\"/\n" +
-	"\x13UpdateEventResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"$\n" +
-	"\x12DeleteEventRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"/\n" +
-	"\x13DeleteEventResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"%\n" +
-	"\x13GetEventByIDRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"=\n" +
-	"\x14GetEventByIDResponse\x12%\n" +
-	"\x05event\x18\x01 \x01(\v2\x0f.calendar.EventR\x05event\"\x13\n" +
-	"\x11ListEventsRequest\"=\n" +
-	"\x12ListEventsResponse\x12'\n" +
-	"\x06events\x18\x01 \x03(\v2\x0f.calendar.EventR\x06events\"2\n" +
-	"\x17ListEventsByUserRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\"]\n" +
-	"\x1eListEventsByUserInRangeRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
-	"\x04from\x18\x02 \x01(\x03R\x04from\x12\x0e\n" +
-	"\x02to\x18\x03 \x01(\x03R\x02to2\xab\x04\n" +
+func (x *ExportEventsRequest) Reset() {
+	*x = ExportEventsRequest{}
+	mi := &file_calendar_calendar_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportEventsRequest) ProtoMessage() {}
+
+func (x *ExportEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_calendar_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportEventsRequest.ProtoReflect.Descriptor instead.
+func (*ExportEventsRequest) Descriptor() ([]byte, []int) {
+	return file_calendar_calendar_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ExportEventsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ExportEventsRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *ExportEventsRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+type ExportEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Calendar      string                 `protobuf:"bytes,1,opt,name=calendar,proto3" json:"calendar,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportEventsResponse) Reset() {
+	*x = ExportEventsResponse{}
+	mi := &file_calendar_calendar_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportEventsResponse) ProtoMessage() {}
+
+func (x *ExportEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_calendar_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportEventsResponse.ProtoReflect.Descriptor instead.
+func (*ExportEventsResponse) Descriptor() ([]byte, []int) {
+	return file_calendar_calendar_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ExportEventsResponse) GetCalendar() string {
+	if x != nil {
+		return x.Calendar
+	}
+	return ""
+}
+
+type ImportEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Calendar      string                 `protobuf:"bytes,2,opt,name=calendar,proto3" json:"calendar,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportEventsRequest) Reset() {
+	*x = ImportEventsRequest{}
+	mi := &file_calendar_calendar_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportEventsRequest) ProtoMessage() {}
+
+func (x *ImportEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_calendar_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportEventsRequest.ProtoReflect.Descriptor instead.
+func (*ImportEventsRequest) Descriptor() ([]byte, []int) {
+	return file_calendar_calendar_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ImportEventsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ImportEventsRequest) GetCalendar() string {
+	if x != nil {
+		return x.Calendar
+	}
+	return ""
+}
+
+type ImportEventResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uid           string                 `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportEventResult) Reset() {
+	*x = ImportEventResult{}
+	mi := &file_calendar_calendar_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportEventResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportEventResult) ProtoMessage() {}
+
+func (x *ImportEventResult) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_calendar_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportEventResult.ProtoReflect.Descriptor instead.
+func (*ImportEventResult) Descriptor() ([]byte, []int) {
+	return file_calendar_calendar_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ImportEventResult) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *ImportEventResult) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ImportEventResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ImportEventResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ImportEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Created       int32                  `protobuf:"varint,1,opt,name=created,proto3" json:"created,omitempty"`
+	Failed        int32                  `protobuf:"varint,2,opt,name=failed,proto3" json:"failed,omitempty"`
+	Results       []*ImportEventResult   `protobuf:"bytes,3,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportEventsResponse) Reset() {
+	*x = ImportEventsResponse{}
+	mi := &file_calendar_calendar_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportEventsResponse) ProtoMessage() {}
+
+func (x *ImportEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_calendar_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportEventsResponse.ProtoReflect.Descriptor instead.
+func (*ImportEventsResponse) Descriptor() ([]byte, []int) {
+	return file_calendar_calendar_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ImportEventsResponse) GetCreated() int32 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *ImportEventsResponse) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *ImportEventsResponse) GetResults() []*ImportEventResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type WatchEventsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Revision token of the last change received before reconnecting.
+	Revision      string `protobuf:"bytes,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchEventsRequest) Reset() {
+	*x = WatchEventsRequest{}
+	mi := &file_calendar_calendar_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEventsRequest) ProtoMessage() {}
+
+func (x *WatchEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_calendar_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEventsRequest.ProtoReflect.Descriptor instead.
+func (*WatchEventsRequest) Descriptor() ([]byte, []int) {
+	return file_calendar_calendar_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *WatchEventsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *WatchEventsRequest) GetRevision() string {
+	if x != nil {
+		return x.Revision
+	}
+	return ""
+}
+
+type EventChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          ChangeType             `protobuf:"varint,1,opt,name=type,proto3,enum=calendar.ChangeType" json:"type,omitempty"`
+	Event         *Event                 `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	Revision      string                 `protobuf:"bytes,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventChange) Reset() {
+	*x = EventChange{}
+	mi := &file_calendar_calendar_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventChange) ProtoMessage() {}
+
+func (x *EventChange) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_calendar_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventChange.ProtoReflect.Descriptor instead.
+func (*EventChange) Descriptor() ([]byte, []int) {
+	return file_calendar_calendar_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *EventChange) GetType() ChangeType {
+	if x != nil {
+		return x.Type
+	}
+	return ChangeType_CHANGE_TYPE_UNSPECIFIED
+}
+
+func (x *EventChange) GetEvent() *Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *EventChange) GetRevision() string {
+	if x != nil {
+		return x.Revision
+	}
+	return ""
+}
+
+type InviteAttendeeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InviteAttendeeRequest) Reset() {
+	*x = InviteAttendeeRequest{}
+	mi := &file_calendar_calendar_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InviteAttendeeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InviteAttendeeRequest) ProtoMessage() {}
+
+func (x *InviteAttendeeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_calendar_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InviteAttendeeRequest.ProtoReflect.Descriptor instead.
+func (*InviteAttendeeRequest) Descriptor() ([]byte, []int) {
+	return file_calendar_calendar_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *InviteAttendeeRequest) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *InviteAttendeeRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type InviteAttendeeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InviteAttendeeResponse) Reset() {
+	*x = InviteAttendeeResponse{}
+	mi := &file_calendar_calendar_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InviteAttendeeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InviteAttendeeResponse) ProtoMessage() {}
+
+func (x *InviteAttendeeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_calendar_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InviteAttendeeResponse.ProtoReflect.Descriptor instead.
+func (*InviteAttendeeResponse) Descriptor() ([]byte, []int) {
+	return file_calendar_calendar_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *InviteAttendeeResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type RespondToInvitationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status        AttendeeStatus         `protobuf:"varint,3,opt,name=status,proto3,enum=calendar.AttendeeStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RespondToInvitationRequest) Reset() {
+	*x = RespondToInvitationRequest{}
+	mi := &file_calendar_calendar_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RespondToInvitationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RespondToInvitationRequest) ProtoMessage() {}
+
+func (x *RespondToInvitationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_calendar_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RespondToInvitationRequest.ProtoReflect.Descriptor instead.
+func (*RespondToInvitationRequest) Descriptor() ([]byte, []int) {
+	return file_calendar_calendar_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RespondToInvitationRequest) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *RespondToInvitationRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RespondToInvitationRequest) GetStatus() AttendeeStatus {
+	if x != nil {
+		return x.Status
+	}
+	return AttendeeStatus_ATTENDEE_STATUS_UNSPECIFIED
+}
+
+type RespondToInvitationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RespondToInvitationResponse) Reset() {
+	*x = RespondToInvitationResponse{}
+	mi := &file_calendar_calendar_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RespondToInvitationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RespondToInvitationResponse) ProtoMessage() {}
+
+func (x *RespondToInvitationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_calendar_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RespondToInvitationResponse.ProtoReflect.Descriptor instead.
+func (*RespondToInvitationResponse) Descriptor() ([]byte, []int) {
+	return file_calendar_calendar_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RespondToInvitationResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type RemoveAttendeeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveAttendeeRequest) Reset() {
+	*x = RemoveAttendeeRequest{}
+	mi := &file_calendar_calendar_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveAttendeeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveAttendeeRequest) ProtoMessage() {}
+
+func (x *RemoveAttendeeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_calendar_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveAttendeeRequest.ProtoReflect.Descriptor instead.
+func (*RemoveAttendeeRequest) Descriptor() ([]byte, []int) {
+	return file_calendar_calendar_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RemoveAttendeeRequest) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *RemoveAttendeeRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type RemoveAttendeeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveAttendeeResponse) Reset() {
+	*x = RemoveAttendeeResponse{}
+	mi := &file_calendar_calendar_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveAttendeeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveAttendeeResponse) ProtoMessage() {}
+
+func (x *RemoveAttendeeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_calendar_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveAttendeeResponse.ProtoReflect.Descriptor instead.
+func (*RemoveAttendeeResponse) Descriptor() ([]byte, []int) {
+	return file_calendar_calendar_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RemoveAttendeeResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type ListAttendeesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAttendeesRequest) Reset() {
+	*x = ListAttendeesRequest{}
+	mi := &file_calendar_calendar_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAttendeesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAttendeesRequest) ProtoMessage() {}
+
+func (x *ListAttendeesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_calendar_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAttendeesRequest.ProtoReflect.Descriptor instead.
+func (*ListAttendeesRequest) Descriptor() ([]byte, []int) {
+	return file_calendar_calendar_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListAttendeesRequest) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+type ListAttendeesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Attendees     []*Attendee            `protobuf:"bytes,1,rep,name=attendees,proto3" json:"attendees,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAttendeesResponse) Reset() {
+	*x = ListAttendeesResponse{}
+	mi := &file_calendar_calendar_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAttendeesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAttendeesResponse) ProtoMessage() {}
+
+func (x *ListAttendeesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_calendar_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAttendeesResponse.ProtoReflect.Descriptor instead.
+func (*ListAttendeesResponse) Descriptor() ([]byte, []int) {
+	return file_calendar_calendar_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListAttendeesResponse) GetAttendees() []*Attendee {
+	if x != nil {
+		return x.Attendees
+	}
+	return nil
+}
+
+type ListNotificationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNotificationsRequest) Reset() {
+	*x = ListNotificationsRequest{}
+	mi := &file_calendar_calendar_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNotificationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNotificationsRequest) ProtoMessage() {}
+
+func (x *ListNotificationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_calendar_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNotificationsRequest.ProtoReflect.Descriptor instead.
+func (*ListNotificationsRequest) Descriptor() ([]byte, []int) {
+	return file_calendar_calendar_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListNotificationsRequest) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+type ListNotificationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Notifications []*Notification        `protobuf:"bytes,1,rep,name=notifications,proto3" json:"notifications,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNotificationsResponse) Reset() {
+	*x = ListNotificationsResponse{}
+	mi := &file_calendar_calendar_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNotificationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNotificationsResponse) ProtoMessage() {}
+
+func (x *ListNotificationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_calendar_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNotificationsResponse.ProtoReflect.Descriptor instead.
+func (*ListNotificationsResponse) Descriptor() ([]byte, []int) {
+	return file_calendar_calendar_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListNotificationsResponse) GetNotifications() []*Notification {
+	if x != nil {
+		return x.Notifications
+	}
+	return nil
+}
+
+type WorkingHours struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Clock times in "HH:MM" format.
+	Start string `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End   string `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	// IANA time zone name, UTC when empty.
+	TimeZone string `protobuf:"bytes,3,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	// ISO weekday numbers, Monday is 1; every day when empty.
+	Weekdays      []int32 `protobuf:"varint,4,rep,packed,name=weekdays,proto3" json:"weekdays,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkingHours) Reset() {
+	*x = WorkingHours{}
+	mi := &file_calendar_calendar_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkingHours) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkingHours) ProtoMessage() {}
+
+func (x *WorkingHours) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_calendar_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkingHours.ProtoReflect.Descriptor instead.
+func (*WorkingHours) Descriptor() ([]byte, []int) {
+	return file_calendar_calendar_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *WorkingHours) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *WorkingHours) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+func (x *WorkingHours) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *WorkingHours) GetWeekdays() []int32 {
+	if x != nil {
+		return x.Weekdays
+	}
+	return nil
+}
+
+type FreeBusyRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	UserIds []string               `protobuf:"bytes,1,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	From    int64                  `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`
+	To      int64                  `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`
+	// Meeting duration and slot step in seconds.
+	Duration      int64         `protobuf:"varint,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	Step          int64         `protobuf:"varint,5,opt,name=step,proto3" json:"step,omitempty"`
+	Limit         int32         `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	WorkingHours  *WorkingHours `protobuf:"bytes,7,opt,name=working_hours,json=workingHours,proto3" json:"working_hours,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FreeBusyRequest) Reset() {
+	*x = FreeBusyRequest{}
+	mi := &file_calendar_calendar_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FreeBusyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreeBusyRequest) ProtoMessage() {}
+
+func (x *FreeBusyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_calendar_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreeBusyRequest.ProtoReflect.Descriptor instead.
+func (*FreeBusyRequest) Descriptor() ([]byte, []int) {
+	return file_calendar_calendar_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *FreeBusyRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+func (x *FreeBusyRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *FreeBusyRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *FreeBusyRequest) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *FreeBusyRequest) GetStep() int64 {
+	if x != nil {
+		return x.Step
+	}
+	return 0
+}
+
+func (x *FreeBusyRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *FreeBusyRequest) GetWorkingHours() *WorkingHours {
+	if x != nil {
+		return x.WorkingHours
+	}
+	return nil
+}
+
+type Interval struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         int64                  `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           int64                  `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Interval) Reset() {
+	*x = Interval{}
+	mi := &file_calendar_calendar_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Interval) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Interval) ProtoMessage() {}
+
+func (x *Interval) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_calendar_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Interval.ProtoReflect.Descriptor instead.
+func (*Interval) Descriptor() ([]byte, []int) {
+	return file_calendar_calendar_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *Interval) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *Interval) GetEnd() int64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+type BusyIntervals struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Intervals     []*Interval            `protobuf:"bytes,1,rep,name=intervals,proto3" json:"intervals,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BusyIntervals) Reset() {
+	*x = BusyIntervals{}
+	mi := &file_calendar_calendar_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BusyIntervals) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BusyIntervals) ProtoMessage() {}
+
+func (x *BusyIntervals) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_calendar_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BusyIntervals.ProtoReflect.Descriptor instead.
+func (*BusyIntervals) Descriptor() ([]byte, []int) {
+	return file_calendar_calendar_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *BusyIntervals) GetIntervals() []*Interval {
+	if x != nil {
+		return x.Intervals
+	}
+	return nil
+}
+
+type FreeBusyResponse struct {
+	state protoimpl.MessageState    `protogen:"open.v1"`
+	Busy  map[string]*BusyIntervals `protobuf:"bytes,1,rep,name=busy,proto3" json:"busy,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Common free slots, soonest first.
+	Slots         []*Interval `protobuf:"bytes,2,rep,name=slots,proto3" json:"slots,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FreeBusyResponse) Reset() {
+	*x = FreeBusyResponse{}
+	mi := &file_calendar_calendar_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FreeBusyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreeBusyResponse) ProtoMessage() {}
+
+func (x *FreeBusyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_calendar_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreeBusyResponse.ProtoReflect.Descriptor instead.
+func (*FreeBusyResponse) Descriptor() ([]byte, []int) {
+	return file_calendar_calendar_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *FreeBusyResponse) GetBusy() map[string]*BusyIntervals {
+	if x != nil {
+		return x.Busy
+	}
+	return nil
+}
+
+func (x *FreeBusyResponse) GetSlots() []*Interval {
+	if x != nil {
+		return x.Slots
+	}
+	return nil
+}
+
+var File_calendar_calendar_proto protoreflect.FileDescriptor
+
+const file_calendar_calendar_proto_rawDesc = "" +
+	"\n" +
+	"\x17calendar/calendar.proto\x12\bcalendar\x1a\x15calendar/events.proto\"?\n" +
+	"\x13CreateEventResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\"I\n" +
+	"\x13UpdateEventResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\">\n" +
+	"\x12DeleteEventRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\"/\n" +
+	"\x13DeleteEventResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"%\n" +
+	"\x13GetEventByIDRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"=\n" +
+	"\x14GetEventByIDResponse\x12%\n" +
+	"\x05event\x18\x01 \x01(\v2\x0f.calendar.EventR\x05event\"\x89\x01\n" +
+	"\x11ListEventsRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x12\n" +
+	"\x04from\x18\x04 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x05 \x01(\x03R\x02to\"e\n" +
+	"\x12ListEventsResponse\x12'\n" +
+	"\x06events\x18\x01 \x03(\v2\x0f.calendar.EventR\x06events\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"2\n" +
+	"\x17ListEventsByUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"]\n" +
+	"\x1eListEventsByUserInRangeRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\x03R\x02to\"R\n" +
+	"\x13ExportEventsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\x03R\x02to\"2\n" +
+	"\x14ExportEventsResponse\x12\x1a\n" +
+	"\bcalendar\x18\x01 \x01(\tR\bcalendar\"J\n" +
+	"\x13ImportEventsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\bcalendar\x18\x02 \x01(\tR\bcalendar\"c\n" +
+	"\x11ImportEventResult\x12\x10\n" +
+	"\x03uid\x18\x01 \x01(\tR\x03uid\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"\x7f\n" +
+	"\x14ImportEventsResponse\x12\x18\n" +
+	"\acreated\x18\x01 \x01(\x05R\acreated\x12\x16\n" +
+	"\x06failed\x18\x02 \x01(\x05R\x06failed\x125\n" +
+	"\aresults\x18\x03 \x03(\v2\x1b.calendar.ImportEventResultR\aresults\"I\n" +
+	"\x12WatchEventsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\tR\brevision\"z\n" +
+	"\vEventChange\x12(\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x14.calendar.ChangeTypeR\x04type\x12%\n" +
+	"\x05event\x18\x02 \x01(\v2\x0f.calendar.EventR\x05event\x12\x1a\n" +
+	"\brevision\x18\x03 \x01(\tR\brevision\"K\n" +
+	"\x15InviteAttendeeRequest\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"2\n" +
+	"\x16InviteAttendeeResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x82\x01\n" +
+	"\x1aRespondToInvitationRequest\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x120\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x18.calendar.AttendeeStatusR\x06status\"7\n" +
+	"\x1bRespondToInvitationResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"K\n" +
+	"\x15RemoveAttendeeRequest\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"2\n" +
+	"\x16RemoveAttendeeResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"1\n" +
+	"\x14ListAttendeesRequest\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\"I\n" +
+	"\x15ListAttendeesResponse\x120\n" +
+	"\tattendees\x18\x01 \x03(\v2\x12.calendar.AttendeeR\tattendees\"5\n" +
+	"\x18ListNotificationsRequest\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\"Y\n" +
+	"\x19ListNotificationsResponse\x12<\n" +
+	"\rnotifications\x18\x01 \x03(\v2\x16.calendar.NotificationR\rnotifications\"o\n" +
+	"\fWorkingHours\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x02 \x01(\tR\x03end\x12\x1b\n" +
+	"\ttime_zone\x18\x03 \x01(\tR\btimeZone\x12\x1a\n" +
+	"\bweekdays\x18\x04 \x03(\x05R\bweekdays\"\xd3\x01\n" +
+	"\x0fFreeBusyRequest\x12\x19\n" +
+	"\buser_ids\x18\x01 \x03(\tR\auserIds\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\x03R\x02to\x12\x1a\n" +
+	"\bduration\x18\x04 \x01(\x03R\bduration\x12\x12\n" +
+	"\x04step\x18\x05 \x01(\x03R\x04step\x12\x14\n" +
+	"\x05limit\x18\x06 \x01(\x05R\x05limit\x12;\n" +
+	"\rworking_hours\x18\a \x01(\v2\x16.calendar.WorkingHoursR\fworkingHours\"2\n" +
+	"\bInterval\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\x03R\x05start\x12\x10\n" +
+	"\x03end\x18\x02 \x01(\x03R\x03end\"A\n" +
+	"\rBusyIntervals\x120\n" +
+	"\tintervals\x18\x01 \x03(\v2\x12.calendar.IntervalR\tintervals\"\xc8\x01\n" +
+	"\x10FreeBusyResponse\x128\n" +
+	"\x04busy\x18\x01 \x03(\v2$.calendar.FreeBusyResponse.BusyEntryR\x04busy\x12(\n" +
+	"\x05slots\x18\x02 \x03(\v2\x12.calendar.IntervalR\x05slots\x1aP\n" +
+	"\tBusyEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12-\n" +
+	"\x05value\x18\x02 \x01(\v2\x17.calendar.BusyIntervalsR\x05value:\x028\x01*t\n" +
+	"\n" +
+	"ChangeType\x12\x1b\n" +
+	"\x17CHANGE_TYPE_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13CHANGE_TYPE_CREATED\x10\x01\x12\x17\n" +
+	"\x13CHANGE_TYPE_UPDATED\x10\x02\x12\x17\n" +
+	"\x13CHANGE_TYPE_DELETED\x10\x032\x94\n" +
+	"\n" +
 	"\x0fCalendarService\x12=\n" +
 	"\vCreateEvent\x12\x0f.calendar.Event\x1a\x1d.calendar.CreateEventResponse\x12=\n" +
 	"\vUpdateEvent\x12\x0f.calendar.Event\x1a\x1d.calendar.UpdateEventResponse\x12J\n" +
@@ -512,7 +1900,16 @@ const file_calendar_calendar_proto_rawDesc = "" +
 	"\n" +
 	"ListEvents\x12\x1b.calendar.ListEventsRequest\x1a\x1c.calendar.ListEventsResponse\x12S\n" +
 	"\x10ListEventsByUser\x12!.calendar.ListEventsByUserRequest\x1a\x1c.calendar.ListEventsResponse\x12a\n" +
-	"\x17ListEventsByUserInRange\x12(.calendar.ListEventsByUserInRangeRequest\x1a\x1c.calendar.ListEventsResponseB?Z=github.com/dimryb/go-hw/hw12_13_14_15_calendar/proto/calendarb\x06proto3"
+	"\x17ListEventsByUserInRange\x12(.calendar.ListEventsByUserInRangeRequest\x1a\x1c.calendar.ListEventsResponse\x12M\n" +
+	"\fExportEvents\x12\x1d.calendar.ExportEventsRequest\x1a\x1e.calendar.ExportEventsResponse\x12M\n" +
+	"\fImportEvents\x12\x1d.calendar.ImportEventsRequest\x1a\x1e.calendar.ImportEventsResponse\x12D\n" +
+	"\vWatchEvents\x12\x1c.calendar.WatchEventsRequest\x1a\x15.calendar.EventChange0\x01\x12S\n" +
+	"\x0eInviteAttendee\x12\x1f.calendar.InviteAttendeeRequest\x1a .calendar.InviteAttendeeResponse\x12b\n" +
+	"\x13RespondToInvitation\x12$.calendar.RespondToInvitationRequest\x1a%.calendar.RespondToInvitationResponse\x12S\n" +
+	"\x0eRemoveAttendee\x12\x1f.calendar.RemoveAttendeeRequest\x1a .calendar.RemoveAttendeeResponse\x12P\n" +
+	"\rListAttendees\x12\x1e.calendar.ListAttendeesRequest\x1a\x1f.calendar.ListAttendeesResponse\x12\\\n" +
+	"\x11ListNotifications\x12\".calendar.ListNotificationsRequest\x1a#.calendar.ListNotificationsResponse\x12E\n" +
+	"\fFindFreeBusy\x12\x19.calendar.FreeBusyRequest\x1a\x1a.calendar.FreeBusyResponseB?Z=github.com/dimryb/go-hw/hw12_13_14_15_calendar/proto/calendarb\x06proto3"
 
 var (
 	file_calendar_calendar_proto_rawDescOnce sync.Once
@@ -526,42 +1923,99 @@ func file_calendar_calendar_proto_rawDescGZIP() []byte {
 	return file_calendar_calendar_proto_rawDescData
 }
 
-var file_calendar_calendar_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_calendar_calendar_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_calendar_calendar_proto_msgTypes = make([]protoimpl.MessageInfo, 33)
 var file_calendar_calendar_proto_goTypes = []any{
-	(*CreateEventResponse)(nil),            // 0: calendar.CreateEventResponse
-	(*UpdateEventResponse)(nil),            // 1: calendar.UpdateEventResponse
-	(*DeleteEventRequest)(nil),             // 2: calendar.DeleteEventRequest
-	(*DeleteEventResponse)(nil),            // 3: calendar.DeleteEventResponse
-	(*GetEventByIDRequest)(nil),            // 4: calendar.GetEventByIDRequest
-	(*GetEventByIDResponse)(nil),           // 5: calendar.GetEventByIDResponse
-	(*ListEventsRequest)(nil),              // 6: calendar.ListEventsRequest
-	(*ListEventsResponse)(nil),             // 7: calendar.ListEventsResponse
-	(*ListEventsByUserRequest)(nil),        // 8: calendar.ListEventsByUserRequest
-	(*ListEventsByUserInRangeRequest)(nil), // 9: calendar.ListEventsByUserInRangeRequest
-	(*Event)(nil),                          // 10: calendar.Event
+	(ChangeType)(0),                        // 0: calendar.ChangeType
+	(*CreateEventResponse)(nil),            // 1: calendar.CreateEventResponse
+	(*UpdateEventResponse)(nil),            // 2: calendar.UpdateEventResponse
+	(*DeleteEventRequest)(nil),             // 3: calendar.DeleteEventRequest
+	(*DeleteEventResponse)(nil),            // 4: calendar.DeleteEventResponse
+	(*GetEventByIDRequest)(nil),            // 5: calendar.GetEventByIDRequest
+	(*GetEventByIDResponse)(nil),           // 6: calendar.GetEventByIDResponse
+	(*ListEventsRequest)(nil),              // 7: calendar.ListEventsRequest
+	(*ListEventsResponse)(nil),             // 8: calendar.ListEventsResponse
+	(*ListEventsByUserRequest)(nil),        // 9: calendar.ListEventsByUserRequest
+	(*ListEventsByUserInRangeRequest)(nil), // 10: calendar.ListEventsByUserInRangeRequest
+	(*ExportEventsRequest)(nil),            // 11: calendar.ExportEventsRequest
+	(*ExportEventsResponse)(nil),           // 12: calendar.ExportEventsResponse
+	(*ImportEventsRequest)(nil),            // 13: calendar.ImportEventsRequest
+	(*ImportEventResult)(nil),              // 14: calendar.ImportEventResult
+	(*ImportEventsResponse)(nil),           // 15: calendar.ImportEventsResponse
+	(*WatchEventsRequest)(nil),             // 16: calendar.WatchEventsRequest
+	(*EventChange)(nil),                    // 17: calendar.EventChange
+	(*InviteAttendeeRequest)(nil),          // 18: calendar.InviteAttendeeRequest
+	(*InviteAttendeeResponse)(nil),         // 19: calendar.InviteAttendeeResponse
+	(*RespondToInvitationRequest)(nil),     // 20: calendar.RespondToInvitationRequest
+	(*RespondToInvitationResponse)(nil),    // 21: calendar.RespondToInvitationResponse
+	(*RemoveAttendeeRequest)(nil),          // 22: calendar.RemoveAttendeeRequest
+	(*RemoveAttendeeResponse)(nil),         // 23: calendar.RemoveAttendeeResponse
+	(*ListAttendeesRequest)(nil),           // 24: calendar.ListAttendeesRequest
+	(*ListAttendeesResponse)(nil),          // 25: calendar.ListAttendeesResponse
+	(*ListNotificationsRequest)(nil),       // 26: calendar.ListNotificationsRequest
+	(*ListNotificationsResponse)(nil),      // 27: calendar.ListNotificationsResponse
+	(*WorkingHours)(nil),                   // 28: calendar.WorkingHours
+	(*FreeBusyRequest)(nil),                // 29: calendar.FreeBusyRequest
+	(*Interval)(nil),                       // 30: calendar.Interval
+	(*BusyIntervals)(nil),                  // 31: calendar.BusyIntervals
+	(*FreeBusyResponse)(nil),               // 32: calendar.FreeBusyResponse
+	nil,                                    // 33: calendar.FreeBusyResponse.BusyEntry
+	(*Event)(nil),                          // 34: calendar.Event
+	(AttendeeStatus)(0),                    // 35: calendar.AttendeeStatus
+	(*Attendee)(nil),                       // 36: calendar.Attendee
+	(*Notification)(nil),                   // 37: calendar.Notification
 }
 var file_calendar_calendar_proto_depIdxs = []int32{
-	10, // 0: calendar.GetEventByIDResponse.event:type_name -> calendar.Event
-	10, // 1: calendar.ListEventsResponse.events:type_name -> calendar.Event
-	10, // 2: calendar.CalendarService.CreateEvent:input_type -> calendar.Event
-	10, // 3: calendar.CalendarService.UpdateEvent:input_type -> calendar.Event
-	2,  // 4: calendar.CalendarService.DeleteEvent:input_type -> calendar.DeleteEventRequest
-	4,  // 5: calendar.CalendarService.GetEventByID:input_type -> calendar.GetEventByIDRequest
-	6,  // 6: calendar.CalendarService.ListEvents:input_type -> calendar.ListEventsRequest
-	8,  // 7: calendar.CalendarService.ListEventsByUser:input_type -> calendar.ListEventsByUserRequest
-	9,  // 8: calendar.CalendarService.ListEventsByUserInRange:input_type -> calendar.ListEventsByUserInRangeRequest
-	0,  // 9: calendar.CalendarService.CreateEvent:output_type -> calendar.CreateEventResponse
-	1,  // 10: calendar.CalendarService.UpdateEvent:output_type -> calendar.UpdateEventResponse
-	3,  // 11: calendar.CalendarService.DeleteEvent:output_type -> calendar.DeleteEventResponse
-	5,  // 12: calendar.CalendarService.GetEventByID:output_type -> calendar.GetEventByIDResponse
-	7,  // 13: calendar.CalendarService.ListEvents:output_type -> calendar.ListEventsResponse
-	7,  // 14: calendar.CalendarService.ListEventsByUser:output_type -> calendar.ListEventsResponse
-	7,  // 15: calendar.CalendarService.ListEventsByUserInRange:output_type -> calendar.ListEventsResponse
-	9,  // [9:16] is the sub-list for method output_type
-	2,  // [2:9] is the sub-list for method input_type
-	2,  // [2:2] is the sub-list for extension type_name
-	2,  // [2:2] is the sub-list for extension extendee
-	0,  // [0:2] is the sub-list for field type_name
+	34, // 0: calendar.GetEventByIDResponse.event:type_name -> calendar.Event
+	34, // 1: calendar.ListEventsResponse.events:type_name -> calendar.Event
+	14, // 2: calendar.ImportEventsResponse.results:type_name -> calendar.ImportEventResult
+	0,  // 3: calendar.EventChange.type:type_name -> calendar.ChangeType
+	34, // 4: calendar.EventChange.event:type_name -> calendar.Event
+	35, // 5: calendar.RespondToInvitationRequest.status:type_name -> calendar.AttendeeStatus
+	36, // 6: calendar.ListAttendeesResponse.attendees:type_name -> calendar.Attendee
+	37, // 7: calendar.ListNotificationsResponse.notifications:type_name -> calendar.Notification
+	28, // 8: calendar.FreeBusyRequest.working_hours:type_name -> calendar.WorkingHours
+	30, // 9: calendar.BusyIntervals.intervals:type_name -> calendar.Interval
+	33, // 10: calendar.FreeBusyResponse.busy:type_name -> calendar.FreeBusyResponse.BusyEntry
+	30, // 11: calendar.FreeBusyResponse.slots:type_name -> calendar.Interval
+	31, // 12: calendar.FreeBusyResponse.BusyEntry.value:type_name -> calendar.BusyIntervals
+	34, // 13: calendar.CalendarService.CreateEvent:input_type -> calendar.Event
+	34, // 14: calendar.CalendarService.UpdateEvent:input_type -> calendar.Event
+	3,  // 15: calendar.CalendarService.DeleteEvent:input_type -> calendar.DeleteEventRequest
+	5,  // 16: calendar.CalendarService.GetEventByID:input_type -> calendar.GetEventByIDRequest
+	7,  // 17: calendar.CalendarService.ListEvents:input_type -> calendar.ListEventsRequest
+	9,  // 18: calendar.CalendarService.ListEventsByUser:input_type -> calendar.ListEventsByUserRequest
+	10, // 19: calendar.CalendarService.ListEventsByUserInRange:input_type -> calendar.ListEventsByUserInRangeRequest
+	11, // 20: calendar.CalendarService.ExportEvents:input_type -> calendar.ExportEventsRequest
+	13, // 21: calendar.CalendarService.ImportEvents:input_type -> calendar.ImportEventsRequest
+	16, // 22: calendar.CalendarService.WatchEvents:input_type -> calendar.WatchEventsRequest
+	18, // 23: calendar.CalendarService.InviteAttendee:input_type -> calendar.InviteAttendeeRequest
+	20, // 24: calendar.CalendarService.RespondToInvitation:input_type -> calendar.RespondToInvitationRequest
+	22, // 25: calendar.CalendarService.RemoveAttendee:input_type -> calendar.RemoveAttendeeRequest
+	24, // 26: calendar.CalendarService.ListAttendees:input_type -> calendar.ListAttendeesRequest
+	26, // 27: calendar.CalendarService.ListNotifications:input_type -> calendar.ListNotificationsRequest
+	29, // 28: calendar.CalendarService.FindFreeBusy:input_type -> calendar.FreeBusyRequest
+	1,  // 29: calendar.CalendarService.CreateEvent:output_type -> calendar.CreateEventResponse
+	2,  // 30: calendar.CalendarService.UpdateEvent:output_type -> calendar.UpdateEventResponse
+	4,  // 31: calendar.CalendarService.DeleteEvent:output_type -> calendar.DeleteEventResponse
+	6,  // 32: calendar.CalendarService.GetEventByID:output_type -> calendar.GetEventByIDResponse
+	8,  // 33: calendar.CalendarService.ListEvents:output_type -> calendar.ListEventsResponse
+	8,  // 34: calendar.CalendarService.ListEventsByUser:output_type -> calendar.ListEventsResponse
+	8,  // 35: calendar.CalendarService.ListEventsByUserInRange:output_type -> calendar.ListEventsResponse
+	12, // 36: calendar.CalendarService.ExportEvents:output_type -> calendar.ExportEventsResponse
+	15, // 37: calendar.CalendarService.ImportEvents:output_type -> calendar.ImportEventsResponse
+	17, // 38: calendar.CalendarService.WatchEvents:output_type -> calendar.EventChange
+	19, // 39: calendar.CalendarService.InviteAttendee:output_type -> calendar.InviteAttendeeResponse
+	21, // 40: calendar.CalendarService.RespondToInvitation:output_type -> calendar.RespondToInvitationResponse
+	23, // 41: calendar.CalendarService.RemoveAttendee:output_type -> calendar.RemoveAttendeeResponse
+	25, // 42: calendar.CalendarService.ListAttendees:output_type -> calendar.ListAttendeesResponse
+	27, // 43: calendar.CalendarService.ListNotifications:output_type -> calendar.ListNotificationsResponse
+	32, // 44: calendar.CalendarService.FindFreeBusy:output_type -> calendar.FreeBusyResponse
+	29, // [29:45] is the sub-list for method output_type
+	13, // [13:29] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_calendar_calendar_proto_init() }
@@ -575,13 +2029,14 @@ func file_calendar_calendar_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_calendar_calendar_proto_rawDesc), len(file_calendar_calendar_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   10,
+			NumEnums:      1,
+			NumMessages:   33,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_calendar_calendar_proto_goTypes,
 		DependencyIndexes: file_calendar_calendar_proto_depIdxs,
+		EnumInfos:         file_calendar_calendar_proto_enumTypes,
 		MessageInfos:      file_calendar_calendar_proto_msgTypes,
 	}.Build()
 	File_calendar_calendar_proto = out.File
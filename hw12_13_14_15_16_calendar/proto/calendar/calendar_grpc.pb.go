@@ -26,6 +26,15 @@ const (
 	CalendarService_ListEvents_FullMethodName              = "/calendar.CalendarService/ListEvents"
 	CalendarService_ListEventsByUser_FullMethodName        = "/calendar.CalendarService/ListEventsByUser"
 	CalendarService_ListEventsByUserInRange_FullMethodName = "/calendar.CalendarService/ListEventsByUserInRange"
+	CalendarService_ExportEvents_FullMethodName            = "/calendar.CalendarService/ExportEvents"
+	CalendarService_ImportEvents_FullMethodName            = "/calendar.CalendarService/ImportEvents"
+	CalendarService_WatchEvents_FullMethodName             = "/calendar.CalendarService/WatchEvents"
+	CalendarService_InviteAttendee_FullMethodName          = "/calendar.CalendarService/InviteAttendee"
+	CalendarService_RespondToInvitation_FullMethodName     = "/calendar.CalendarService/RespondToInvitation"
+	CalendarService_RemoveAttendee_FullMethodName          = "/calendar.CalendarService/RemoveAttendee"
+	CalendarService_ListAttendees_FullMethodName           = "/calendar.CalendarService/ListAttendees"
+	CalendarService_ListNotifications_FullMethodName       = "/calendar.CalendarService/ListNotifications"
+	CalendarService_FindFreeBusy_FullMethodName            = "/calendar.CalendarService/FindFreeBusy"
 )
 
 // CalendarServiceClient is the client API for CalendarService service.
@@ -39,6 +48,15 @@ type CalendarServiceClient interface {
 	ListEvents(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error)
 	ListEventsByUser(ctx context.Context, in *ListEventsByUserRequest, opts ...grpc.CallOption) (*ListEventsResponse, error)
 	ListEventsByUserInRange(ctx context.Context, in *ListEventsByUserInRangeRequest, opts ...grpc.CallOption) (*ListEventsResponse, error)
+	ExportEvents(ctx context.Context, in *ExportEventsRequest, opts ...grpc.CallOption) (*ExportEventsResponse, error)
+	ImportEvents(ctx context.Context, in *ImportEventsRequest, opts ...grpc.CallOption) (*ImportEventsResponse, error)
+	WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[EventChange], error)
+	InviteAttendee(ctx context.Context, in *InviteAttendeeRequest, opts ...grpc.CallOption) (*InviteAttendeeResponse, error)
+	RespondToInvitation(ctx context.Context, in *RespondToInvitationRequest, opts ...grpc.CallOption) (*RespondToInvitationResponse, error)
+	RemoveAttendee(ctx context.Context, in *RemoveAttendeeRequest, opts ...grpc.CallOption) (*RemoveAttendeeResponse, error)
+	ListAttendees(ctx context.Context, in *ListAttendeesRequest, opts ...grpc.CallOption) (*ListAttendeesResponse, error)
+	ListNotifications(ctx context.Context, in *ListNotificationsRequest, opts ...grpc.CallOption) (*ListNotificationsResponse, error)
+	FindFreeBusy(ctx context.Context, in *FreeBusyRequest, opts ...grpc.CallOption) (*FreeBusyResponse, error)
 }
 
 type calendarServiceClient struct {
@@ -119,6 +137,105 @@ func (c *calendarServiceClient) ListEventsByUserInRange(ctx context.Context, in
 	return out, nil
 }
 
+func (c *calendarServiceClient) ExportEvents(ctx context.Context, in *ExportEventsRequest, opts ...grpc.CallOption) (*ExportEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportEventsResponse)
+	err := c.cc.Invoke(ctx, CalendarService_ExportEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) ImportEvents(ctx context.Context, in *ImportEventsRequest, opts ...grpc.CallOption) (*ImportEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportEventsResponse)
+	err := c.cc.Invoke(ctx, CalendarService_ImportEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[EventChange], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CalendarService_ServiceDesc.Streams[0], CalendarService_WatchEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchEventsRequest, EventChange]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CalendarService_WatchEventsClient = grpc.ServerStreamingClient[EventChange]
+
+func (c *calendarServiceClient) InviteAttendee(ctx context.Context, in *InviteAttendeeRequest, opts ...grpc.CallOption) (*InviteAttendeeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InviteAttendeeResponse)
+	err := c.cc.Invoke(ctx, CalendarService_InviteAttendee_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) RespondToInvitation(ctx context.Context, in *RespondToInvitationRequest, opts ...grpc.CallOption) (*RespondToInvitationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RespondToInvitationResponse)
+	err := c.cc.Invoke(ctx, CalendarService_RespondToInvitation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) RemoveAttendee(ctx context.Context, in *RemoveAttendeeRequest, opts ...grpc.CallOption) (*RemoveAttendeeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveAttendeeResponse)
+	err := c.cc.Invoke(ctx, CalendarService_RemoveAttendee_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) ListAttendees(ctx context.Context, in *ListAttendeesRequest, opts ...grpc.CallOption) (*ListAttendeesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAttendeesResponse)
+	err := c.cc.Invoke(ctx, CalendarService_ListAttendees_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) ListNotifications(ctx context.Context, in *ListNotificationsRequest, opts ...grpc.CallOption) (*ListNotificationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListNotificationsResponse)
+	err := c.cc.Invoke(ctx, CalendarService_ListNotifications_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) FindFreeBusy(ctx context.Context, in *FreeBusyRequest, opts ...grpc.CallOption) (*FreeBusyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FreeBusyResponse)
+	err := c.cc.Invoke(ctx, CalendarService_FindFreeBusy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CalendarServiceServer is the server API for CalendarService service.
 // All implementations must embed UnimplementedCalendarServiceServer
 // for forward compatibility.
@@ -130,6 +247,15 @@ type CalendarServiceServer interface {
 	ListEvents(context.Context, *ListEventsRequest) (*ListEventsResponse, error)
 	ListEventsByUser(context.Context, *ListEventsByUserRequest) (*ListEventsResponse, error)
 	ListEventsByUserInRange(context.Context, *ListEventsByUserInRangeRequest) (*ListEventsResponse, error)
+	ExportEvents(context.Context, *ExportEventsRequest) (*ExportEventsResponse, error)
+	ImportEvents(context.Context, *ImportEventsRequest) (*ImportEventsResponse, error)
+	WatchEvents(*WatchEventsRequest, grpc.ServerStreamingServer[EventChange]) error
+	InviteAttendee(context.Context, *InviteAttendeeRequest) (*InviteAttendeeResponse, error)
+	RespondToInvitation(context.Context, *RespondToInvitationRequest) (*RespondToInvitationResponse, error)
+	RemoveAttendee(context.Context, *RemoveAttendeeRequest) (*RemoveAttendeeResponse, error)
+	ListAttendees(context.Context, *ListAttendeesRequest) (*ListAttendeesResponse, error)
+	ListNotifications(context.Context, *ListNotificationsRequest) (*ListNotificationsResponse, error)
+	FindFreeBusy(context.Context, *FreeBusyRequest) (*FreeBusyResponse, error)
 	mustEmbedUnimplementedCalendarServiceServer()
 }
 
@@ -161,6 +287,33 @@ func (UnimplementedCalendarServiceServer) ListEventsByUser(context.Context, *Lis
 func (UnimplementedCalendarServiceServer) ListEventsByUserInRange(context.Context, *ListEventsByUserInRangeRequest) (*ListEventsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListEventsByUserInRange not implemented")
 }
+func (UnimplementedCalendarServiceServer) ExportEvents(context.Context, *ExportEventsRequest) (*ExportEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportEvents not implemented")
+}
+func (UnimplementedCalendarServiceServer) ImportEvents(context.Context, *ImportEventsRequest) (*ImportEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportEvents not implemented")
+}
+func (UnimplementedCalendarServiceServer) WatchEvents(*WatchEventsRequest, grpc.ServerStreamingServer[EventChange]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
+func (UnimplementedCalendarServiceServer) InviteAttendee(context.Context, *InviteAttendeeRequest) (*InviteAttendeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InviteAttendee not implemented")
+}
+func (UnimplementedCalendarServiceServer) RespondToInvitation(context.Context, *RespondToInvitationRequest) (*RespondToInvitationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RespondToInvitation not implemented")
+}
+func (UnimplementedCalendarServiceServer) RemoveAttendee(context.Context, *RemoveAttendeeRequest) (*RemoveAttendeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveAttendee not implemented")
+}
+func (UnimplementedCalendarServiceServer) ListAttendees(context.Context, *ListAttendeesRequest) (*ListAttendeesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAttendees not implemented")
+}
+func (UnimplementedCalendarServiceServer) ListNotifications(context.Context, *ListNotificationsRequest) (*ListNotificationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNotifications not implemented")
+}
+func (UnimplementedCalendarServiceServer) FindFreeBusy(context.Context, *FreeBusyRequest) (*FreeBusyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindFreeBusy not implemented")
+}
 func (UnimplementedCalendarServiceServer) mustEmbedUnimplementedCalendarServiceServer() {}
 func (UnimplementedCalendarServiceServer) testEmbeddedByValue()                         {}
 
@@ -308,6 +461,161 @@ func _CalendarService_ListEventsByUserInRange_Handler(srv interface{}, ctx conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CalendarService_ExportEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).ExportEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_ExportEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).ExportEvents(ctx, req.(*ExportEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_ImportEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).ImportEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_ImportEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).ImportEvents(ctx, req.(*ImportEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CalendarServiceServer).WatchEvents(m, &grpc.GenericServerStream[WatchEventsRequest, EventChange]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CalendarService_WatchEventsServer = grpc.ServerStreamingServer[EventChange]
+
+func _CalendarService_InviteAttendee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InviteAttendeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).InviteAttendee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_InviteAttendee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).InviteAttendee(ctx, req.(*InviteAttendeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_RespondToInvitation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RespondToInvitationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).RespondToInvitation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_RespondToInvitation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).RespondToInvitation(ctx, req.(*RespondToInvitationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_RemoveAttendee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveAttendeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).RemoveAttendee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_RemoveAttendee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).RemoveAttendee(ctx, req.(*RemoveAttendeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_ListAttendees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAttendeesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).ListAttendees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_ListAttendees_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).ListAttendees(ctx, req.(*ListAttendeesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_ListNotifications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNotificationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).ListNotifications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_ListNotifications_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).ListNotifications(ctx, req.(*ListNotificationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_FindFreeBusy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FreeBusyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).FindFreeBusy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_FindFreeBusy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).FindFreeBusy(ctx, req.(*FreeBusyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CalendarService_ServiceDesc is the grpc.ServiceDesc for CalendarService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -343,7 +651,45 @@ var CalendarService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListEventsByUserInRange",
 			Handler:    _CalendarService_ListEventsByUserInRange_Handler,
 		},
+		{
+			MethodName: "ExportEvents",
+			Handler:    _CalendarService_ExportEvents_Handler,
+		},
+		{
+			MethodName: "ImportEvents",
+			Handler:    _CalendarService_ImportEvents_Handler,
+		},
+		{
+			MethodName: "InviteAttendee",
+			Handler:    _CalendarService_InviteAttendee_Handler,
+		},
+		{
+			MethodName: "RespondToInvitation",
+			Handler:    _CalendarService_RespondToInvitation_Handler,
+		},
+		{
+			MethodName: "RemoveAttendee",
+			Handler:    _CalendarService_RemoveAttendee_Handler,
+		},
+		{
+			MethodName: "ListAttendees",
+			Handler:    _CalendarService_ListAttendees_Handler,
+		},
+		{
+			MethodName: "ListNotifications",
+			Handler:    _CalendarService_ListNotifications_Handler,
+		},
+		{
+			MethodName: "FindFreeBusy",
+			Handler:    _CalendarService_FindFreeBusy_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _CalendarService_WatchEvents_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "calendar/calendar.proto",
 }
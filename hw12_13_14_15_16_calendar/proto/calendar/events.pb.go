@@ -21,15 +21,130 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type AttendeeStatus int32
+
+const (
+	AttendeeStatus_ATTENDEE_STATUS_UNSPECIFIED  AttendeeStatus = 0
+	AttendeeStatus_ATTENDEE_STATUS_NEEDS_ACTION AttendeeStatus = 1
+	AttendeeStatus_ATTENDEE_STATUS_ACCEPTED     AttendeeStatus = 2
+	AttendeeStatus_ATTENDEE_STATUS_DECLINED     AttendeeStatus = 3
+	AttendeeStatus_ATTENDEE_STATUS_TENTATIVE    AttendeeStatus = 4
+)
+
+// Enum value maps for AttendeeStatus.
+var (
+	AttendeeStatus_name = map[int32]string{
+		0: "ATTENDEE_STATUS_UNSPECIFIED",
+		1: "ATTENDEE_STATUS_NEEDS_ACTION",
+		2: "ATTENDEE_STATUS_ACCEPTED",
+		3: "ATTENDEE_STATUS_DECLINED",
+		4: "ATTENDEE_STATUS_TENTATIVE",
+	}
+	AttendeeStatus_value = map[string]int32{
+		"ATTENDEE_STATUS_UNSPECIFIED":  0,
+		"ATTENDEE_STATUS_NEEDS_ACTION": 1,
+		"ATTENDEE_STATUS_ACCEPTED":     2,
+		"ATTENDEE_STATUS_DECLINED":     3,
+		"ATTENDEE_STATUS_TENTATIVE":    4,
+	}
+)
+
+func (x AttendeeStatus) Enum() *AttendeeStatus {
+	p := new(AttendeeStatus)
+	*p = x
+	return p
+}
+
+func (x AttendeeStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AttendeeStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_calendar_events_proto_enumTypes[0].Descriptor()
+}
+
+func (AttendeeStatus) Type() protoreflect.EnumType {
+	return &file_calendar_events_proto_enumTypes[0]
+}
+
+func (x AttendeeStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AttendeeStatus.Descriptor instead.
+func (AttendeeStatus) EnumDescriptor() ([]byte, []int) {
+	return file_calendar_events_proto_rawDescGZIP(), []int{0}
+}
+
+type NotificationState int32
+
+const (
+	NotificationState_NOTIFICATION_STATE_UNSPECIFIED NotificationState = 0
+	NotificationState_NOTIFICATION_STATE_PENDING     NotificationState = 1
+	NotificationState_NOTIFICATION_STATE_PUBLISHED   NotificationState = 2
+	NotificationState_NOTIFICATION_STATE_DELIVERED   NotificationState = 3
+)
+
+// Enum value maps for NotificationState.
+var (
+	NotificationState_name = map[int32]string{
+		0: "NOTIFICATION_STATE_UNSPECIFIED",
+		1: "NOTIFICATION_STATE_PENDING",
+		2: "NOTIFICATION_STATE_PUBLISHED",
+		3: "NOTIFICATION_STATE_DELIVERED",
+	}
+	NotificationState_value = map[string]int32{
+		"NOTIFICATION_STATE_UNSPECIFIED": 0,
+		"NOTIFICATION_STATE_PENDING":     1,
+		"NOTIFICATION_STATE_PUBLISHED":   2,
+		"NOTIFICATION_STATE_DELIVERED":   3,
+	}
+)
+
+func (x NotificationState) Enum() *NotificationState {
+	p := new(NotificationState)
+	*p = x
+	return p
+}
+
+func (x NotificationState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NotificationState) Descriptor() protoreflect.EnumDescriptor {
+	return file_calendar_events_proto_enumTypes[1].Descriptor()
+}
+
+func (NotificationState) Type() protoreflect.EnumType {
+	return &file_calendar_events_proto_enumTypes[1]
+}
+
+func (x NotificationState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NotificationState.Descriptor instead.
+func (NotificationState) EnumDescriptor() ([]byte, []int) {
+	return file_calendar_events_proto_rawDescGZIP(), []int{1}
+}
+
 type Event struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
-	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
-	StartTime     int64                  `protobuf:"varint,5,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
-	EndTime       int64                  `protobuf:"varint,6,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
-	NotifyBefore  int64                  `protobuf:"varint,7,opt,name=notify_before,json=notifyBefore,proto3" json:"notify_before,omitempty"`
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId       string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Title        string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Description  string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	StartTime    int64                  `protobuf:"varint,5,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime      int64                  `protobuf:"varint,6,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	NotifyBefore int64                  `protobuf:"varint,7,opt,name=notify_before,json=notifyBefore,proto3" json:"notify_before,omitempty"`
+	Rrule        string                 `protobuf:"bytes,8,opt,name=rrule,proto3" json:"rrule,omitempty"`
+	Exdates      []int64                `protobuf:"varint,9,rep,packed,name=exdates,proto3" json:"exdates,omitempty"`
+	// IANA time zone the series is expanded in, UTC when empty.
+	TimeZone string `protobuf:"bytes,10,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	// All-day events span whole days from local midnight in time_zone.
+	AllDay bool `protobuf:"varint,11,opt,name=all_day,json=allDay,proto3" json:"all_day,omitempty"`
+	// Version of the event; UpdateEvent requires the current one.
+	Version       int64 `protobuf:"varint,12,opt,name=version,proto3" json:"version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -113,11 +228,243 @@ func (x *Event) GetNotifyBefore() int64 {
 	return 0
 }
 
+func (x *Event) GetRrule() string {
+	if x != nil {
+		return x.Rrule
+	}
+	return ""
+}
+
+func (x *Event) GetExdates() []int64 {
+	if x != nil {
+		return x.Exdates
+	}
+	return nil
+}
+
+func (x *Event) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *Event) GetAllDay() bool {
+	if x != nil {
+		return x.AllDay
+	}
+	return false
+}
+
+func (x *Event) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type Attendee struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status        AttendeeStatus         `protobuf:"varint,3,opt,name=status,proto3,enum=calendar.AttendeeStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attendee) Reset() {
+	*x = Attendee{}
+	mi := &file_calendar_events_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attendee) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attendee) ProtoMessage() {}
+
+func (x *Attendee) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_events_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attendee.ProtoReflect.Descriptor instead.
+func (*Attendee) Descriptor() ([]byte, []int) {
+	return file_calendar_events_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Attendee) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *Attendee) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Attendee) GetStatus() AttendeeStatus {
+	if x != nil {
+		return x.Status
+	}
+	return AttendeeStatus_ATTENDEE_STATUS_UNSPECIFIED
+}
+
+// NotificationStatus is a delivery outcome reported by the sender.
+type NotificationStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NotificationStatus) Reset() {
+	*x = NotificationStatus{}
+	mi := &file_calendar_events_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationStatus) ProtoMessage() {}
+
+func (x *NotificationStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_events_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationStatus.ProtoReflect.Descriptor instead.
+func (*NotificationStatus) Descriptor() ([]byte, []int) {
+	return file_calendar_events_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *NotificationStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *NotificationStatus) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type Notification struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	EventId       string                 `protobuf:"bytes,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	Occurrence    int64                  `protobuf:"varint,3,opt,name=occurrence,proto3" json:"occurrence,omitempty"`
+	NotifyAt      int64                  `protobuf:"varint,4,opt,name=notify_at,json=notifyAt,proto3" json:"notify_at,omitempty"`
+	State         NotificationState      `protobuf:"varint,5,opt,name=state,proto3,enum=calendar.NotificationState" json:"state,omitempty"`
+	Statuses      []*NotificationStatus  `protobuf:"bytes,6,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Notification) Reset() {
+	*x = Notification{}
+	mi := &file_calendar_events_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Notification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Notification) ProtoMessage() {}
+
+func (x *Notification) ProtoReflect() protoreflect.Message {
+	mi := &file_calendar_events_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Notification.ProtoReflect.Descriptor instead.
+func (*Notification) Descriptor() ([]byte, []int) {
+	return file_calendar_events_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Notification) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Notification) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *Notification) GetOccurrence() int64 {
+	if x != nil {
+		return x.Occurrence
+	}
+	return 0
+}
+
+func (x *Notification) GetNotifyAt() int64 {
+	if x != nil {
+		return x.NotifyAt
+	}
+	return 0
+}
+
+func (x *Notification) GetState() NotificationState {
+	if x != nil {
+		return x.State
+	}
+	return NotificationState_NOTIFICATION_STATE_UNSPECIFIED
+}
+
+func (x *Notification) GetStatuses() []*NotificationStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
 var File_calendar_events_proto protoreflect.FileDescriptor
 
 const file_calendar_events_proto_rawDesc = "" +
 	"\n" +
-	"\x15calendar/events.proto\x12\bcalendar\"\xc7\x01\n" +
+	"\x15calendar/events.proto\x12\bcalendar\"\xc7\x02\n" +
 	"\x05Event\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x14\n" +
@@ -126,7 +473,40 @@ const file_calendar_events_proto_rawDesc = "" +
 	"\n" +
 	"start_time\x18\x05 \x01(\x03R\tstartTime\x12\x19\n" +
 	"\bend_time\x18\x06 \x01(\x03R\aendTime\x12#\n" +
-	"\rnotify_before\x18\a \x01(\x03R\fnotifyBeforeB?Z=github.com/dimryb/go-hw/hw12_13_14_15_calendar/proto/calendarb\x06proto3"
+	"\rnotify_before\x18\a \x01(\x03R\fnotifyBefore\x12\x14\n" +
+	"\x05rrule\x18\b \x01(\tR\x05rrule\x12\x18\n" +
+	"\aexdates\x18\t \x03(\x03R\aexdates\x12\x1b\n" +
+	"\ttime_zone\x18\n" +
+	" \x01(\tR\btimeZone\x12\x17\n" +
+	"\aall_day\x18\v \x01(\bR\x06allDay\x12\x18\n" +
+	"\aversion\x18\f \x01(\x03R\aversion\"p\n" +
+	"\bAttendee\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x120\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x18.calendar.AttendeeStatusR\x06status\"J\n" +
+	"\x12NotificationStatus\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\"\xe3\x01\n" +
+	"\fNotification\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bevent_id\x18\x02 \x01(\tR\aeventId\x12\x1e\n" +
+	"\n" +
+	"occurrence\x18\x03 \x01(\x03R\n" +
+	"occurrence\x12\x1b\n" +
+	"\tnotify_at\x18\x04 \x01(\x03R\bnotifyAt\x121\n" +
+	"\x05state\x18\x05 \x01(\x0e2\x1b.calendar.NotificationStateR\x05state\x128\n" +
+	"\bstatuses\x18\x06 \x03(\v2\x1c.calendar.NotificationStatusR\bstatuses*\xae\x01\n" +
+	"\x0eAttendeeStatus\x12\x1f\n" +
+	"\x1bATTENDEE_STATUS_UNSPECIFIED\x10\x00\x12 \n" +
+	"\x1cATTENDEE_STATUS_NEEDS_ACTION\x10\x01\x12\x1c\n" +
+	"\x18ATTENDEE_STATUS_ACCEPTED\x10\x02\x12\x1c\n" +
+	"\x18ATTENDEE_STATUS_DECLINED\x10\x03\x12\x1d\n" +
+	"\x19ATTENDEE_STATUS_TENTATIVE\x10\x04*\x9b\x01\n" +
+	"\x11NotificationState\x12\"\n" +
+	"\x1eNOTIFICATION_STATE_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aNOTIFICATION_STATE_PENDING\x10\x01\x12 \n" +
+	"\x1cNOTIFICATION_STATE_PUBLISHED\x10\x02\x12 \n" +
+	"\x1cNOTIFICATION_STATE_DELIVERED\x10\x03B?Z=github.com/dimryb/go-hw/hw12_13_14_15_calendar/proto/calendarb\x06proto3"
 
 var (
 	file_calendar_events_proto_rawDescOnce sync.Once
@@ -140,16 +520,25 @@ func file_calendar_events_proto_rawDescGZIP() []byte {
 	return file_calendar_events_proto_rawDescData
 }
 
-var file_calendar_events_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_calendar_events_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_calendar_events_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_calendar_events_proto_goTypes = []any{
-	(*Event)(nil), // 0: calendar.Event
+	(AttendeeStatus)(0),        // 0: calendar.AttendeeStatus
+	(NotificationState)(0),     // 1: calendar.NotificationState
+	(*Event)(nil),              // 2: calendar.Event
+	(*Attendee)(nil),           // 3: calendar.Attendee
+	(*NotificationStatus)(nil), // 4: calendar.NotificationStatus
+	(*Notification)(nil),       // 5: calendar.Notification
 }
 var file_calendar_events_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0, // 0: calendar.Attendee.status:type_name -> calendar.AttendeeStatus
+	1, // 1: calendar.Notification.state:type_name -> calendar.NotificationState
+	4, // 2: calendar.Notification.statuses:type_name -> calendar.NotificationStatus
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_calendar_events_proto_init() }
@@ -162,13 +551,14 @@ func file_calendar_events_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_calendar_events_proto_rawDesc), len(file_calendar_events_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   1,
+			NumEnums:      2,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_calendar_events_proto_goTypes,
 		DependencyIndexes: file_calendar_events_proto_depIdxs,
+		EnumInfos:         file_calendar_events_proto_enumTypes,
 		MessageInfos:      file_calendar_events_proto_msgTypes,
 	}.Build()
 	File_calendar_events_proto = out.File
@@ -8,13 +8,27 @@ import (
 	"testing"
 	"time"
 
-	"github.com/dimryb/go-hw/hw12_13_14_15_calendar/internal/rmq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+type NotificationStatusResponse struct {
+	Status    string `json:"status"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type NotificationResponse struct {
+	ID       string                       `json:"id"`
+	State    string                       `json:"state"`
+	Statuses []NotificationStatusResponse `json:"statuses"`
+}
+
+type ListNotificationsResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+}
+
 func TestNotificationIsSent(t *testing.T) {
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
 	defer cancel()
 
 	now := time.Now().UTC()
@@ -33,45 +47,65 @@ func TestNotificationIsSent(t *testing.T) {
 
 	reqBody, _ := json.Marshal(eventReq)
 
+	client := &http.Client{}
+	var eventID string
+
 	t.Run("CreateEvent", func(t *testing.T) {
 		httpReq, err := http.NewRequestWithContext(ctx, "POST", calendarBaseURL+"/event/create", bytes.NewBuffer(reqBody))
-		assert.NoError(t, err)
+		require.NoError(t, err)
 		httpReq.Header.Set("Content-Type", "application/json")
 
-		client := &http.Client{}
 		resp, err := client.Do(httpReq)
-		assert.NoError(t, err)
+		require.NoError(t, err)
 		defer resp.Body.Close()
 
-		assert.Equal(t, http.StatusCreated, resp.StatusCode, "Expected status 201 Created")
+		require.Equal(t, http.StatusCreated, resp.StatusCode, "Expected status 201 Created")
 
-		var createResp map[string]interface{}
-		err = json.NewDecoder(resp.Body).Decode(&createResp)
-		assert.NoError(t, err)
-		assert.Equal(t, "created", createResp["status"])
+		var createResp CreateEventResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&createResp))
+		assert.Equal(t, "created", createResp.Status)
+		eventID = createResp.ID
 	})
+	require.NotEmpty(t, eventID, "event was not created")
 
-	t.Run("WaitForNotificationStatus", func(t *testing.T) {
-		rmqClient, err := rmq.NewClient(rabbitURL, "notification_status")
-		require.NoError(t, err, "Failed to create RMQ client for status")
-		defer func() { _ = rmqClient.Close() }()
-
-		msgs, err := rmqClient.Consume("notification_status")
-		require.NoError(t, err, "Failed to register a consumer for status")
+	// The status reports are consumed by the scheduler, so the test watches the delivery history the
+	// scheduler records instead of the queue.
+	t.Run("WaitForDelivery", func(t *testing.T) {
+		history := func() ListNotificationsResponse {
+			httpReq, err := http.NewRequestWithContext(ctx, "GET",
+				calendarBaseURL+"/event/notifications?eventId="+eventID, nil)
+			require.NoError(t, err)
 
-		select {
-		case msg := <-msgs:
-			var status rmq.NotificationStatus
-			err := json.Unmarshal(msg, &status)
+			resp, err := client.Do(httpReq)
 			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
 
-			assert.Equal(t, "user_test_scheduler", status.UserID)
-			assert.Equal(t, "delivered", status.Status)
-			assert.NotZero(t, status.Timestamp)
-			t.Logf("Received notification status: %+v", status)
+			var list ListNotificationsResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+			return list
+		}
 
-		case <-time.After(60 * time.Second):
-			t.Fatal("Timeout waiting for status message in RabbitMQ queue")
+		deadline := time.Now().Add(60 * time.Second)
+		for {
+			list := history()
+			if len(list.Notifications) == 1 && list.Notifications[0].State == "delivered" {
+				notification := list.Notifications[0]
+				var delivered []NotificationStatusResponse
+				for _, status := range notification.Statuses {
+					if status.Status == "delivered" {
+						delivered = append(delivered, status)
+					}
+				}
+				require.NotEmpty(t, delivered)
+				assert.NotZero(t, delivered[0].Timestamp)
+				t.Logf("Notification delivered: %+v", notification)
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Timeout waiting for the notification to be delivered, history: %+v", list)
+			}
+			time.Sleep(time.Second)
 		}
 	})
 }